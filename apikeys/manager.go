@@ -4,6 +4,8 @@ import (
 	"log"
 	"sync"
 	"time"
+
+	"github.com/status-im/proxy-common/clock"
 )
 
 // IAPIKeyManager defines the interface for API key management
@@ -21,20 +23,38 @@ type APIKeyManager struct {
 	keyTypes    []KeyType            // ordered by priority
 	lastFailed  map[string]time.Time // Stores the time of the last failure for each key
 	backoffTime time.Duration        // Backoff duration before retrying a failed key
+	clock       clock.Clock
 	mu          sync.RWMutex
 }
 
+// Option is a functional option for configuring APIKeyManager
+type Option func(*APIKeyManager)
+
+// WithClock sets the clock used to track key backoff
+func WithClock(c clock.Clock) Option {
+	return func(m *APIKeyManager) {
+		m.clock = c
+	}
+}
+
 // NewAPIKeyManager creates a new API key manager
-func NewAPIKeyManager(provider KeyProvider, keyTypes []KeyType, backoff time.Duration) *APIKeyManager {
+func NewAPIKeyManager(provider KeyProvider, keyTypes []KeyType, backoff time.Duration, opts ...Option) *APIKeyManager {
 	if backoff == 0 {
 		backoff = 5 * time.Minute
 	}
-	return &APIKeyManager{
+	m := &APIKeyManager{
 		provider:    provider,
 		keyTypes:    keyTypes,
 		lastFailed:  make(map[string]time.Time),
 		backoffTime: backoff,
+		clock:       clock.Real{},
+	}
+
+	for _, opt := range opts {
+		opt(m)
 	}
+
+	return m
 }
 
 // isKeyInBackoff checks if a key is currently in backoff period (private implementation)
@@ -47,7 +67,7 @@ func (m *APIKeyManager) isKeyInBackoff(key string) bool {
 	defer m.mu.RUnlock()
 
 	if lastFailTime, exists := m.lastFailed[key]; exists {
-		return time.Since(lastFailTime) < m.backoffTime
+		return m.clock.Now().Sub(lastFailTime) < m.backoffTime
 	}
 
 	return false
@@ -85,6 +105,6 @@ func (m *APIKeyManager) MarkKeyAsFailed(key string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.lastFailed[key] = time.Now()
+	m.lastFailed[key] = m.clock.Now()
 	log.Printf("APIKeyManager: Marked key as failed for %v", m.backoffTime)
 }
@@ -3,6 +3,8 @@ package apikeys
 import (
 	"testing"
 	"time"
+
+	"github.com/status-im/proxy-common/clock"
 )
 
 // MockKeyProvider implements KeyProvider for testing
@@ -110,7 +112,8 @@ func TestAPIKeyManager_MarkKeyAsFailed(t *testing.T) {
 	provider.SetKeys(ProKey, []string{"pro1", "pro2", "pro3", "pro4"})
 
 	// Create API key manager with a shorter backoff for testing
-	manager := NewAPIKeyManager(provider, []KeyType{ProKey}, 100*time.Millisecond)
+	mock := clock.NewMock(time.Now())
+	manager := NewAPIKeyManager(provider, []KeyType{ProKey}, 100*time.Millisecond, WithClock(mock))
 
 	// Get initial available keys
 	initialKeys := manager.GetAvailableKeys()
@@ -138,8 +141,8 @@ func TestAPIKeyManager_MarkKeyAsFailed(t *testing.T) {
 		t.Errorf("Expected %d Pro keys after marking one as failed, got %d", initialProCount-1, afterFailProCount)
 	}
 
-	// Wait for backoff to expire
-	time.Sleep(150 * time.Millisecond)
+	// Let the backoff expire
+	mock.Advance(150 * time.Millisecond)
 
 	// Get available keys after backoff expired
 	afterBackoffKeys := manager.GetAvailableKeys()
@@ -9,6 +9,11 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
 	"github.com/status-im/proxy-common/auth/config"
 	"github.com/status-im/proxy-common/auth/jwt"
 	"github.com/status-im/proxy-common/auth/metrics"
@@ -18,6 +23,7 @@ import (
 type Handlers struct {
 	config     *config.Config
 	metrics    metrics.MetricsRecorder
+	tracer     trace.Tracer
 	tokenUsage map[string]int
 	tokenMutex sync.RWMutex
 }
@@ -30,10 +36,18 @@ func WithMetrics(m metrics.MetricsRecorder) Option {
 	}
 }
 
+// WithTracer sets the tracer for puzzle.solve and puzzle.verify spans
+func WithTracer(t trace.Tracer) Option {
+	return func(h *Handlers) {
+		h.tracer = t
+	}
+}
+
 func New(cfg *config.Config, opts ...Option) *Handlers {
 	h := &Handlers{
 		config:     cfg,
 		metrics:    metrics.NewNoopMetrics(),
+		tracer:     noop.NewTracerProvider().Tracer(""),
 		tokenUsage: make(map[string]int),
 	}
 
@@ -123,7 +137,14 @@ func (h *Handlers) SolveHandler(w http.ResponseWriter, r *http.Request) {
 		ArgonHash: req.ArgonHash,
 	}
 
-	if !puzzle.ValidateHMACProtectedSolution(puzzleObj, solution, h.config.Argon2Params, h.config.JWTSecret) {
+	_, span := h.tracer.Start(r.Context(), "puzzle.verify", trace.WithAttributes(
+		attribute.Int("puzzle.difficulty", puzzleObj.Difficulty),
+	))
+	valid := puzzle.ValidateHMACProtectedSolution(puzzleObj, solution, h.config.Argon2Params, h.config.JWTSecret)
+	span.SetAttributes(attribute.Bool("puzzle.valid", valid))
+	span.End()
+
+	if !valid {
 		h.metrics.RecordPuzzleAttempt("invalid_solution")
 		http.Error(w, "invalid solution or HMAC verification failed", 400)
 		return
@@ -161,7 +182,18 @@ func (h *Handlers) TestSolveHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	_, span := h.tracer.Start(r.Context(), "puzzle.solve", trace.WithAttributes(
+		attribute.Int("puzzle.difficulty", p.Difficulty),
+	))
 	solution, err := puzzle.Solve(p, h.config.Argon2Params)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetAttributes(attribute.Int64("puzzle.nonce", int64(solution.Nonce)))
+	}
+	span.End()
+
 	if err != nil {
 		http.Error(w, "failed to solve test puzzle", 500)
 		return
@@ -8,6 +8,10 @@ import (
 	"testing"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
 	"github.com/status-im/proxy-common/auth/config"
 	"github.com/status-im/proxy-common/auth/puzzle"
 )
@@ -461,3 +465,52 @@ func TestTestSolveHandler(t *testing.T) {
 		t.Error("expected example_request field in response")
 	}
 }
+
+func TestPuzzleTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	cfg := getTestConfig()
+	h := New(cfg, WithTracer(provider.Tracer("test")))
+
+	testReq := httptest.NewRequest(http.MethodGet, "/dev/test-solve", nil)
+	testW := httptest.NewRecorder()
+	h.TestSolveHandler(testW, testReq)
+
+	var testResp struct {
+		ExampleRequest SolveRequest `json:"example_request"`
+	}
+	if err := json.NewDecoder(testW.Body).Decode(&testResp); err != nil {
+		t.Fatalf("failed to decode test-solve response: %v", err)
+	}
+
+	body, _ := json.Marshal(testResp.ExampleRequest)
+	solveReq := httptest.NewRequest(http.MethodPost, "/auth/solve", bytes.NewReader(body))
+	solveW := httptest.NewRecorder()
+	h.SolveHandler(solveW, solveReq)
+
+	if solveW.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", solveW.Code, solveW.Body.String())
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name != "puzzle.solve" {
+		t.Errorf("expected puzzle.solve span, got %s", spans[0].Name)
+	}
+	if spans[1].Name != "puzzle.verify" {
+		t.Errorf("expected puzzle.verify span, got %s", spans[1].Name)
+	}
+
+	valid := false
+	for _, attr := range spans[1].Attributes {
+		if attr == attribute.Bool("puzzle.valid", true) {
+			valid = true
+		}
+	}
+	if !valid {
+		t.Errorf("expected puzzle.valid=true on verify span, got %v", spans[1].Attributes)
+	}
+}
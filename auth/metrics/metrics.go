@@ -33,6 +33,7 @@ type PrometheusMetrics struct {
 	tokenVerifications *prometheus.CounterVec
 }
 
+// NewPrometheusMetrics returns a recorder backed by the global metrics on the default registry
 func NewPrometheusMetrics() MetricsRecorder {
 	return &PrometheusMetrics{
 		tokensIssued:       TokensIssued,
@@ -42,6 +43,22 @@ func NewPrometheusMetrics() MetricsRecorder {
 	}
 }
 
+// NewPrometheusMetricsWithRegistry returns a recorder whose metrics are registered with reg.
+// The default registerer gets the global metrics, as with NewPrometheusMetrics.
+func NewPrometheusMetricsWithRegistry(reg prometheus.Registerer) MetricsRecorder {
+	if reg == nil || reg == prometheus.DefaultRegisterer {
+		return NewPrometheusMetrics()
+	}
+
+	factory := promauto.With(reg)
+	return &PrometheusMetrics{
+		tokensIssued:       factory.NewCounter(tokensIssuedOpts),
+		puzzlesSolved:      factory.NewCounter(puzzlesSolvedOpts),
+		puzzleAttempts:     factory.NewCounterVec(puzzleAttemptsOpts, []string{"status"}),
+		tokenVerifications: factory.NewCounterVec(tokenVerificationsOpts, []string{"status"}),
+	}
+}
+
 func (p *PrometheusMetrics) RecordPuzzleAttempt(status string) {
 	p.puzzleAttempts.WithLabelValues(status).Inc()
 }
@@ -58,31 +75,41 @@ func (p *PrometheusMetrics) IncrementPuzzlesSolved() {
 	p.puzzlesSolved.Inc()
 }
 
-// Legacy global metrics for backward compatibility
 var (
-	// TokensIssued tracks the total number of JWT tokens issued
-	TokensIssued = promauto.NewCounter(prometheus.CounterOpts{
+	tokensIssuedOpts = prometheus.CounterOpts{
 		Name: "auth_tokens_issued_total",
 		Help: "The total number of JWT tokens issued",
-	})
+	}
 
-	// PuzzlesSolved tracks the total number of puzzles solved successfully
-	PuzzlesSolved = promauto.NewCounter(prometheus.CounterOpts{
+	puzzlesSolvedOpts = prometheus.CounterOpts{
 		Name: "auth_puzzles_solved_total",
 		Help: "The total number of puzzles solved successfully",
-	})
+	}
 
-	// PuzzleAttempts tracks puzzle solution attempts (including failed ones)
-	PuzzleAttempts = promauto.NewCounterVec(prometheus.CounterOpts{
+	puzzleAttemptsOpts = prometheus.CounterOpts{
 		Name: "auth_puzzle_attempts_total",
 		Help: "The total number of puzzle solution attempts",
-	}, []string{"status"}) // status: "success", "failed", "invalid_hmac", "expired"
+	}
 
-	// TokenVerifications tracks JWT token verification attempts
-	TokenVerifications = promauto.NewCounterVec(prometheus.CounterOpts{
+	tokenVerificationsOpts = prometheus.CounterOpts{
 		Name: "auth_token_verifications_total",
 		Help: "The total number of token verification attempts",
-	}, []string{"status"}) // status: "success", "failed", "expired", "rate_limited"
+	}
+)
+
+// Legacy global metrics for backward compatibility
+var (
+	// TokensIssued tracks the total number of JWT tokens issued
+	TokensIssued = promauto.NewCounter(tokensIssuedOpts)
+
+	// PuzzlesSolved tracks the total number of puzzles solved successfully
+	PuzzlesSolved = promauto.NewCounter(puzzlesSolvedOpts)
+
+	// PuzzleAttempts tracks puzzle solution attempts (including failed ones)
+	PuzzleAttempts = promauto.NewCounterVec(puzzleAttemptsOpts, []string{"status"}) // status: "success", "failed", "invalid_hmac", "expired"
+
+	// TokenVerifications tracks JWT token verification attempts
+	TokenVerifications = promauto.NewCounterVec(tokenVerificationsOpts, []string{"status"}) // status: "success", "failed", "expired", "rate_limited"
 )
 
 // Legacy functions for backward compatibility
@@ -1,10 +1,14 @@
 package metrics
 
 import (
+	"context"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 func TestNoopMetrics(t *testing.T) {
@@ -149,3 +153,88 @@ func TestNoopMetricsInterface(t *testing.T) {
 	m.IncrementTokensIssued()
 	m.IncrementPuzzlesSolved()
 }
+
+func TestOTelMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	m, err := NewOTelMetrics(provider.Meter("test"))
+	if err != nil {
+		t.Fatalf("failed to create OTel metrics: %v", err)
+	}
+
+	m.IncrementTokensIssued()
+	m.IncrementPuzzlesSolved()
+	m.IncrementPuzzlesSolved()
+	m.RecordPuzzleAttempt("success")
+	m.RecordPuzzleAttempt("invalid_solution")
+	m.RecordPuzzleAttempt("invalid_solution")
+	m.RecordTokenVerification("rate_limited")
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+
+	values := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, metric := range sm.Metrics {
+			sum, ok := metric.Data.(metricdata.Sum[int64])
+			if !ok {
+				t.Fatalf("expected %s to be an int64 sum, got %T", metric.Name, metric.Data)
+			}
+			for _, dp := range sum.DataPoints {
+				key := metric.Name
+				if status, ok := dp.Attributes.Value(attribute.Key("status")); ok {
+					key += "/" + status.AsString()
+				}
+				values[key] = dp.Value
+			}
+		}
+	}
+
+	expected := map[string]int64{
+		"auth.tokens_issued":                    1,
+		"auth.puzzles_solved":                   2,
+		"auth.puzzle_attempts/success":          1,
+		"auth.puzzle_attempts/invalid_solution": 2,
+		"auth.token_verifications/rate_limited": 1,
+	}
+	for key, want := range expected {
+		if values[key] != want {
+			t.Errorf("expected %s to be %d, got %d", key, want, values[key])
+		}
+	}
+}
+
+func TestNewPrometheusMetricsWithRegistry(t *testing.T) {
+	reg1 := prometheus.NewRegistry()
+	reg2 := prometheus.NewRegistry()
+
+	m1 := NewPrometheusMetricsWithRegistry(reg1)
+	m2 := NewPrometheusMetricsWithRegistry(reg2)
+
+	m1.IncrementTokensIssued()
+	m2.RecordPuzzleAttempt("success")
+	m2.RecordPuzzleAttempt("success")
+
+	if count := testutil.ToFloat64(m1.(*PrometheusMetrics).tokensIssued); count != 1 {
+		t.Errorf("expected tokens issued count 1, got %f", count)
+	}
+	if count := testutil.ToFloat64(m2.(*PrometheusMetrics).tokensIssued); count != 0 {
+		t.Errorf("expected separate registry to have tokens issued count 0, got %f", count)
+	}
+
+	n, err := testutil.GatherAndCount(reg2, "auth_puzzle_attempts_total")
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 puzzle attempts series, got %d", n)
+	}
+
+	if m := NewPrometheusMetricsWithRegistry(prometheus.DefaultRegisterer); m.(*PrometheusMetrics).tokensIssued != TokensIssued {
+		t.Error("expected the default registerer to reuse the global metrics")
+	}
+}
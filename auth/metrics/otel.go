@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// OTelMetrics records auth metrics through an OpenTelemetry Meter, using the
+// Prometheus metric names with dots and without the _total suffix
+type OTelMetrics struct {
+	tokensIssued       metric.Int64Counter
+	puzzlesSolved      metric.Int64Counter
+	puzzleAttempts     metric.Int64Counter
+	tokenVerifications metric.Int64Counter
+}
+
+// NewOTelMetrics creates the auth instruments on meter
+func NewOTelMetrics(meter metric.Meter) (MetricsRecorder, error) {
+	var errs []error
+	counter := func(name, description string) metric.Int64Counter {
+		c, err := meter.Int64Counter(name, metric.WithDescription(description))
+		errs = append(errs, err)
+		return c
+	}
+
+	m := &OTelMetrics{
+		tokensIssued:       counter("auth.tokens_issued", "The total number of JWT tokens issued"),
+		puzzlesSolved:      counter("auth.puzzles_solved", "The total number of puzzles solved successfully"),
+		puzzleAttempts:     counter("auth.puzzle_attempts", "The total number of puzzle solution attempts"),
+		tokenVerifications: counter("auth.token_verifications", "The total number of token verification attempts"),
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (o *OTelMetrics) RecordPuzzleAttempt(status string) {
+	o.puzzleAttempts.Add(context.Background(), 1, metric.WithAttributes(attribute.String("status", status)))
+}
+
+func (o *OTelMetrics) RecordTokenVerification(status string) {
+	o.tokenVerifications.Add(context.Background(), 1, metric.WithAttributes(attribute.String("status", status)))
+}
+
+func (o *OTelMetrics) IncrementTokensIssued() {
+	o.tokensIssued.Add(context.Background(), 1)
+}
+
+func (o *OTelMetrics) IncrementPuzzlesSolved() {
+	o.puzzlesSolved.Add(context.Background(), 1)
+}
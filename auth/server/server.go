@@ -5,7 +5,10 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/status-im/proxy-common/auth/config"
 	"github.com/status-im/proxy-common/auth/handlers"
 	"github.com/status-im/proxy-common/auth/metrics"
@@ -14,6 +17,9 @@ import (
 type Server struct {
 	config         *config.Config
 	handlers       *handlers.Handlers
+	metrics        metrics.MetricsRecorder
+	registerer     prometheus.Registerer
+	tracer         trace.Tracer
 	mux            *http.ServeMux
 	enableMetrics  bool
 	metricsPath    string
@@ -40,6 +46,15 @@ func WithMetricsPath(path string) Option {
 	}
 }
 
+// WithRegisterer registers the auth metrics with reg instead of the default registry.
+// The metrics endpoint serves reg when it is also a prometheus.Gatherer, such as a
+// *prometheus.Registry, and the default registry otherwise.
+func WithRegisterer(reg prometheus.Registerer) Option {
+	return func(s *Server) {
+		s.registerer = reg
+	}
+}
+
 func WithTestMode(enable bool) Option {
 	return func(s *Server) {
 		s.enableTestMode = enable
@@ -48,9 +63,14 @@ func WithTestMode(enable bool) Option {
 
 func WithCustomMetrics(m metrics.MetricsRecorder) Option {
 	return func(s *Server) {
-		if s.handlers == nil {
-			s.handlers = handlers.New(s.config, handlers.WithMetrics(m))
-		}
+		s.metrics = m
+	}
+}
+
+// WithTracer sets the tracer for puzzle solve and verify spans
+func WithTracer(t trace.Tracer) Option {
+	return func(s *Server) {
+		s.tracer = t
 	}
 }
 
@@ -58,6 +78,7 @@ func New(opts ...Option) (*Server, error) {
 	s := &Server{
 		enableMetrics:  true,
 		metricsPath:    "/metrics",
+		registerer:     prometheus.DefaultRegisterer,
 		enableTestMode: false,
 	}
 
@@ -77,16 +98,20 @@ func New(opts ...Option) (*Server, error) {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
-	if s.handlers == nil {
-		var metricsRecorder metrics.MetricsRecorder
+	if s.metrics == nil {
 		if s.enableMetrics {
-			metricsRecorder = metrics.NewPrometheusMetrics()
+			s.metrics = metrics.NewPrometheusMetricsWithRegistry(s.registerer)
 		} else {
-			metricsRecorder = metrics.NewNoopMetrics()
+			s.metrics = metrics.NewNoopMetrics()
 		}
-		s.handlers = handlers.New(s.config, handlers.WithMetrics(metricsRecorder))
 	}
 
+	handlerOpts := []handlers.Option{handlers.WithMetrics(s.metrics)}
+	if s.tracer != nil {
+		handlerOpts = append(handlerOpts, handlers.WithTracer(s.tracer))
+	}
+	s.handlers = handlers.New(s.config, handlerOpts...)
+
 	s.setupRoutes()
 
 	return s, nil
@@ -105,10 +130,20 @@ func (s *Server) setupRoutes() {
 	}
 
 	if s.enableMetrics {
-		s.mux.Handle(s.metricsPath, promhttp.Handler())
+		s.mux.Handle(s.metricsPath, s.metricsHandler())
 	}
 }
 
+// metricsHandler serves the registry the auth metrics are registered with
+func (s *Server) metricsHandler() http.Handler {
+	gatherer, ok := s.registerer.(prometheus.Gatherer)
+	if !ok || s.registerer == prometheus.DefaultRegisterer {
+		return promhttp.Handler()
+	}
+
+	return promhttp.InstrumentMetricHandler(s.registerer, promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}))
+}
+
 func (s *Server) ListenAndServe(addr string) error {
 	log.Printf("[go-auth-service] starting on %s", addr)
 	log.Printf("[go-auth-service] algorithm: %s, memory: %dKB, time: %d, token expiry: %d minutes",
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/status-im/proxy-common/auth/config"
+)
+
+func TestServer_MetricsRegistry(t *testing.T) {
+	cfg := config.New(config.WithJWTSecret("test-secret"))
+
+	newServer := func(reg *prometheus.Registry) *Server {
+		srv, err := New(WithConfig(cfg), WithRegisterer(reg))
+		if err != nil {
+			t.Fatalf("failed to create server: %v", err)
+		}
+		return srv
+	}
+
+	// Two servers on separate registries must not clash on registration
+	reg := prometheus.NewRegistry()
+	srv := newServer(reg)
+	newServer(prometheus.NewRegistry())
+
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("failed to get metrics: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if !strings.Contains(string(body), "auth_tokens_issued_total 0") {
+		t.Errorf("expected registry metrics in response, got:\n%s", body)
+	}
+	if strings.Contains(string(body), "go_goroutines") {
+		t.Error("expected default registry metrics to be absent")
+	}
+}
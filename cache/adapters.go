@@ -0,0 +1,244 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/status-im/proxy-common/models"
+)
+
+// Ensure adapters implement their target interfaces
+var _ CacheV2 = (*cacheV2Adapter)(nil)
+var _ NegativeCacheV2 = (*cacheV2Adapter)(nil)
+var _ LevelAwareCacheV2 = (*levelAwareCacheV2Adapter)(nil)
+var _ Cache = (*cacheAdapter)(nil)
+var _ NegativeCache = (*cacheAdapter)(nil)
+var _ LevelAwareCache = (*levelAwareCacheAdapter)(nil)
+
+// NewCacheV2Adapter exposes a Cache through the CacheV2 interface.
+// Since Cache swallows errors, the adapter only reports ErrCacheMiss and context errors.
+// SetNegative deletes the key if c does not implement NegativeCache.
+func NewCacheV2Adapter(c Cache) CacheV2 {
+	return &cacheV2Adapter{cache: c}
+}
+
+// NewLevelAwareCacheV2Adapter exposes a LevelAwareCache through the LevelAwareCacheV2 interface
+func NewLevelAwareCacheV2Adapter(c LevelAwareCache) LevelAwareCacheV2 {
+	return &levelAwareCacheV2Adapter{cacheV2Adapter: cacheV2Adapter{cache: c}, levelAware: c}
+}
+
+// NewCacheAdapter exposes a CacheV2 through the Cache interface.
+// Operations run with context.Background() and errors are discarded.
+// SetNegative deletes the key if c does not implement NegativeCacheV2.
+func NewCacheAdapter(c CacheV2) Cache {
+	return &cacheAdapter{cache: c}
+}
+
+// NewLevelAwareCacheAdapter exposes a LevelAwareCacheV2 through the LevelAwareCache interface
+func NewLevelAwareCacheAdapter(c LevelAwareCacheV2) LevelAwareCache {
+	return &levelAwareCacheAdapter{cacheAdapter: cacheAdapter{cache: c}, levelAware: c}
+}
+
+type cacheV2Adapter struct {
+	cache Cache
+}
+
+func (a *cacheV2Adapter) Get(ctx context.Context, key string) (*models.CacheEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	entry, found := a.cache.Get(key)
+	if !found {
+		return nil, ErrCacheMiss
+	}
+	return entry, nil
+}
+
+func (a *cacheV2Adapter) GetStale(ctx context.Context, key string) (*models.CacheEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	entry, found := a.cache.GetStale(key)
+	if !found {
+		return nil, ErrCacheMiss
+	}
+	return entry, nil
+}
+
+func (a *cacheV2Adapter) GetEntry(ctx context.Context, key string) (*models.CacheEntry, error) {
+	nc, ok := a.cache.(NegativeCache)
+	if !ok {
+		return a.Get(ctx, key)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	entry, found := nc.GetEntry(key)
+	if !found {
+		return nil, ErrCacheMiss
+	}
+	return entry, nil
+}
+
+func (a *cacheV2Adapter) GetStaleEntry(ctx context.Context, key string) (*models.CacheEntry, error) {
+	nc, ok := a.cache.(NegativeCache)
+	if !ok {
+		return a.GetStale(ctx, key)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	entry, found := nc.GetStaleEntry(key)
+	if !found {
+		return nil, ErrCacheMiss
+	}
+	return entry, nil
+}
+
+func (a *cacheV2Adapter) Set(ctx context.Context, key string, val []byte, ttl models.TTL) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	a.cache.Set(key, val, ttl)
+	return nil
+}
+
+func (a *cacheV2Adapter) SetNegative(ctx context.Context, key string, errPayload []byte, ttl models.TTL) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if nc, ok := a.cache.(NegativeCache); ok {
+		nc.SetNegative(key, errPayload, ttl)
+	} else {
+		a.cache.Delete(key)
+	}
+	return nil
+}
+
+func (a *cacheV2Adapter) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	a.cache.Delete(key)
+	return nil
+}
+
+type levelAwareCacheV2Adapter struct {
+	cacheV2Adapter
+	levelAware LevelAwareCache
+}
+
+func (a *levelAwareCacheV2Adapter) GetWithLevel(ctx context.Context, key string) (*models.CacheResult, error) {
+	if err := ctx.Err(); err != nil {
+		return missResult(), err
+	}
+	return resultOrMiss(a.levelAware.GetWithLevel(key))
+}
+
+func (a *levelAwareCacheV2Adapter) GetStaleWithLevel(ctx context.Context, key string) (*models.CacheResult, error) {
+	if err := ctx.Err(); err != nil {
+		return missResult(), err
+	}
+	return resultOrMiss(a.levelAware.GetStaleWithLevel(key))
+}
+
+type cacheAdapter struct {
+	cache CacheV2
+}
+
+func (a *cacheAdapter) Get(key string) (*models.CacheEntry, bool) {
+	entry, err := a.cache.Get(context.Background(), key)
+	if err != nil {
+		return nil, false
+	}
+	return entry, true
+}
+
+func (a *cacheAdapter) GetStale(key string) (*models.CacheEntry, bool) {
+	entry, err := a.cache.GetStale(context.Background(), key)
+	if err != nil {
+		return nil, false
+	}
+	return entry, true
+}
+
+func (a *cacheAdapter) GetEntry(key string) (*models.CacheEntry, bool) {
+	nc, ok := a.cache.(NegativeCacheV2)
+	if !ok {
+		return a.Get(key)
+	}
+	entry, err := nc.GetEntry(context.Background(), key)
+	if err != nil {
+		return nil, false
+	}
+	return entry, true
+}
+
+func (a *cacheAdapter) GetStaleEntry(key string) (*models.CacheEntry, bool) {
+	nc, ok := a.cache.(NegativeCacheV2)
+	if !ok {
+		return a.GetStale(key)
+	}
+	entry, err := nc.GetStaleEntry(context.Background(), key)
+	if err != nil {
+		return nil, false
+	}
+	return entry, true
+}
+
+func (a *cacheAdapter) Set(key string, val []byte, ttl models.TTL) {
+	_ = a.cache.Set(context.Background(), key, val, ttl)
+}
+
+func (a *cacheAdapter) SetNegative(key string, errPayload []byte, ttl models.TTL) {
+	if nc, ok := a.cache.(NegativeCacheV2); ok {
+		_ = nc.SetNegative(context.Background(), key, errPayload, ttl)
+	} else {
+		_ = a.cache.Delete(context.Background(), key)
+	}
+}
+
+func (a *cacheAdapter) Delete(key string) {
+	_ = a.cache.Delete(context.Background(), key)
+}
+
+type levelAwareCacheAdapter struct {
+	cacheAdapter
+	levelAware LevelAwareCacheV2
+}
+
+func (a *levelAwareCacheAdapter) GetWithLevel(key string) *models.CacheResult {
+	return resultOrNegative(a.levelAware.GetWithLevel(context.Background(), key))
+}
+
+func (a *levelAwareCacheAdapter) GetStaleWithLevel(key string) *models.CacheResult {
+	return resultOrNegative(a.levelAware.GetStaleWithLevel(context.Background(), key))
+}
+
+// missResult returns a CacheResult describing a miss
+func missResult() *models.CacheResult {
+	return &models.CacheResult{
+		Entry: nil,
+		Found: false,
+		Level: models.CacheLevelMiss,
+	}
+}
+
+// resultOrMiss converts a LevelAwareCache result to the LevelAwareCacheV2 convention
+func resultOrMiss(result *models.CacheResult) (*models.CacheResult, error) {
+	if result != nil && result.IsNegative() {
+		return result, ErrCacheMiss
+	}
+	if result == nil || !result.Found {
+		return missResult(), ErrCacheMiss
+	}
+	return result, nil
+}
+
+// resultOrNegative converts a LevelAwareCacheV2 result to the LevelAwareCache
+// convention, keeping negative entries returned with ErrCacheMiss
+func resultOrNegative(result *models.CacheResult, err error) *models.CacheResult {
+	if result != nil && (err == nil || result.IsNegative()) {
+		return result
+	}
+	return missResult()
+}
@@ -0,0 +1,179 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/cache/mock"
+	"github.com/status-im/proxy-common/models"
+)
+
+func TestCacheV2Adapter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mock.NewMockCache(ctrl)
+	adapter := cache.NewCacheV2Adapter(mockCache)
+	entry := &models.CacheEntry{Data: []byte("test-value")}
+	ttl := models.TTL{Fresh: time.Minute}
+
+	t.Run("hit returns entry", func(t *testing.T) {
+		mockCache.EXPECT().Get("test-key").Return(entry, true)
+
+		got, err := adapter.Get(context.Background(), "test-key")
+
+		assert.NoError(t, err)
+		assert.Equal(t, entry, got)
+	})
+
+	t.Run("miss returns cache.ErrCacheMiss", func(t *testing.T) {
+		mockCache.EXPECT().GetStale("test-key").Return(nil, false)
+
+		got, err := adapter.GetStale(context.Background(), "test-key")
+
+		assert.ErrorIs(t, err, cache.ErrCacheMiss)
+		assert.Nil(t, got)
+	})
+
+	t.Run("set and delete pass through", func(t *testing.T) {
+		mockCache.EXPECT().Set("test-key", entry.Data, ttl)
+		mockCache.EXPECT().Delete("test-key")
+
+		assert.NoError(t, adapter.Set(context.Background(), "test-key", entry.Data, ttl))
+		assert.NoError(t, adapter.Delete(context.Background(), "test-key"))
+	})
+
+	t.Run("cancelled context skips the cache", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := adapter.Get(ctx, "test-key")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorIs(t, adapter.Set(ctx, "test-key", entry.Data, ttl), context.Canceled)
+	})
+}
+
+func TestCacheV2Adapter_SetNegative(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ttl := models.TTL{Fresh: time.Second}
+
+	t.Run("forwards to a negative cache", func(t *testing.T) {
+		mockCache := mock.NewMockNegativeCache(ctrl)
+		mockCache.EXPECT().SetNegative("test-key", []byte("not found"), ttl)
+
+		adapter := cache.NewCacheV2Adapter(mockCache).(cache.NegativeCacheV2)
+		assert.NoError(t, adapter.SetNegative(context.Background(), "test-key", []byte("not found"), ttl))
+	})
+
+	t.Run("deletes from other caches", func(t *testing.T) {
+		mockCache := mock.NewMockCache(ctrl)
+		mockCache.EXPECT().Delete("test-key")
+
+		adapter := cache.NewCacheV2Adapter(mockCache).(cache.NegativeCacheV2)
+		assert.NoError(t, adapter.SetNegative(context.Background(), "test-key", nil, ttl))
+	})
+}
+
+func TestCacheAdapter_SetNegative(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ttl := models.TTL{Fresh: time.Second}
+
+	t.Run("forwards to a negative cache", func(t *testing.T) {
+		mockCache := mock.NewMockNegativeCacheV2(ctrl)
+		mockCache.EXPECT().SetNegative(gomock.Any(), "test-key", []byte("not found"), ttl).Return(nil)
+
+		cache.NewCacheAdapter(mockCache).(cache.NegativeCache).SetNegative("test-key", []byte("not found"), ttl)
+	})
+
+	t.Run("deletes from other caches", func(t *testing.T) {
+		mockCache := mock.NewMockCacheV2(ctrl)
+		mockCache.EXPECT().Delete(gomock.Any(), "test-key").Return(nil)
+
+		cache.NewCacheAdapter(mockCache).(cache.NegativeCache).SetNegative("test-key", nil, ttl)
+	})
+}
+
+func TestLevelAwareCacheV2Adapter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mock.NewMockLevelAwareCache(ctrl)
+	adapter := cache.NewLevelAwareCacheV2Adapter(mockCache)
+
+	hit := &models.CacheResult{Entry: &models.CacheEntry{Data: []byte("v")}, Found: true, Level: models.CacheLevelL2}
+	mockCache.EXPECT().GetWithLevel("hit").Return(hit)
+	mockCache.EXPECT().GetStaleWithLevel("miss").Return(&models.CacheResult{Level: models.CacheLevelMiss})
+
+	result, err := adapter.GetWithLevel(context.Background(), "hit")
+	assert.NoError(t, err)
+	assert.Equal(t, hit, result)
+
+	result, err = adapter.GetStaleWithLevel(context.Background(), "miss")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	assert.False(t, result.Found)
+	assert.Equal(t, models.CacheLevelMiss, result.Level)
+}
+
+func TestCacheAdapter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mock.NewMockCacheV2(ctrl)
+	adapter := cache.NewCacheAdapter(mockCache)
+	entry := &models.CacheEntry{Data: []byte("test-value")}
+	ttl := models.TTL{Fresh: time.Minute}
+
+	t.Run("hit returns entry", func(t *testing.T) {
+		mockCache.EXPECT().Get(gomock.Any(), "test-key").Return(entry, nil)
+
+		got, found := adapter.Get("test-key")
+
+		assert.True(t, found)
+		assert.Equal(t, entry, got)
+	})
+
+	t.Run("errors are reported as misses", func(t *testing.T) {
+		mockCache.EXPECT().GetStale(gomock.Any(), "test-key").Return(nil, errors.New("connection refused"))
+
+		got, found := adapter.GetStale("test-key")
+
+		assert.False(t, found)
+		assert.Nil(t, got)
+	})
+
+	t.Run("set and delete errors are discarded", func(t *testing.T) {
+		mockCache.EXPECT().Set(gomock.Any(), "test-key", entry.Data, ttl).Return(errors.New("timeout"))
+		mockCache.EXPECT().Delete(gomock.Any(), "test-key").Return(errors.New("timeout"))
+
+		adapter.Set("test-key", entry.Data, ttl)
+		adapter.Delete("test-key")
+	})
+}
+
+func TestLevelAwareCacheAdapter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mock.NewMockLevelAwareCacheV2(ctrl)
+	adapter := cache.NewLevelAwareCacheAdapter(mockCache)
+
+	hit := &models.CacheResult{Entry: &models.CacheEntry{Data: []byte("v")}, Found: true, Level: models.CacheLevelL1}
+	mockCache.EXPECT().GetWithLevel(gomock.Any(), "hit").Return(hit, nil)
+	mockCache.EXPECT().GetStaleWithLevel(gomock.Any(), "down").Return(nil, errors.New("connection refused"))
+
+	assert.Equal(t, hit, adapter.GetWithLevel("hit"))
+
+	result := adapter.GetStaleWithLevel("down")
+	assert.False(t, result.Found)
+	assert.Equal(t, models.CacheLevelMiss, result.Level)
+}
@@ -0,0 +1,306 @@
+// Package admin provides an HTTP handler for inspecting and purging cache levels
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/clock"
+	"github.com/status-im/proxy-common/models"
+)
+
+// ErrForbidden is returned by authorizers that reject a request
+var ErrForbidden = errors.New("forbidden")
+
+// Authorizer decides whether a request may use the admin endpoints. A non-nil error
+// rejects the request with 403 Forbidden.
+type Authorizer interface {
+	Authorize(r *http.Request) error
+}
+
+// AuthorizerFunc adapts a function to the Authorizer interface
+type AuthorizerFunc func(r *http.Request) error
+
+// Authorize calls f(r)
+func (f AuthorizerFunc) Authorize(r *http.Request) error {
+	return f(r)
+}
+
+// DenyAll rejects every request. It is the default until an authorizer is configured.
+var DenyAll = AuthorizerFunc(func(*http.Request) error { return ErrForbidden })
+
+// BearerToken accepts requests carrying "Authorization: Bearer <token>"
+func BearerToken(token string) Authorizer {
+	return AuthorizerFunc(func(r *http.Request) error {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			return ErrForbidden
+		}
+		return nil
+	})
+}
+
+// Handler serves the cache admin endpoints, relative to where it is mounted:
+//
+//	GET    /keys/{key}        look the key up in every level
+//	DELETE /keys/{key}        delete the key from every level
+//	DELETE /prefixes/{prefix} delete every key starting with prefix
+//	GET    /stats             dump the stats of every level
+//
+// Levels are the caches behind a MultiCache, in the same order, so they are reported
+// as L1, L2 and so on. Lookups and stats read the levels directly; deletes go through
+// the MultiCache set with WithMultiCache, if any, so they reach other replicas.
+type Handler struct {
+	levels     []cache.Cache
+	multi      cache.TaggedCache
+	authorizer Authorizer
+	logger     cache.Logger
+	clock      clock.Clock
+	mux        *http.ServeMux
+}
+
+// Option is a functional option for configuring Handler
+type Option func(*Handler)
+
+// WithAuthorizer sets the authorizer every request is checked against
+func WithAuthorizer(a Authorizer) Option {
+	return func(h *Handler) {
+		h.authorizer = a
+	}
+}
+
+// WithMultiCache routes key and prefix deletes through mc, the MultiCache over the
+// levels, instead of deleting from each level. Its invalidation bus, if configured,
+// then clears the other replicas' L1 as well.
+func WithMultiCache(mc cache.TaggedCache) Option {
+	return func(h *Handler) {
+		h.multi = mc
+	}
+}
+
+// WithLogger sets the logger deletions are reported to
+func WithLogger(logger cache.Logger) Option {
+	return func(h *Handler) {
+		h.logger = logger
+	}
+}
+
+// WithClock sets the clock used to compute entry age and remaining TTL
+func WithClock(c clock.Clock) Option {
+	return func(h *Handler) {
+		h.clock = c
+	}
+}
+
+// New creates an admin handler for levels. Without WithAuthorizer every request is
+// rejected.
+func New(levels []cache.Cache, opts ...Option) *Handler {
+	h := &Handler{
+		levels:     levels,
+		authorizer: DenyAll,
+		logger:     cache.NoopLogger{},
+		clock:      clock.Real{},
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if h.multi == nil {
+		h.logger.Warn("Admin handler deletes from the levels directly; other replicas keep their L1 entries")
+	}
+
+	h.mux = http.NewServeMux()
+	h.mux.HandleFunc("GET /keys/{key...}", h.lookupKey)
+	h.mux.HandleFunc("DELETE /keys/{key...}", h.deleteKey)
+	h.mux.HandleFunc("DELETE /prefixes/{prefix...}", h.deletePrefix)
+	h.mux.HandleFunc("GET /stats", h.stats)
+
+	return h
+}
+
+// ServeHTTP authorizes the request and dispatches it to the matching endpoint
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h.authorizer.Authorize(r); err != nil {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// LevelEntry describes a key at one level
+type LevelEntry struct {
+	Level          string    `json:"level"`
+	Found          bool      `json:"found"`
+	Kind           string    `json:"kind,omitempty"`
+	Size           int       `json:"size,omitempty"`
+	CreatedAt      time.Time `json:"created_at,omitzero"`
+	Age            string    `json:"age,omitempty"`
+	Fresh          bool      `json:"fresh,omitempty"`
+	FreshRemaining string    `json:"fresh_remaining,omitempty"`
+	StaleRemaining string    `json:"stale_remaining,omitempty"`
+}
+
+// KeyLookup is the response of GET /keys/{key}
+type KeyLookup struct {
+	Key    string       `json:"key"`
+	Levels []LevelEntry `json:"levels"`
+}
+
+func (h *Handler) lookupKey(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if key == "" {
+		http.Error(w, "key must not be empty", http.StatusBadRequest)
+		return
+	}
+
+	now := h.clock.Now()
+
+	resp := KeyLookup{Key: key, Levels: make([]LevelEntry, 0, len(h.levels))}
+	found := false
+	for i, c := range h.levels {
+		le := LevelEntry{Level: levelName(i)}
+		if entry, ok := getStaleEntry(c, key); ok && entry != nil {
+			found = true
+			remaining := entry.RemainingTTLAt(now)
+			le.Found = true
+			le.Kind = entryKind(entry)
+			le.Size = len(entry.Data)
+			le.CreatedAt = entry.CreatedTime().UTC()
+			le.Age = now.Sub(entry.CreatedTime()).String()
+			le.Fresh = entry.IsFreshAt(now)
+			le.FreshRemaining = remaining.Fresh.String()
+			le.StaleRemaining = remaining.Stale.String()
+		}
+		resp.Levels = append(resp.Levels, le)
+	}
+
+	status := http.StatusOK
+	if !found {
+		status = http.StatusNotFound
+	}
+	h.writeJSON(w, status, resp)
+}
+
+// KeyDeletion is the response of DELETE /keys/{key}
+type KeyDeletion struct {
+	Key string `json:"key"`
+}
+
+func (h *Handler) deleteKey(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if key == "" {
+		http.Error(w, "key must not be empty", http.StatusBadRequest)
+		return
+	}
+	if h.multi != nil {
+		h.multi.Delete(key)
+	} else {
+		for _, c := range h.levels {
+			c.Delete(key)
+		}
+	}
+
+	h.logger.Info("Deleted cache key via admin handler", "key", key, "remote_addr", r.RemoteAddr)
+	h.writeJSON(w, http.StatusOK, KeyDeletion{Key: key})
+}
+
+// PrefixDeletion is the response of DELETE /prefixes/{prefix}. Removed counts the keys
+// removed per level, or under "multi" when deletes go through the MultiCache. Levels
+// that do not implement cache.TaggedCache are listed as unsupported.
+type PrefixDeletion struct {
+	Prefix      string         `json:"prefix"`
+	Removed     map[string]int `json:"removed"`
+	Unsupported []string       `json:"unsupported,omitempty"`
+}
+
+func (h *Handler) deletePrefix(w http.ResponseWriter, r *http.Request) {
+	prefix := r.PathValue("prefix")
+	if prefix == "" {
+		http.Error(w, "prefix must not be empty", http.StatusBadRequest)
+		return
+	}
+
+	resp := PrefixDeletion{Prefix: prefix, Removed: make(map[string]int, len(h.levels))}
+	if h.multi != nil {
+		resp.Removed["multi"] = len(h.multi.InvalidatePrefix(prefix))
+	}
+	for i, c := range h.levels {
+		tc, ok := c.(cache.TaggedCache)
+		if !ok {
+			resp.Unsupported = append(resp.Unsupported, levelName(i))
+			continue
+		}
+		if h.multi == nil {
+			resp.Removed[levelName(i)] = len(tc.InvalidatePrefix(prefix))
+		}
+	}
+
+	h.logger.Info("Deleted cache prefix via admin handler", "prefix", prefix, "removed", resp.Removed,
+		"remote_addr", r.RemoteAddr)
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+// LevelStats is the stats of one level. Available is reported for levels implementing
+// cache.HealthChecker.
+type LevelStats struct {
+	Level     string `json:"level"`
+	Available *bool  `json:"available,omitempty"`
+	cache.LevelStats
+}
+
+// Stats is the response of GET /stats
+type Stats struct {
+	Levels []LevelStats `json:"levels"`
+}
+
+func (h *Handler) stats(w http.ResponseWriter, r *http.Request) {
+	resp := Stats{Levels: make([]LevelStats, 0, len(h.levels))}
+	for i, c := range h.levels {
+		ls := LevelStats{Level: levelName(i)}
+		if sr, ok := c.(cache.StatsReporter); ok {
+			ls.LevelStats = sr.Stats()
+		}
+		if hc, ok := c.(cache.HealthChecker); ok {
+			available := hc.Available()
+			ls.Available = &available
+		}
+		resp.Levels = append(resp.Levels, ls)
+	}
+
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Warn("Failed to write admin response", "error", err)
+	}
+}
+
+// levelName returns the name of the level at index i, e.g. "L1"
+func levelName(i int) string {
+	return models.CacheLevelFromIndex(i).String()
+}
+
+// getStaleEntry reads key from c including negative entries, if c can return them
+func getStaleEntry(c cache.Cache, key string) (*models.CacheEntry, bool) {
+	if nc, ok := c.(cache.NegativeCache); ok {
+		return nc.GetStaleEntry(key)
+	}
+	return c.GetStale(key)
+}
+
+// entryKind returns "negative" for negative entries and "positive" otherwise
+func entryKind(entry *models.CacheEntry) string {
+	if entry.IsNegative() {
+		return "negative"
+	}
+	return "positive"
+}
@@ -0,0 +1,228 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/cache/invalidation"
+	"github.com/status-im/proxy-common/cache/l1"
+	"github.com/status-im/proxy-common/cache/mock"
+	"github.com/status-im/proxy-common/cache/multi"
+	"github.com/status-im/proxy-common/clock"
+	"github.com/status-im/proxy-common/models"
+)
+
+const testToken = "secret"
+
+// newTestHandler returns a handler over a BigCache L1 and a mock L2 that does not
+// support prefix invalidation
+func newTestHandler(t *testing.T, clk clock.Clock) (*Handler, cache.Cache, *mock.MockCache) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	l1Cache, err := l1.NewBigCache(&cache.BigCacheConfig{Enabled: true, Size: 10}, l1.WithClock(clk))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l1Cache.(*l1.BigCache).Close() })
+	l2Cache := mock.NewMockCache(ctrl)
+
+	h := New([]cache.Cache{l1Cache, l2Cache}, WithAuthorizer(BearerToken(testToken)), WithClock(clk))
+	return h, l1Cache, l2Cache
+}
+
+func serve(h http.Handler, method, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler_Authorization(t *testing.T) {
+	tests := []struct {
+		name       string
+		authorizer Authorizer
+		header     string
+		wantStatus int
+	}{
+		{name: "denied by default", header: "Bearer " + testToken, wantStatus: http.StatusForbidden},
+		{name: "missing token", authorizer: BearerToken(testToken), wantStatus: http.StatusForbidden},
+		{name: "wrong token", authorizer: BearerToken(testToken), header: "Bearer nope", wantStatus: http.StatusForbidden},
+		{name: "valid token", authorizer: BearerToken(testToken), header: "Bearer " + testToken, wantStatus: http.StatusOK},
+		{
+			name:       "custom authorizer",
+			authorizer: AuthorizerFunc(func(r *http.Request) error { return nil }),
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []Option
+			if tt.authorizer != nil {
+				opts = append(opts, WithAuthorizer(tt.authorizer))
+			}
+			h := New(nil, opts...)
+
+			req := httptest.NewRequest(http.MethodGet, "/stats", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_LookupKey(t *testing.T) {
+	now := time.UnixMilli(time.Now().UnixMilli())
+	clk := clock.NewMock(now)
+	h, l1Cache, l2Cache := newTestHandler(t, clk)
+
+	l1Cache.Set("chain/eth_call", []byte("result"), models.TTL{Fresh: time.Minute, Stale: 30 * time.Second})
+	clk.Advance(10 * time.Second)
+	l2Cache.EXPECT().GetStale("chain/eth_call").Return(nil, false)
+
+	rec := serve(h, http.MethodGet, "/keys/chain/eth_call")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var resp KeyLookup
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "chain/eth_call", resp.Key)
+	require.Len(t, resp.Levels, 2)
+
+	assert.Equal(t, LevelEntry{
+		Level:          "L1",
+		Found:          true,
+		Kind:           "positive",
+		Size:           len("result"),
+		CreatedAt:      now.UTC(),
+		Age:            "10s",
+		Fresh:          true,
+		FreshRemaining: "50s",
+		StaleRemaining: "30s",
+	}, resp.Levels[0])
+	assert.Equal(t, LevelEntry{Level: "L2"}, resp.Levels[1])
+}
+
+func TestHandler_LookupKey_NotFound(t *testing.T) {
+	h, _, l2Cache := newTestHandler(t, clock.Real{})
+	l2Cache.EXPECT().GetStale("missing").Return(nil, false)
+
+	rec := serve(h, http.MethodGet, "/keys/missing")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = serve(h, http.MethodGet, "/keys/")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandler_DeleteKey(t *testing.T) {
+	h, l1Cache, l2Cache := newTestHandler(t, clock.Real{})
+
+	l1Cache.Set("key", []byte("value"), models.TTL{Fresh: time.Minute})
+	l2Cache.EXPECT().Delete("key")
+
+	rec := serve(h, http.MethodDelete, "/keys/key")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	_, found := l1Cache.Get("key")
+	assert.False(t, found)
+}
+
+func TestHandler_DeletePrefix(t *testing.T) {
+	h, l1Cache, _ := newTestHandler(t, clock.Real{})
+
+	ttl := models.TTL{Fresh: time.Minute}
+	l1Cache.Set("eth:1", []byte("a"), ttl)
+	l1Cache.Set("eth:2", []byte("b"), ttl)
+	l1Cache.Set("btc:1", []byte("c"), ttl)
+
+	rec := serve(h, http.MethodDelete, "/prefixes/eth:")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp PrefixDeletion
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, PrefixDeletion{
+		Prefix:      "eth:",
+		Removed:     map[string]int{"L1": 2},
+		Unsupported: []string{"L2"},
+	}, resp)
+
+	_, found := l1Cache.Get("btc:1")
+	assert.True(t, found)
+
+	rec = serve(h, http.MethodDelete, "/prefixes/")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandler_WithMultiCache(t *testing.T) {
+	newLevel := func() cache.Cache {
+		c, err := l1.NewBigCache(&cache.BigCacheConfig{Enabled: true, Size: 10})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = c.(*l1.BigCache).Close() })
+		return c
+	}
+
+	bus := invalidation.NewMemoryBus()
+	localL1, remoteL1, l2 := newLevel(), newLevel(), newLevel()
+	local := multi.NewMultiCache([]cache.Cache{localL1, l2}, true, multi.WithInvalidationBus(bus))
+	multi.NewMultiCache([]cache.Cache{remoteL1, l2}, true, multi.WithInvalidationBus(bus))
+
+	h := New([]cache.Cache{localL1, l2}, WithAuthorizer(BearerToken(testToken)),
+		WithMultiCache(local.(cache.TaggedCache)))
+
+	ttl := models.TTL{Fresh: time.Minute}
+	for _, key := range []string{"key", "eth:1", "eth:2"} {
+		for _, c := range []cache.Cache{localL1, remoteL1, l2} {
+			c.Set(key, []byte("value"), ttl)
+		}
+	}
+
+	rec := serve(h, http.MethodDelete, "/keys/key")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serve(h, http.MethodDelete, "/prefixes/eth:")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp PrefixDeletion
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, map[string]int{"multi": 2}, resp.Removed)
+
+	// The other replica's L1 is cleared over the bus
+	for _, key := range []string{"key", "eth:1", "eth:2"} {
+		for _, c := range []cache.Cache{localL1, remoteL1, l2} {
+			_, found := c.Get(key)
+			assert.False(t, found, key)
+		}
+	}
+}
+
+func TestHandler_Stats(t *testing.T) {
+	h, l1Cache, _ := newTestHandler(t, clock.Real{})
+	l1Cache.Set("key", []byte("value"), models.TTL{Fresh: time.Minute})
+	l1Cache.Get("key")
+
+	rec := serve(h, http.MethodGet, "/stats")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp Stats
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	require.Len(t, resp.Levels, 2)
+	assert.Equal(t, "L1", resp.Levels[0].Level)
+	assert.Equal(t, int64(1), resp.Levels[0].Keys)
+	assert.Equal(t, int64(1), resp.Levels[0].Hits)
+	assert.Equal(t, int64(10*1024*1024), resp.Levels[0].CapacityBytes)
+	assert.Equal(t, LevelStats{Level: "L2"}, resp.Levels[1])
+
+	rec = serve(h, http.MethodPost, "/stats")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -0,0 +1,261 @@
+package codec
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/models"
+)
+
+// Ensure codecs implement cache.Codec
+var _ cache.Codec = Binary{}
+var _ cache.Codec = JSON{}
+
+const (
+	// Magic is the first byte of every binary-encoded entry. It can never start a
+	// JSON document, which lets decoders tell the two formats apart.
+	Magic byte = 0xCE
+
+	// Version1 stores second-precision timestamps followed by the raw payload
+	Version1 byte = 1
+	// Version2 stores millisecond-precision timestamps followed by the raw payload
+	Version2 byte = 2
+	// Version3 is Version2 with the entry kind and error payload preceding the data:
+	// kind(1) + uvarint error length + error + data. It is only written for negative
+	// entries, so readers predating it treat those as unsupported rather than as hits.
+	Version3 byte = 3
+
+	// HeaderSize is the size of the binary header preceding the payload:
+	// magic(1) + version(1) + flags(1) + created_at(8) + stale_at(8) + expires_at(8)
+	HeaderSize = 27
+
+	// FlagNilData marks an entry whose Data was nil rather than empty
+	FlagNilData byte = 1 << 0
+)
+
+var (
+	// ErrUnknownFormat is returned when data is neither a binary nor a JSON entry
+	ErrUnknownFormat = errors.New("unknown cache entry format")
+	// ErrUnsupportedVersion is returned for binary entries written by a newer codec
+	ErrUnsupportedVersion = errors.New("unsupported cache entry version")
+	// ErrTruncated is returned when binary data is shorter than its header
+	ErrTruncated = errors.New("truncated cache entry")
+	// ErrMalformed is returned when a Version3 payload cannot be split into its parts
+	ErrMalformed = errors.New("malformed cache entry")
+)
+
+// Binary encodes entries as a fixed header followed by the payload, avoiding the
+// base64 inflation of JSON. It transparently decodes legacy JSON entries.
+//
+// When Compression is set, payloads of at least Threshold bytes are compressed and the
+// algorithm is recorded in the header; payloads that do not shrink are stored raw.
+// Decoding handles every algorithm regardless of the codec's own settings, and fails
+// with ErrTooLarge once a payload decompresses past MaxDecompressedSize bytes
+// (DefaultMaxDecompressedSize when zero).
+type Binary struct {
+	Compression         cache.CompressionAlgorithm
+	Threshold           int
+	MaxDecompressedSize int
+
+	// OnCompress, if set, is called with the payload size before and after compression
+	OnCompress func(algorithm cache.CompressionAlgorithm, rawBytes, compressedBytes int)
+}
+
+// NewBinary creates a Binary codec from compression settings
+func NewBinary(cfg cache.CompressionConfig) Binary {
+	return Binary{
+		Compression:         cfg.Algorithm,
+		Threshold:           cfg.Threshold,
+		MaxDecompressedSize: cfg.MaxDecompressedSize,
+	}
+}
+
+// New returns the codec writing entries in format: Binary with the given compression
+// settings and OnCompress hook, or JSON, which only applies the decompression limit.
+// It fails if the format or compression settings are invalid.
+func New(
+	format cache.EntryFormat,
+	compression cache.CompressionConfig,
+	onCompress func(algorithm cache.CompressionAlgorithm, rawBytes, compressedBytes int),
+) (cache.Codec, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+	if err := compression.Validate(); err != nil {
+		return nil, err
+	}
+	if format != cache.EntryFormatBinary {
+		return JSON{MaxDecompressedSize: compression.MaxDecompressedSize}, nil
+	}
+
+	b := NewBinary(compression)
+	b.OnCompress = onCompress
+	return b, nil
+}
+
+// Encode serializes entry in binary format
+func (b Binary) Encode(entry *models.CacheEntry) ([]byte, error) {
+	var flags byte
+	if entry.Data == nil {
+		flags |= FlagNilData
+	}
+
+	version := Version2
+	payload := entry.Data
+	if entry.Kind != models.EntryKindPositive || entry.Error != nil {
+		version = Version3
+		payload = encodeKind(entry)
+	}
+
+	if len(payload) > 0 && len(payload) >= b.Threshold {
+		id, err := compressionID(b.Compression)
+		if err != nil {
+			return nil, err
+		}
+		if id != compressionNone {
+			compressed, err := compress(id, payload)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compress cache entry: %w", err)
+			}
+			if len(compressed) < len(payload) {
+				if b.OnCompress != nil {
+					b.OnCompress(b.Compression, len(payload), len(compressed))
+				}
+				payload = compressed
+				flags |= id << flagCompressionShift
+			}
+		}
+	}
+
+	buf := make([]byte, HeaderSize+len(payload))
+	buf[0] = Magic
+	buf[1] = version
+	buf[2] = flags
+	binary.BigEndian.PutUint64(buf[3:11], uint64(entry.CreatedTime().UnixMilli()))
+	binary.BigEndian.PutUint64(buf[11:19], uint64(entry.StaleTime().UnixMilli()))
+	binary.BigEndian.PutUint64(buf[19:27], uint64(entry.ExpiresTime().UnixMilli()))
+	copy(buf[HeaderSize:], payload)
+
+	return buf, nil
+}
+
+// Decode deserializes a binary or legacy JSON entry. The returned entry's Data
+// aliases data, so callers must not reuse data afterwards.
+func (b Binary) Decode(data []byte) (*models.CacheEntry, error) {
+	return decode(data, maxSize(b.MaxDecompressedSize))
+}
+
+// JSON encodes entries as JSON, the format used before the binary codec existed.
+// It decodes binary entries too, so it can be used as the writer during a rolling
+// deploy while older replicas still share the same L2. Compressed binary entries are
+// bounded by MaxDecompressedSize as in Binary.
+type JSON struct {
+	MaxDecompressedSize int
+}
+
+// Encode serializes entry as JSON
+func (JSON) Encode(entry *models.CacheEntry) ([]byte, error) {
+	return json.Marshal(entry)
+}
+
+// Decode deserializes a binary or JSON entry
+func (j JSON) Decode(data []byte) (*models.CacheEntry, error) {
+	return decode(data, maxSize(j.MaxDecompressedSize))
+}
+
+// decode dispatches on the first byte to the binary or JSON decoder
+func decode(data []byte, limit int) (*models.CacheEntry, error) {
+	if len(data) == 0 {
+		return nil, ErrTruncated
+	}
+
+	switch data[0] {
+	case Magic:
+		return decodeBinary(data, limit)
+	case '{':
+		var entry models.CacheEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, err
+		}
+		return &entry, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// decodeBinary parses a binary entry
+func decodeBinary(data []byte, limit int) (*models.CacheEntry, error) {
+	if len(data) < 2 {
+		return nil, ErrTruncated
+	}
+
+	var scale int64
+	switch data[1] {
+	case Version1:
+		scale = 1000
+	case Version2, Version3:
+		scale = 1
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, data[1])
+	}
+	if len(data) < HeaderSize {
+		return nil, ErrTruncated
+	}
+
+	entry := &models.CacheEntry{}
+	entry.SetTimes(
+		time.UnixMilli(int64(binary.BigEndian.Uint64(data[3:11]))*scale),
+		time.UnixMilli(int64(binary.BigEndian.Uint64(data[11:19]))*scale),
+		time.UnixMilli(int64(binary.BigEndian.Uint64(data[19:27]))*scale),
+	)
+	if data[2]&FlagNilData != 0 && data[1] != Version3 {
+		return entry, nil
+	}
+
+	payload, err := decompress((data[2]&flagCompressionMask)>>flagCompressionShift, data[HeaderSize:], limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress cache entry: %w", err)
+	}
+	if data[1] == Version3 {
+		if payload, err = decodeKind(entry, payload); err != nil {
+			return nil, err
+		}
+	}
+	if data[2]&FlagNilData == 0 {
+		entry.Data = payload
+	}
+
+	return entry, nil
+}
+
+// encodeKind builds a Version3 payload from the entry's kind, error and data
+func encodeKind(entry *models.CacheEntry) []byte {
+	buf := make([]byte, 0, 1+binary.MaxVarintLen64+len(entry.Error)+len(entry.Data))
+	buf = append(buf, byte(entry.Kind))
+	buf = binary.AppendUvarint(buf, uint64(len(entry.Error)))
+	buf = append(buf, entry.Error...)
+	return append(buf, entry.Data...)
+}
+
+// decodeKind sets the kind and error of entry from a Version3 payload, returning the data
+func decodeKind(entry *models.CacheEntry, payload []byte) ([]byte, error) {
+	if len(payload) < 1 {
+		return nil, ErrMalformed
+	}
+	entry.Kind = models.EntryKind(payload[0])
+
+	errLen, n := binary.Uvarint(payload[1:])
+	if n <= 0 || errLen > uint64(len(payload)-1-n) {
+		return nil, ErrMalformed
+	}
+	rest := payload[1+n:]
+	if errLen > 0 {
+		entry.Error = rest[:errLen]
+	}
+
+	return rest[errLen:], nil
+}
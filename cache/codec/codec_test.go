@@ -0,0 +1,327 @@
+package codec
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/models"
+)
+
+func newTestEntry(data []byte) *models.CacheEntry {
+	return models.NewCacheEntry(data, models.TTL{Fresh: 60 * time.Second, Stale: 60 * time.Second}, time.Now())
+}
+
+func TestBinary_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "payload", data: []byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`)},
+		{name: "binary payload", data: []byte{0x00, 0xCE, 0xFF, '{'}},
+		{name: "empty payload", data: []byte{}},
+		{name: "nil payload", data: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := newTestEntry(tt.data)
+
+			encoded, err := Binary{}.Encode(entry)
+			require.NoError(t, err)
+			assert.Len(t, encoded, HeaderSize+len(tt.data))
+
+			decoded, err := Binary{}.Decode(encoded)
+			require.NoError(t, err)
+			assert.Equal(t, entry, decoded)
+		})
+	}
+}
+
+func TestBinary_NegativeEntry(t *testing.T) {
+	ttl := models.TTL{Fresh: 5 * time.Second}
+	errPayload := []byte(`{"code":-32000,"message":"transaction not found"}`)
+
+	tests := []struct {
+		name  string
+		entry *models.CacheEntry
+		codec Binary
+	}{
+		{name: "not found", entry: models.NewNegativeCacheEntry(nil, ttl, time.UnixMilli(1700000000000))},
+		{name: "upstream error", entry: models.NewNegativeCacheEntry(errPayload, ttl, time.UnixMilli(1700000000000))},
+		{
+			name:  "compressed error",
+			entry: models.NewNegativeCacheEntry(bytes.Repeat(errPayload, 50), ttl, time.UnixMilli(1700000000000)),
+			codec: Binary{Compression: cache.CompressionSnappy, Threshold: 64},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.codec.Encode(tt.entry)
+			require.NoError(t, err)
+			assert.Equal(t, Version3, encoded[1])
+
+			decoded, err := Binary{}.Decode(encoded)
+			require.NoError(t, err)
+			assert.Equal(t, tt.entry, decoded)
+		})
+	}
+
+	t.Run("positive entries stay version 2", func(t *testing.T) {
+		encoded, err := Binary{}.Encode(newTestEntry([]byte("data")))
+		require.NoError(t, err)
+		assert.Equal(t, Version2, encoded[1])
+	})
+}
+
+func TestBinary_SmallerThanJSON(t *testing.T) {
+	entry := newTestEntry(make([]byte, 4096))
+
+	binaryData, err := Binary{}.Encode(entry)
+	require.NoError(t, err)
+	jsonData, err := JSON{}.Encode(entry)
+	require.NoError(t, err)
+
+	assert.Less(t, len(binaryData), len(jsonData))
+}
+
+func TestBinary_DecodesLegacyJSON(t *testing.T) {
+	entry := newTestEntry([]byte("legacy"))
+	legacy, err := json.Marshal(entry)
+	require.NoError(t, err)
+
+	decoded, err := Binary{}.Decode(legacy)
+
+	require.NoError(t, err)
+	assert.Equal(t, entry, decoded)
+}
+
+func TestBinary_DecodesSecondPrecisionJSON(t *testing.T) {
+	legacy := []byte(`{"data":"bGVnYWN5","expires_at":1700000120,"stale_at":1700000060,"created_at":1700000000}`)
+
+	decoded, err := Binary{}.Decode(legacy)
+
+	require.NoError(t, err)
+	assert.Equal(t, &models.CacheEntry{
+		Data:      []byte("legacy"),
+		CreatedAt: 1700000000,
+		StaleAt:   1700000060,
+		ExpiresAt: 1700000120,
+	}, decoded)
+	assert.Equal(t, time.Unix(1700000060, 0), decoded.StaleTime())
+}
+
+func TestBinary_DecodesVersion1(t *testing.T) {
+	legacy := make([]byte, HeaderSize, HeaderSize+len("v1"))
+	legacy[0] = Magic
+	legacy[1] = Version1
+	binary.BigEndian.PutUint64(legacy[3:11], 1700000000)
+	binary.BigEndian.PutUint64(legacy[11:19], 1700000060)
+	binary.BigEndian.PutUint64(legacy[19:27], 1700000120)
+	legacy = append(legacy, "v1"...)
+
+	decoded, err := Binary{}.Decode(legacy)
+
+	require.NoError(t, err)
+	assert.Equal(t, &models.CacheEntry{
+		Data:        []byte("v1"),
+		CreatedAt:   1700000000,
+		StaleAt:     1700000060,
+		ExpiresAt:   1700000120,
+		CreatedAtMs: 1700000000000,
+		StaleAtMs:   1700000060000,
+		ExpiresAtMs: 1700000120000,
+	}, decoded)
+}
+
+func TestBinary_MillisecondPrecision(t *testing.T) {
+	entry := models.NewCacheEntry([]byte("ms"), models.TTL{Fresh: 250 * time.Millisecond}, time.UnixMilli(1700000000123))
+
+	encoded, err := Binary{}.Encode(entry)
+	require.NoError(t, err)
+	assert.Equal(t, Version2, encoded[1])
+
+	decoded, err := Binary{}.Decode(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1700000000373), decoded.StaleAtMs)
+	assert.Equal(t, int64(1700000000), decoded.StaleAt)
+}
+
+func TestJSON_DecodesBinary(t *testing.T) {
+	entry := newTestEntry([]byte("binary"))
+	encoded, err := Binary{}.Encode(entry)
+	require.NoError(t, err)
+
+	decoded, err := JSON{}.Decode(encoded)
+
+	require.NoError(t, err)
+	assert.Equal(t, entry, decoded)
+}
+
+func TestDecode_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{name: "empty", data: nil, wantErr: ErrTruncated},
+		{name: "unknown format", data: []byte("garbage"), wantErr: ErrUnknownFormat},
+		{name: "truncated header", data: []byte{Magic, Version1, 0, 1, 2}, wantErr: ErrTruncated},
+		{name: "future version", data: append([]byte{Magic, 99}, make([]byte, HeaderSize)...), wantErr: ErrUnsupportedVersion},
+		{name: "version 3 without kind", data: append([]byte{Magic, Version3}, make([]byte, HeaderSize-2)...), wantErr: ErrMalformed},
+		{name: "version 3 error overflow", data: append(append([]byte{Magic, Version3}, make([]byte, HeaderSize-2)...), 1, 10, 'x'), wantErr: ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := Binary{}.Decode(tt.data)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, entry)
+		})
+	}
+
+	t.Run("invalid json", func(t *testing.T) {
+		entry, err := JSON{}.Decode([]byte("{invalid"))
+
+		assert.Error(t, err)
+		assert.Nil(t, entry)
+	})
+}
+
+func TestBinary_Compression(t *testing.T) {
+	payload := bytes.Repeat([]byte(`{"address":"0x0000000000000000000000000000000000000000","topics":[]},`), 100)
+
+	algorithms := []cache.CompressionAlgorithm{
+		cache.CompressionGzip,
+		cache.CompressionSnappy,
+		cache.CompressionZstd,
+	}
+
+	for _, algorithm := range algorithms {
+		t.Run(string(algorithm), func(t *testing.T) {
+			var raw, compressed int
+			c := NewBinary(cache.CompressionConfig{Algorithm: algorithm, Threshold: 1024})
+			c.OnCompress = func(a cache.CompressionAlgorithm, rawBytes, compressedBytes int) {
+				assert.Equal(t, algorithm, a)
+				raw, compressed = rawBytes, compressedBytes
+			}
+
+			entry := newTestEntry(payload)
+			encoded, err := c.Encode(entry)
+			require.NoError(t, err)
+
+			assert.Less(t, len(encoded), HeaderSize+len(payload))
+			assert.Equal(t, len(payload), raw)
+			assert.Equal(t, len(encoded)-HeaderSize, compressed)
+
+			// Decoding does not depend on the decoder's compression settings
+			decoded, err := Binary{}.Decode(encoded)
+			require.NoError(t, err)
+			assert.Equal(t, entry, decoded)
+		})
+	}
+}
+
+func TestBinary_Compression_BelowThreshold(t *testing.T) {
+	c := NewBinary(cache.CompressionConfig{Algorithm: cache.CompressionZstd, Threshold: 1024})
+	c.OnCompress = func(cache.CompressionAlgorithm, int, int) {
+		t.Fatal("values below the threshold should not be compressed")
+	}
+
+	payload := bytes.Repeat([]byte("a"), 512)
+	encoded, err := c.Encode(newTestEntry(payload))
+
+	require.NoError(t, err)
+	assert.Equal(t, payload, encoded[HeaderSize:])
+}
+
+func TestBinary_Compression_IncompressibleStoredRaw(t *testing.T) {
+	payload := make([]byte, 2048)
+	_, err := rand.Read(payload)
+	require.NoError(t, err)
+
+	c := NewBinary(cache.CompressionConfig{Algorithm: cache.CompressionGzip, Threshold: 1024})
+	encoded, err := c.Encode(newTestEntry(payload))
+
+	require.NoError(t, err)
+	assert.Equal(t, payload, encoded[HeaderSize:])
+
+	decoded, err := c.Decode(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, payload, decoded.Data)
+}
+
+func TestBinary_Compression_UnsupportedAlgorithm(t *testing.T) {
+	c := Binary{Compression: "lz4", Threshold: 1}
+
+	encoded, err := c.Encode(newTestEntry([]byte("payload")))
+
+	assert.Error(t, err)
+	assert.Nil(t, encoded)
+}
+
+func TestBinary_Compression_CorruptedPayload(t *testing.T) {
+	c := NewBinary(cache.CompressionConfig{Algorithm: cache.CompressionZstd, Threshold: 1})
+	encoded, err := c.Encode(newTestEntry(bytes.Repeat([]byte("a"), 4096)))
+	require.NoError(t, err)
+
+	encoded = encoded[:len(encoded)-4]
+
+	decoded, err := c.Decode(encoded)
+	assert.Error(t, err)
+	assert.Nil(t, decoded)
+}
+
+func TestBinary_Compression_DecompressionLimit(t *testing.T) {
+	payload := bytes.Repeat([]byte("a"), 64*1024)
+
+	for _, algorithm := range []cache.CompressionAlgorithm{cache.CompressionGzip, cache.CompressionSnappy, cache.CompressionZstd} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			encoded, err := NewBinary(cache.CompressionConfig{Algorithm: algorithm, Threshold: 1}).Encode(newTestEntry(payload))
+			require.NoError(t, err)
+
+			decoded, err := Binary{MaxDecompressedSize: len(payload) - 1}.Decode(encoded)
+			assert.ErrorIs(t, err, ErrTooLarge)
+			assert.Nil(t, decoded)
+
+			decoded, err = JSON{MaxDecompressedSize: len(payload) - 1}.Decode(encoded)
+			assert.ErrorIs(t, err, ErrTooLarge)
+			assert.Nil(t, decoded)
+
+			decoded, err = Binary{MaxDecompressedSize: len(payload)}.Decode(encoded)
+			require.NoError(t, err)
+			assert.Equal(t, payload, decoded.Data)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Run("json by default", func(t *testing.T) {
+		c, err := New("", cache.CompressionConfig{Algorithm: cache.CompressionZstd}, nil)
+		require.NoError(t, err)
+		assert.IsType(t, JSON{}, c)
+	})
+
+	t.Run("binary with compression", func(t *testing.T) {
+		c, err := New(cache.EntryFormatBinary, cache.CompressionConfig{Algorithm: cache.CompressionZstd, Threshold: 1}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, cache.CompressionZstd, c.(Binary).Compression)
+	})
+
+	t.Run("rejects invalid settings", func(t *testing.T) {
+		_, err := New("msgpack", cache.CompressionConfig{}, nil)
+		assert.Error(t, err)
+
+		_, err = New(cache.EntryFormatBinary, cache.CompressionConfig{Algorithm: "lz4"}, nil)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,151 @@
+package codec
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/status-im/proxy-common/cache"
+)
+
+// Compression algorithm identifiers stored in bits 1-2 of the header flags byte
+const (
+	compressionNone   byte = 0
+	compressionGzip   byte = 1
+	compressionSnappy byte = 2
+	compressionZstd   byte = 3
+
+	flagCompressionShift      = 1
+	flagCompressionMask  byte = 0x3 << flagCompressionShift
+)
+
+// DefaultMaxDecompressedSize bounds decompressed payloads when a codec sets no limit
+const DefaultMaxDecompressedSize = 64 << 20
+
+// ErrTooLarge is returned when a compressed payload decompresses past the size limit
+var ErrTooLarge = errors.New("decompressed cache entry exceeds size limit")
+
+// zstd encoders and decoders are safe for concurrent EncodeAll/DecodeAll calls. The
+// decoder stops DecodeAll at the capacity of the destination buffer.
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecodeAllCapLimit(true))
+)
+
+// maxSize returns limit, or DefaultMaxDecompressedSize when limit is not positive
+func maxSize(limit int) int {
+	if limit <= 0 {
+		return DefaultMaxDecompressedSize
+	}
+	return limit
+}
+
+// compressionID maps a configured algorithm to its header identifier
+func compressionID(algorithm cache.CompressionAlgorithm) (byte, error) {
+	switch algorithm {
+	case "", cache.CompressionNone:
+		return compressionNone, nil
+	case cache.CompressionGzip:
+		return compressionGzip, nil
+	case cache.CompressionSnappy:
+		return compressionSnappy, nil
+	case cache.CompressionZstd:
+		return compressionZstd, nil
+	default:
+		return 0, fmt.Errorf("unsupported compression algorithm '%s'", algorithm)
+	}
+}
+
+// compress compresses data with the algorithm identified by id
+func compress(id byte, data []byte) ([]byte, error) {
+	switch id {
+	case compressionGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case compressionSnappy:
+		return snappy.Encode(nil, data), nil
+	case compressionZstd:
+		return zstdEncoder.EncodeAll(data, nil), nil
+	default:
+		return data, nil
+	}
+}
+
+// decompress reverses compress for the algorithm identified by id, failing with
+// ErrTooLarge rather than producing more than limit bytes
+func decompress(id byte, data []byte, limit int) ([]byte, error) {
+	switch id {
+	case compressionNone:
+		return data, nil
+	case compressionGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return readLimited(r, limit)
+	case compressionSnappy:
+		n, err := snappy.DecodedLen(data)
+		if err != nil {
+			return nil, err
+		}
+		if n > limit {
+			return nil, ErrTooLarge
+		}
+		return snappy.Decode(nil, data)
+	case compressionZstd:
+		return decompressZstd(data, limit)
+	default:
+		return nil, fmt.Errorf("unknown compression algorithm id %d", id)
+	}
+}
+
+// decompressZstd decodes frames written with their content size, as EncodeAll does,
+// into a buffer of exactly that size, and streams any other input
+func decompressZstd(data []byte, limit int) ([]byte, error) {
+	var header zstd.Header
+	if err := header.Decode(data); err != nil {
+		return nil, err
+	}
+	if !header.HasFCS {
+		r, err := zstd.NewReader(bytes.NewReader(data), zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return readLimited(r, limit)
+	}
+	if header.FrameContentSize > uint64(limit) {
+		return nil, ErrTooLarge
+	}
+
+	out, err := zstdDecoder.DecodeAll(data, make([]byte, 0, header.FrameContentSize))
+	if errors.Is(err, zstd.ErrDecoderSizeExceeded) {
+		return nil, ErrTooLarge
+	}
+	return out, err
+}
+
+// readLimited reads r to the end, failing with ErrTooLarge past limit bytes
+func readLimited(r io.Reader, limit int) ([]byte, error) {
+	out, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(out) > limit {
+		return nil, ErrTooLarge
+	}
+	return out, nil
+}
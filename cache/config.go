@@ -1,6 +1,17 @@
 package cache
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/status-im/proxy-common/models"
+)
 
 // BigCacheConfig represents BigCache (L1) configuration
 type BigCacheConfig struct {
@@ -8,6 +19,13 @@ type BigCacheConfig struct {
 	Size         int  `yaml:"size" json:"size"`
 	MaxEntrySize int  `yaml:"max_entry_size" json:"max_entry_size"`
 	Shards       int  `yaml:"shards" json:"shards"` // must be power of 2
+
+	// TTLScale multiplies every TTL written to L1, e.g. 0.5 to bound cross-replica staleness
+	TTLScale  float64         `yaml:"ttl_scale" json:"ttl_scale"`
+	TTLJitter TTLJitterConfig `yaml:"ttl_jitter" json:"ttl_jitter"`
+
+	Format      EntryFormat       `yaml:"format" json:"format"`
+	Compression CompressionConfig `yaml:"compression" json:"compression"` // binary format only
 }
 
 func (c *BigCacheConfig) ApplyDefaults() {
@@ -20,6 +38,14 @@ func (c *BigCacheConfig) ApplyDefaults() {
 	if c.Shards == 0 {
 		c.Shards = 256 // power of 2
 	}
+	if c.TTLScale == 0 {
+		c.TTLScale = 1
+	}
+	if c.Format == "" {
+		c.Format = EntryFormatJSON
+	}
+
+	c.Compression.ApplyDefaults()
 }
 
 // KeyDBConfig represents KeyDB (L2) cache configuration
@@ -28,6 +54,13 @@ type KeyDBConfig struct {
 	Connection ConnectionConfig `yaml:"connection" json:"connection"`
 	Keepalive  KeepaliveConfig  `yaml:"keepalive" json:"keepalive"`
 	Cache      CacheSettings    `yaml:"cache" json:"cache"`
+	Sentinel   SentinelConfig   `yaml:"sentinel" json:"sentinel"`
+	Cluster    ClusterConfig    `yaml:"cluster" json:"cluster"`
+
+	Format         EntryFormat          `yaml:"format" json:"format"`
+	Compression    CompressionConfig    `yaml:"compression" json:"compression"` // binary format only
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuit_breaker" json:"circuit_breaker"`
+	WriteBehind    WriteBehindConfig    `yaml:"write_behind" json:"write_behind"`
 }
 
 func (c *KeyDBConfig) ApplyDefaults() {
@@ -54,12 +87,34 @@ func (c *KeyDBConfig) ApplyDefaults() {
 	if c.Cache.MaxTTL == 0 {
 		c.Cache.MaxTTL = 86400 * time.Second
 	}
+	if c.Cache.TTLScale == 0 {
+		c.Cache.TTLScale = 1
+	}
+	if c.Format == "" {
+		c.Format = EntryFormatJSON
+	}
+
+	c.Compression.ApplyDefaults()
+	c.CircuitBreaker.ApplyDefaults()
+	c.WriteBehind.ApplyDefaults()
 }
 
 type ConnectionConfig struct {
 	ConnectTimeout time.Duration `yaml:"connect_timeout" json:"connect_timeout"`
 	SendTimeout    time.Duration `yaml:"send_timeout" json:"send_timeout"`
 	ReadTimeout    time.Duration `yaml:"read_timeout" json:"read_timeout"`
+	TLS            TLSConfig     `yaml:"tls" json:"tls"`
+}
+
+// TLSConfig represents TLS settings for the KeyDB connection.
+// TLS is enabled by a rediss:// URL scheme or by setting Enabled.
+type TLSConfig struct {
+	Enabled            bool   `yaml:"enabled" json:"enabled"`
+	CAFile             string `yaml:"ca_file" json:"ca_file"`     // PEM CA bundle; system roots if empty
+	CertFile           string `yaml:"cert_file" json:"cert_file"` // PEM client certificate for mutual TLS
+	KeyFile            string `yaml:"key_file" json:"key_file"`   // PEM client private key for mutual TLS
+	ServerName         string `yaml:"server_name" json:"server_name"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
 }
 
 // KeepaliveConfig represents connection pool settings
@@ -68,11 +123,499 @@ type KeepaliveConfig struct {
 	MaxIdleTimeout time.Duration `yaml:"max_idle_timeout" json:"max_idle_timeout"`
 }
 
+// SentinelConfig represents Redis Sentinel failover settings.
+// Used when the KeyDB URL scheme is redis+sentinel:// or MasterName is set.
+type SentinelConfig struct {
+	MasterName string   `yaml:"master_name" json:"master_name"`
+	Addrs      []string `yaml:"addrs" json:"addrs"`       // sentinel seed addresses, used if the URL has no hosts
+	Password   string   `yaml:"password" json:"password"` // password for the sentinels themselves
+}
+
+// ClusterConfig represents Redis Cluster settings.
+// Used when the KeyDB URL scheme is redis+cluster:// or Addrs is set.
+type ClusterConfig struct {
+	Addrs          []string `yaml:"addrs" json:"addrs"` // cluster seed addresses, used if the URL has no hosts
+	RouteByLatency bool     `yaml:"route_by_latency" json:"route_by_latency"`
+	RouteRandomly  bool     `yaml:"route_randomly" json:"route_randomly"`
+}
+
+// CacheSettings represents the TTL limits enforced on every L2 write
 type CacheSettings struct {
-	DefaultTTL time.Duration `yaml:"default_ttl" json:"default_ttl"`
-	MaxTTL     time.Duration `yaml:"max_ttl" json:"max_ttl"`
+	DefaultTTL time.Duration `yaml:"default_ttl" json:"default_ttl"` // used when a caller passes a zero TTL
+	MaxTTL     time.Duration `yaml:"max_ttl" json:"max_ttl"`         // cap on fresh + stale
+	TTLScale   float64       `yaml:"ttl_scale" json:"ttl_scale"`     // multiplies every TTL before the limits apply
+
+	TTLJitter TTLJitterConfig `yaml:"ttl_jitter" json:"ttl_jitter"`
+}
+
+// TTL adjustment reasons reported to MetricsRecorder.RecordCacheTTLAdjusted
+const (
+	TTLAdjustmentDefault = "default"
+	TTLAdjustmentClamped = "clamped"
+)
+
+// ApplyTTL scales ttl by TTLScale, substitutes DefaultTTL for a zero TTL and caps
+// fresh + stale at MaxTTL, shortening the stale period first. A DefaultTTL above
+// MaxTTL is capped too. It returns the adjusted TTL and the last adjustment made, or ""
+// if the limits did not apply.
+func (s CacheSettings) ApplyTTL(ttl models.TTL) (models.TTL, string) {
+	ttl = ScaleTTL(ttl, s.TTLScale)
+	if ttl.Fresh < 0 {
+		ttl.Fresh = 0
+	}
+	if ttl.Stale < 0 {
+		ttl.Stale = 0
+	}
+
+	reason := ""
+	if ttl.Fresh+ttl.Stale == 0 && s.DefaultTTL > 0 {
+		ttl = models.TTL{Fresh: s.DefaultTTL}
+		reason = TTLAdjustmentDefault
+	}
+
+	if s.MaxTTL > 0 && ttl.Fresh+ttl.Stale > s.MaxTTL {
+		if ttl.Fresh > s.MaxTTL {
+			ttl.Fresh = s.MaxTTL
+		}
+		ttl.Stale = s.MaxTTL - ttl.Fresh
+		reason = TTLAdjustmentClamped
+	}
+
+	return ttl, reason
+}
+
+// ScaleTTL multiplies both periods of ttl by scale; a scale of 0 or 1 leaves it unchanged
+func ScaleTTL(ttl models.TTL, scale float64) models.TTL {
+	if scale == 0 || scale == 1 {
+		return ttl
+	}
+
+	return models.TTL{
+		Fresh: time.Duration(float64(ttl.Fresh) * scale),
+		Stale: time.Duration(float64(ttl.Stale) * scale),
+	}
+}
+
+// TTLJitterConfig represents per-level TTL jitter settings. Jitter shortens both
+// periods of a TTL by a random share of up to Percent percent, so entries written
+// together do not all expire in the same second. Jitter never lengthens a TTL,
+// so it cannot push an entry past MaxTTL.
+type TTLJitterConfig struct {
+	Percent       float64 `yaml:"percent" json:"percent"`             // 0 disables jitter, capped at 100
+	Deterministic bool    `yaml:"deterministic" json:"deterministic"` // derive the share from the key so replicas agree
+}
+
+// Apply shortens ttl by the jitter share for key
+func (c TTLJitterConfig) Apply(key string, ttl models.TTL) models.TTL {
+	if c.Percent <= 0 {
+		return ttl
+	}
+
+	share := min(c.Percent, 100) / 100 * c.fraction(key)
+	return models.TTL{
+		Fresh: ttl.Fresh - time.Duration(float64(ttl.Fresh)*share),
+		Stale: ttl.Stale - time.Duration(float64(ttl.Stale)*share),
+	}
+}
+
+// fraction returns a value in [0, 1), derived from key when Deterministic is set
+func (c TTLJitterConfig) fraction(key string) float64 {
+	if !c.Deterministic {
+		return rand.Float64()
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+
+	// FNV-1a barely moves the high bits for keys differing only in their last
+	// bytes, so spread them with the murmur3 finalizer
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return float64(x>>11) / (1 << 53)
+}
+
+// CircuitBreakerConfig represents the L2 circuit breaker settings. Within each Window,
+// once MinRequests requests were made, the breaker opens if the share of failed
+// requests reaches ErrorRate or the share of requests slower than SlowThreshold reaches
+// SlowRate. While open, L2 is skipped; after OpenTimeout a PING probe closes the
+// breaker if it succeeds within SlowThreshold, or keeps it open for another OpenTimeout.
+type CircuitBreakerConfig struct {
+	Enabled       bool          `yaml:"enabled" json:"enabled"`
+	Window        time.Duration `yaml:"window" json:"window"`
+	MinRequests   int           `yaml:"min_requests" json:"min_requests"`
+	ErrorRate     float64       `yaml:"error_rate" json:"error_rate"` // 0 to 1
+	SlowThreshold time.Duration `yaml:"slow_threshold" json:"slow_threshold"`
+	SlowRate      float64       `yaml:"slow_rate" json:"slow_rate"` // 0 to 1
+	OpenTimeout   time.Duration `yaml:"open_timeout" json:"open_timeout"`
+}
+
+func (c *CircuitBreakerConfig) ApplyDefaults() {
+	if c.Window == 0 {
+		c.Window = 10 * time.Second
+	}
+	if c.MinRequests == 0 {
+		c.MinRequests = 20
+	}
+	if c.ErrorRate == 0 {
+		c.ErrorRate = 0.5
+	}
+	if c.SlowThreshold == 0 {
+		c.SlowThreshold = 250 * time.Millisecond
+	}
+	if c.SlowRate == 0 {
+		c.SlowRate = 0.5
+	}
+	if c.OpenTimeout == 0 {
+		c.OpenTimeout = 5 * time.Second
+	}
+}
+
+// CircuitState is the state of a circuit breaker guarding a cache level
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // requests pass through
+	CircuitHalfOpen                     // a probe is checking whether the level recovered
+	CircuitOpen                         // requests are rejected without reaching the level
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitHalfOpen:
+		return "half_open"
+	case CircuitOpen:
+		return "open"
+	default:
+		return fmt.Sprintf("state(%d)", int(s))
+	}
+}
+
+// OverflowPolicy decides which write is dropped when the write-behind queue is full
+type OverflowPolicy string
+
+const (
+	OverflowDropOldest OverflowPolicy = "drop_oldest" // evict the oldest queued write
+	OverflowDropNewest OverflowPolicy = "drop_newest" // reject the incoming write
+)
+
+// Validate rejects unknown policies; empty selects the default
+func (p OverflowPolicy) Validate() error {
+	switch p {
+	case "", OverflowDropOldest, OverflowDropNewest:
+		return nil
+	default:
+		return fmt.Errorf("invalid overflow policy '%s': must be one of 'drop_oldest', 'drop_newest'", p)
+	}
+}
+
+// UnmarshalYAML implements custom YAML unmarshaling for OverflowPolicy
+func (p *OverflowPolicy) UnmarshalYAML(value *yaml.Node) error {
+	var str string
+	if err := value.Decode(&str); err != nil {
+		return err
+	}
+	*p = OverflowPolicy(str)
+	return p.Validate()
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for OverflowPolicy
+func (p *OverflowPolicy) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	*p = OverflowPolicy(str)
+	return p.Validate()
+}
+
+// WriteBehindConfig represents the asynchronous L2 writer settings. When enabled, Set,
+// SetNegative and SetMany queue the encoded entry and return; a background writer
+// sends up to BatchSize queued writes per pipeline, as soon as a batch is full or
+// after FlushInterval. At most QueueSize writes are queued, and Overflow picks the
+// one dropped when the queue is full.
+type WriteBehindConfig struct {
+	Enabled       bool           `yaml:"enabled" json:"enabled"`
+	QueueSize     int            `yaml:"queue_size" json:"queue_size"`
+	BatchSize     int            `yaml:"batch_size" json:"batch_size"`
+	FlushInterval time.Duration  `yaml:"flush_interval" json:"flush_interval"`
+	Overflow      OverflowPolicy `yaml:"overflow" json:"overflow"`
+}
+
+func (c *WriteBehindConfig) ApplyDefaults() {
+	if c.QueueSize == 0 {
+		c.QueueSize = 10000
+	}
+	if c.BatchSize == 0 {
+		c.BatchSize = 100
+	}
+	if c.FlushInterval == 0 {
+		c.FlushInterval = 10 * time.Millisecond
+	}
+	if c.Overflow == "" {
+		c.Overflow = OverflowDropOldest
+	}
+}
+
+// Validate rejects negative queue size, batch size and flush interval, and unknown
+// overflow policies
+func (c WriteBehindConfig) Validate() error {
+	if c.QueueSize < 0 {
+		return fmt.Errorf("invalid write-behind queue size %d: must be positive", c.QueueSize)
+	}
+	if c.BatchSize < 0 {
+		return fmt.Errorf("invalid write-behind batch size %d: must be positive", c.BatchSize)
+	}
+	if c.FlushInterval < 0 {
+		return fmt.Errorf("invalid write-behind flush interval %v: must be positive", c.FlushInterval)
+	}
+	return c.Overflow.Validate()
+}
+
+// UnmarshalYAML implements custom YAML unmarshaling for WriteBehindConfig, rejecting
+// invalid values when the config is loaded
+func (c *WriteBehindConfig) UnmarshalYAML(value *yaml.Node) error {
+	type plain WriteBehindConfig
+	if err := value.Decode((*plain)(c)); err != nil {
+		return err
+	}
+	return c.Validate()
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for WriteBehindConfig, rejecting
+// invalid values when the config is loaded
+func (c *WriteBehindConfig) UnmarshalJSON(data []byte) error {
+	type plain WriteBehindConfig
+	if err := json.Unmarshal(data, (*plain)(c)); err != nil {
+		return err
+	}
+	return c.Validate()
+}
+
+// EntryFormat selects how a cache level encodes the entries it writes. Entries in
+// either format are always decoded.
+type EntryFormat string
+
+const (
+	// EntryFormatJSON is readable by every release and is the default
+	EntryFormatJSON EntryFormat = "json"
+	// EntryFormatBinary is smaller, supports compression and is readable by releases
+	// that ship the binary codec. Enable it once no replica sharing the level is older.
+	EntryFormatBinary EntryFormat = "binary"
+)
+
+// Validate rejects unknown formats; empty selects the default
+func (f EntryFormat) Validate() error {
+	switch f {
+	case "", EntryFormatJSON, EntryFormatBinary:
+		return nil
+	default:
+		return fmt.Errorf("invalid entry format '%s': must be one of 'json', 'binary'", f)
+	}
+}
+
+// UnmarshalYAML implements custom YAML unmarshaling for EntryFormat
+func (f *EntryFormat) UnmarshalYAML(value *yaml.Node) error {
+	var str string
+	if err := value.Decode(&str); err != nil {
+		return err
+	}
+	*f = EntryFormat(str)
+	return f.Validate()
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for EntryFormat
+func (f *EntryFormat) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	*f = EntryFormat(str)
+	return f.Validate()
+}
+
+// CompressionAlgorithm represents the algorithm used to compress cache values
+type CompressionAlgorithm string
+
+const (
+	CompressionNone   CompressionAlgorithm = "none"
+	CompressionGzip   CompressionAlgorithm = "gzip"
+	CompressionSnappy CompressionAlgorithm = "snappy"
+	CompressionZstd   CompressionAlgorithm = "zstd"
+)
+
+// Validate rejects unknown algorithms; empty selects the default
+func (a CompressionAlgorithm) Validate() error {
+	switch a {
+	case "", CompressionNone, CompressionGzip, CompressionSnappy, CompressionZstd:
+		return nil
+	default:
+		return fmt.Errorf("invalid compression algorithm '%s': must be one of 'none', 'gzip', 'snappy', 'zstd'", a)
+	}
+}
+
+// UnmarshalYAML implements custom YAML unmarshaling for CompressionAlgorithm
+func (a *CompressionAlgorithm) UnmarshalYAML(value *yaml.Node) error {
+	var str string
+	if err := value.Decode(&str); err != nil {
+		return err
+	}
+	*a = CompressionAlgorithm(str)
+	return a.Validate()
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for CompressionAlgorithm
+func (a *CompressionAlgorithm) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	*a = CompressionAlgorithm(str)
+	return a.Validate()
+}
+
+// CompressionConfig represents per-level value compression settings
+type CompressionConfig struct {
+	Algorithm           CompressionAlgorithm `yaml:"algorithm" json:"algorithm"`
+	Threshold           int                  `yaml:"threshold" json:"threshold"`                         // minimum value size in bytes to compress
+	MaxDecompressedSize int                  `yaml:"max_decompressed_size" json:"max_decompressed_size"` // bytes a read entry may decompress to
+}
+
+func (c *CompressionConfig) ApplyDefaults() {
+	if c.Algorithm == "" {
+		c.Algorithm = CompressionNone
+	}
+	if c.Threshold == 0 {
+		c.Threshold = 1024
+	}
+	if c.MaxDecompressedSize == 0 {
+		c.MaxDecompressedSize = 64 * 1024 * 1024
+	}
+}
+
+// Validate rejects unknown algorithms and negative sizes
+func (c CompressionConfig) Validate() error {
+	if err := c.Algorithm.Validate(); err != nil {
+		return err
+	}
+	if c.Threshold < 0 {
+		return fmt.Errorf("invalid compression threshold %d: must be positive", c.Threshold)
+	}
+	if c.MaxDecompressedSize < 0 {
+		return fmt.Errorf("invalid max decompressed size %d: must be positive", c.MaxDecompressedSize)
+	}
+	return nil
 }
 
 type MultiCacheConfig struct {
-	EnablePropagation bool `yaml:"enable_propagation" json:"enable_propagation"`
+	EnablePropagation bool          `yaml:"enable_propagation" json:"enable_propagation"`
+	Levels            []LevelPolicy `yaml:"levels" json:"levels"` // per-level policies, in level order
+}
+
+// LevelPolicy restricts which entries are written to one level of a multi-level cache,
+// whether by Set, SetNegative, SetMany, SetWithTags or propagation from a later level,
+// and which reads are served by it. Deletes are not affected. The zero value reads and
+// writes every entry.
+// MaxEntrySize is checked against the encoded entry by levels implementing
+// SizeLimitedCache, and against the raw value or error payload otherwise.
+type LevelPolicy struct {
+	ReadOnly           bool               `yaml:"read_only" json:"read_only"`                         // never written
+	SkipCacheTypes     []models.CacheType `yaml:"skip_cache_types" json:"skip_cache_types"`           // cache types not written
+	MaxEntrySize       int                `yaml:"max_entry_size" json:"max_entry_size"`               // bytes, 0 for no limit
+	WriteOnly          bool               `yaml:"write_only" json:"write_only"`                       // never read
+	SkipReadCacheTypes []models.CacheType `yaml:"skip_read_cache_types" json:"skip_read_cache_types"` // cache types not read
+}
+
+// Accepts reports whether an entry of size bytes for cacheType may be written to the
+// level. An empty cacheType is only checked against ReadOnly and MaxEntrySize.
+func (p LevelPolicy) Accepts(cacheType models.CacheType, size int) bool {
+	if p.ReadOnly {
+		return false
+	}
+	if p.MaxEntrySize > 0 && size > p.MaxEntrySize {
+		return false
+	}
+	return cacheType == "" || !slices.Contains(p.SkipCacheTypes, cacheType)
+}
+
+// Reads reports whether reads for cacheType may be served by the level. An empty
+// cacheType is only checked against WriteOnly.
+func (p LevelPolicy) Reads(cacheType models.CacheType) bool {
+	if p.WriteOnly {
+		return false
+	}
+	return cacheType == "" || !slices.Contains(p.SkipReadCacheTypes, cacheType)
+}
+
+// RevalidateConfig represents stale-while-revalidate background refresh settings, passed
+// to loader.WithStaleWhileRevalidate. Zero values are replaced by ApplyDefaults.
+type RevalidateConfig struct {
+	Enabled   bool          `yaml:"enabled" json:"enabled"`
+	Workers   int           `yaml:"workers" json:"workers"`       // concurrent background refreshes
+	QueueSize int           `yaml:"queue_size" json:"queue_size"` // pending refreshes before new ones are dropped
+	Timeout   time.Duration `yaml:"timeout" json:"timeout"`       // per-refresh upstream timeout
+}
+
+func (c *RevalidateConfig) ApplyDefaults() {
+	if c.Workers == 0 {
+		c.Workers = 4
+	}
+	if c.QueueSize == 0 {
+		c.QueueSize = 1000
+	}
+	if c.Timeout == 0 {
+		c.Timeout = 10 * time.Second
+	}
+}
+
+// Validate rejects negative workers, queue size and timeout
+func (c RevalidateConfig) Validate() error {
+	if c.Workers < 0 {
+		return fmt.Errorf("invalid stale-while-revalidate workers %d: must be positive", c.Workers)
+	}
+	if c.QueueSize < 0 {
+		return fmt.Errorf("invalid stale-while-revalidate queue size %d: must be positive", c.QueueSize)
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("invalid stale-while-revalidate timeout %v: must be positive", c.Timeout)
+	}
+	return nil
+}
+
+// UnmarshalYAML implements custom YAML unmarshaling for RevalidateConfig, rejecting
+// invalid values when the config is loaded
+func (c *RevalidateConfig) UnmarshalYAML(value *yaml.Node) error {
+	type plain RevalidateConfig
+	if err := value.Decode((*plain)(c)); err != nil {
+		return err
+	}
+	return c.Validate()
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for RevalidateConfig, rejecting
+// invalid values when the config is loaded
+func (c *RevalidateConfig) UnmarshalJSON(data []byte) error {
+	type plain RevalidateConfig
+	if err := json.Unmarshal(data, (*plain)(c)); err != nil {
+		return err
+	}
+	return c.Validate()
+}
+
+// KeyBuilderConfig represents cache key builder settings
+type KeyBuilderConfig struct {
+	Namespace    string `yaml:"namespace" json:"namespace"`           // e.g. the proxy family sharing an L2
+	Version      string `yaml:"version" json:"version"`               // bump to invalidate every key at once
+	MaxKeyLength int    `yaml:"max_key_length" json:"max_key_length"` // longer keys have their params hashed
+}
+
+func (c *KeyBuilderConfig) ApplyDefaults() {
+	if c.MaxKeyLength == 0 {
+		c.MaxKeyLength = 256
+	}
 }
@@ -1,8 +1,13 @@
 package cache
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/status-im/proxy-common/models"
 )
 
 func TestBigCacheConfig_ApplyDefaults(t *testing.T) {
@@ -276,6 +281,110 @@ func TestKeyDBConfig_ApplyDefaults(t *testing.T) {
 	})
 }
 
+func TestCircuitBreakerConfig_ApplyDefaults(t *testing.T) {
+	config := &KeyDBConfig{}
+	config.ApplyDefaults()
+
+	want := CircuitBreakerConfig{
+		Window:        10 * time.Second,
+		MinRequests:   20,
+		ErrorRate:     0.5,
+		SlowThreshold: 250 * time.Millisecond,
+		SlowRate:      0.5,
+		OpenTimeout:   5 * time.Second,
+	}
+	if config.CircuitBreaker != want {
+		t.Errorf("expected %+v, got %+v", want, config.CircuitBreaker)
+	}
+
+	custom := CircuitBreakerConfig{Enabled: true, MinRequests: 5, ErrorRate: 0.2}
+	custom.ApplyDefaults()
+	if !custom.Enabled || custom.MinRequests != 5 || custom.ErrorRate != 0.2 {
+		t.Errorf("expected custom values to be kept, got %+v", custom)
+	}
+}
+
+func TestWriteBehindConfig_ApplyDefaults(t *testing.T) {
+	config := &KeyDBConfig{}
+	config.ApplyDefaults()
+
+	want := WriteBehindConfig{
+		QueueSize:     10000,
+		BatchSize:     100,
+		FlushInterval: 10 * time.Millisecond,
+		Overflow:      OverflowDropOldest,
+	}
+	if config.WriteBehind != want {
+		t.Errorf("expected %+v, got %+v", want, config.WriteBehind)
+	}
+}
+
+func TestOverflowPolicy_UnmarshalYAML(t *testing.T) {
+	for _, valid := range []string{"drop_oldest", "drop_newest"} {
+		t.Run(valid, func(t *testing.T) {
+			var config WriteBehindConfig
+			if err := yaml.Unmarshal([]byte("overflow: "+valid), &config); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if config.Overflow != OverflowPolicy(valid) {
+				t.Errorf("expected Overflow to be '%s', got '%s'", valid, config.Overflow)
+			}
+		})
+	}
+
+	t.Run("rejects unknown policy", func(t *testing.T) {
+		var config WriteBehindConfig
+		if err := yaml.Unmarshal([]byte("overflow: block"), &config); err == nil {
+			t.Error("expected error for unknown overflow policy")
+		}
+	})
+}
+
+func TestWriteBehindConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		yamlData string
+		jsonData string
+	}{
+		{name: "negative queue size", yamlData: "queue_size: -1", jsonData: `{"queue_size": -1}`},
+		{name: "negative batch size", yamlData: "batch_size: -1", jsonData: `{"batch_size": -1}`},
+		{name: "negative flush interval", yamlData: "flush_interval: -1ms", jsonData: `{"flush_interval": -1}`},
+		{name: "unknown overflow policy", yamlData: "overflow: block", jsonData: `{"overflow": "block"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config WriteBehindConfig
+			if err := yaml.Unmarshal([]byte(tt.yamlData), &config); err == nil {
+				t.Error("expected YAML error")
+			}
+			if err := json.Unmarshal([]byte(tt.jsonData), &config); err == nil {
+				t.Error("expected JSON error")
+			}
+		})
+	}
+
+	var config WriteBehindConfig
+	if err := yaml.Unmarshal([]byte("enabled: true\nbatch_size: 10"), &config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !config.Enabled || config.BatchSize != 10 {
+		t.Errorf("unexpected config: %+v", config)
+	}
+}
+
+func TestCircuitState_String(t *testing.T) {
+	tests := map[CircuitState]string{
+		CircuitClosed:   "closed",
+		CircuitHalfOpen: "half_open",
+		CircuitOpen:     "open",
+	}
+	for state, want := range tests {
+		if got := state.String(); got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	}
+}
+
 func TestMultiCacheConfig(t *testing.T) {
 	t.Run("EnablePropagation can be true", func(t *testing.T) {
 		config := MultiCacheConfig{EnablePropagation: true}
@@ -291,3 +400,417 @@ func TestMultiCacheConfig(t *testing.T) {
 		}
 	})
 }
+
+func TestLevelPolicy_Accepts(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    LevelPolicy
+		cacheType models.CacheType
+		size      int
+		want      bool
+	}{
+		{name: "zero policy", policy: LevelPolicy{}, cacheType: models.CacheTypeMinimal, size: 1 << 20, want: true},
+		{name: "read only", policy: LevelPolicy{ReadOnly: true}, cacheType: models.CacheTypeShort, size: 1, want: false},
+		{name: "skipped type", policy: LevelPolicy{SkipCacheTypes: []models.CacheType{models.CacheTypeMinimal}}, cacheType: models.CacheTypeMinimal, want: false},
+		{name: "other type", policy: LevelPolicy{SkipCacheTypes: []models.CacheType{models.CacheTypeMinimal}}, cacheType: models.CacheTypePermanent, want: true},
+		{name: "no type", policy: LevelPolicy{SkipCacheTypes: []models.CacheType{models.CacheTypeMinimal}}, want: true},
+		{name: "at size limit", policy: LevelPolicy{MaxEntrySize: 10}, size: 10, want: true},
+		{name: "above size limit", policy: LevelPolicy{MaxEntrySize: 10}, size: 11, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Accepts(tt.cacheType, tt.size); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLevelPolicy_Reads(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    LevelPolicy
+		cacheType models.CacheType
+		want      bool
+	}{
+		{name: "zero policy", policy: LevelPolicy{}, cacheType: models.CacheTypeMinimal, want: true},
+		{name: "read only", policy: LevelPolicy{ReadOnly: true}, cacheType: models.CacheTypeShort, want: true},
+		{name: "write only", policy: LevelPolicy{WriteOnly: true}, cacheType: models.CacheTypeShort, want: false},
+		{name: "skipped type", policy: LevelPolicy{SkipReadCacheTypes: []models.CacheType{models.CacheTypeMinimal}}, cacheType: models.CacheTypeMinimal, want: false},
+		{name: "other type", policy: LevelPolicy{SkipReadCacheTypes: []models.CacheType{models.CacheTypeMinimal}}, cacheType: models.CacheTypePermanent, want: true},
+		{name: "no type", policy: LevelPolicy{SkipReadCacheTypes: []models.CacheType{models.CacheTypeMinimal}}, want: true},
+		{name: "write skip does not affect reads", policy: LevelPolicy{SkipCacheTypes: []models.CacheType{models.CacheTypeMinimal}}, cacheType: models.CacheTypeMinimal, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Reads(tt.cacheType); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMultiCacheConfig_LevelsYAML(t *testing.T) {
+	var config MultiCacheConfig
+	data := `
+levels:
+  - max_entry_size: 65536
+    skip_cache_types: [permanent]
+  - read_only: true
+    skip_cache_types: [minimal]
+    skip_read_cache_types: [short]
+`
+	if err := yaml.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(config.Levels) != 2 {
+		t.Fatalf("expected 2 levels, got %d", len(config.Levels))
+	}
+	if config.Levels[0].MaxEntrySize != 65536 || config.Levels[0].SkipCacheTypes[0] != models.CacheTypePermanent {
+		t.Errorf("unexpected L1 policy: %+v", config.Levels[0])
+	}
+	if !config.Levels[1].ReadOnly || config.Levels[1].SkipCacheTypes[0] != models.CacheTypeMinimal ||
+		config.Levels[1].SkipReadCacheTypes[0] != models.CacheTypeShort {
+		t.Errorf("unexpected L2 policy: %+v", config.Levels[1])
+	}
+
+	if err := yaml.Unmarshal([]byte("levels:\n  - skip_cache_types: [forever]\n"), &config); err == nil {
+		t.Error("expected error for invalid cache type")
+	}
+}
+
+func TestRevalidateConfig_ApplyDefaults(t *testing.T) {
+	t.Run("applies default values to zero config", func(t *testing.T) {
+		config := &RevalidateConfig{}
+		config.ApplyDefaults()
+
+		if config.Workers != 4 {
+			t.Errorf("expected Workers to be 4, got %d", config.Workers)
+		}
+		if config.QueueSize != 1000 {
+			t.Errorf("expected QueueSize to be 1000, got %d", config.QueueSize)
+		}
+		if config.Timeout != 10*time.Second {
+			t.Errorf("expected Timeout to be 10s, got %v", config.Timeout)
+		}
+	})
+
+	t.Run("does not override non-zero values", func(t *testing.T) {
+		config := &RevalidateConfig{
+			Enabled:   true,
+			Workers:   8,
+			QueueSize: 50,
+			Timeout:   time.Second,
+		}
+		config.ApplyDefaults()
+
+		if !config.Enabled {
+			t.Error("expected Enabled to remain true")
+		}
+		if config.Workers != 8 {
+			t.Errorf("expected Workers to remain 8, got %d", config.Workers)
+		}
+		if config.QueueSize != 50 {
+			t.Errorf("expected QueueSize to remain 50, got %d", config.QueueSize)
+		}
+		if config.Timeout != time.Second {
+			t.Errorf("expected Timeout to remain 1s, got %v", config.Timeout)
+		}
+	})
+}
+
+func TestRevalidateConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		yamlData string
+		jsonData string
+	}{
+		{name: "negative workers", yamlData: "workers: -1", jsonData: `{"workers": -1}`},
+		{name: "negative queue size", yamlData: "queue_size: -5", jsonData: `{"queue_size": -5}`},
+		{name: "negative timeout", yamlData: "timeout: -1s", jsonData: `{"timeout": -1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config RevalidateConfig
+			if err := yaml.Unmarshal([]byte(tt.yamlData), &config); err == nil {
+				t.Error("expected YAML error")
+			}
+			if err := json.Unmarshal([]byte(tt.jsonData), &config); err == nil {
+				t.Error("expected JSON error")
+			}
+		})
+	}
+
+	var config RevalidateConfig
+	if err := yaml.Unmarshal([]byte("enabled: true\nworkers: 2"), &config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !config.Enabled || config.Workers != 2 {
+		t.Errorf("unexpected config: %+v", config)
+	}
+}
+
+func TestCompressionConfig_ApplyDefaults(t *testing.T) {
+	t.Run("applies default values to zero config", func(t *testing.T) {
+		config := &CompressionConfig{}
+		config.ApplyDefaults()
+
+		if config.Algorithm != CompressionNone {
+			t.Errorf("expected Algorithm to be 'none', got '%s'", config.Algorithm)
+		}
+		if config.Threshold != 1024 {
+			t.Errorf("expected Threshold to be 1024, got %d", config.Threshold)
+		}
+	})
+
+	t.Run("applied through level configs", func(t *testing.T) {
+		bigCacheConfig := &BigCacheConfig{Compression: CompressionConfig{Algorithm: CompressionZstd}}
+		bigCacheConfig.ApplyDefaults()
+		keyDBConfig := &KeyDBConfig{Compression: CompressionConfig{Threshold: 4096}}
+		keyDBConfig.ApplyDefaults()
+
+		if bigCacheConfig.Compression.Algorithm != CompressionZstd || bigCacheConfig.Compression.Threshold != 1024 {
+			t.Errorf("unexpected L1 compression config %+v", bigCacheConfig.Compression)
+		}
+		if keyDBConfig.Compression.Algorithm != CompressionNone || keyDBConfig.Compression.Threshold != 4096 {
+			t.Errorf("unexpected L2 compression config %+v", keyDBConfig.Compression)
+		}
+	})
+}
+
+func TestCompressionAlgorithm_UnmarshalYAML(t *testing.T) {
+	for _, valid := range []string{"none", "gzip", "snappy", "zstd"} {
+		t.Run(valid, func(t *testing.T) {
+			var config CompressionConfig
+			if err := yaml.Unmarshal([]byte("algorithm: "+valid), &config); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if config.Algorithm != CompressionAlgorithm(valid) {
+				t.Errorf("expected Algorithm to be '%s', got '%s'", valid, config.Algorithm)
+			}
+		})
+	}
+
+	t.Run("rejects unknown algorithm", func(t *testing.T) {
+		var config CompressionConfig
+		if err := yaml.Unmarshal([]byte("algorithm: lz4"), &config); err == nil {
+			t.Error("expected error for unknown algorithm")
+		}
+		if err := json.Unmarshal([]byte(`{"algorithm":"lz4"}`), &config); err == nil {
+			t.Error("expected error for unknown algorithm in JSON")
+		}
+	})
+}
+
+func TestCompressionConfig_Validate(t *testing.T) {
+	valid := CompressionConfig{}
+	valid.ApplyDefaults()
+	if err := valid.Validate(); err != nil {
+		t.Errorf("unexpected error for default config: %v", err)
+	}
+	if valid.MaxDecompressedSize != 64*1024*1024 {
+		t.Errorf("expected MaxDecompressedSize to be 64MiB, got %d", valid.MaxDecompressedSize)
+	}
+
+	for name, config := range map[string]CompressionConfig{
+		"unknown algorithm":              {Algorithm: "lz4"},
+		"negative threshold":             {Threshold: -1},
+		"negative max decompressed size": {MaxDecompressedSize: -1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := config.Validate(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestEntryFormat(t *testing.T) {
+	t.Run("defaults to json", func(t *testing.T) {
+		bigCacheConfig := &BigCacheConfig{}
+		bigCacheConfig.ApplyDefaults()
+		keyDBConfig := &KeyDBConfig{}
+		keyDBConfig.ApplyDefaults()
+
+		if bigCacheConfig.Format != EntryFormatJSON || keyDBConfig.Format != EntryFormatJSON {
+			t.Errorf("expected json format, got '%s' and '%s'", bigCacheConfig.Format, keyDBConfig.Format)
+		}
+	})
+
+	for _, valid := range []string{"json", "binary"} {
+		t.Run(valid, func(t *testing.T) {
+			var config KeyDBConfig
+			if err := yaml.Unmarshal([]byte("format: "+valid), &config); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if config.Format != EntryFormat(valid) {
+				t.Errorf("expected Format to be '%s', got '%s'", valid, config.Format)
+			}
+		})
+	}
+
+	t.Run("rejects unknown format", func(t *testing.T) {
+		var config KeyDBConfig
+		if err := yaml.Unmarshal([]byte("format: msgpack"), &config); err == nil {
+			t.Error("expected error for unknown format")
+		}
+		if err := json.Unmarshal([]byte(`{"format":"msgpack"}`), &config); err == nil {
+			t.Error("expected error for unknown format in JSON")
+		}
+	})
+}
+
+func TestCacheSettings_ApplyTTL(t *testing.T) {
+	settings := CacheSettings{DefaultTTL: time.Hour, MaxTTL: 24 * time.Hour, TTLScale: 1}
+
+	tests := []struct {
+		name       string
+		settings   CacheSettings
+		ttl        models.TTL
+		wantTTL    models.TTL
+		wantReason string
+	}{
+		{
+			settings: settings,
+			name:     "within limits",
+			ttl:      models.TTL{Fresh: time.Minute, Stale: time.Minute},
+			wantTTL:  models.TTL{Fresh: time.Minute, Stale: time.Minute},
+		},
+		{
+			settings:   settings,
+			name:       "zero ttl gets default",
+			ttl:        models.TTL{},
+			wantTTL:    models.TTL{Fresh: time.Hour},
+			wantReason: TTLAdjustmentDefault,
+		},
+		{
+			settings:   settings,
+			name:       "negative ttl gets default",
+			ttl:        models.TTL{Fresh: -time.Second},
+			wantTTL:    models.TTL{Fresh: time.Hour},
+			wantReason: TTLAdjustmentDefault,
+		},
+		{
+			settings:   settings,
+			name:       "stale period clamped first",
+			ttl:        models.TTL{Fresh: 20 * time.Hour, Stale: 10 * time.Hour},
+			wantTTL:    models.TTL{Fresh: 20 * time.Hour, Stale: 4 * time.Hour},
+			wantReason: TTLAdjustmentClamped,
+		},
+		{
+			settings:   settings,
+			name:       "fresh period clamped",
+			ttl:        models.TTL{Fresh: 48 * time.Hour, Stale: time.Hour},
+			wantTTL:    models.TTL{Fresh: 24 * time.Hour},
+			wantReason: TTLAdjustmentClamped,
+		},
+		{
+			name:     "scaled before limits",
+			settings: CacheSettings{DefaultTTL: time.Hour, MaxTTL: time.Hour, TTLScale: 2},
+			ttl:      models.TTL{Fresh: 20 * time.Minute, Stale: 5 * time.Minute},
+			wantTTL:  models.TTL{Fresh: 40 * time.Minute, Stale: 10 * time.Minute},
+		},
+		{
+			name:       "scaled past max",
+			settings:   CacheSettings{DefaultTTL: time.Hour, MaxTTL: time.Hour, TTLScale: 2},
+			ttl:        models.TTL{Fresh: 40 * time.Minute},
+			wantTTL:    models.TTL{Fresh: time.Hour},
+			wantReason: TTLAdjustmentClamped,
+		},
+		{
+			name:       "default above max clamped",
+			settings:   CacheSettings{DefaultTTL: 48 * time.Hour, MaxTTL: 24 * time.Hour},
+			ttl:        models.TTL{},
+			wantTTL:    models.TTL{Fresh: 24 * time.Hour},
+			wantReason: TTLAdjustmentClamped,
+		},
+		{
+			name:     "no limits configured",
+			settings: CacheSettings{},
+			ttl:      models.TTL{},
+			wantTTL:  models.TTL{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ttl, reason := tt.settings.ApplyTTL(tt.ttl)
+
+			if ttl != tt.wantTTL {
+				t.Errorf("expected TTL %+v, got %+v", tt.wantTTL, ttl)
+			}
+			if reason != tt.wantReason {
+				t.Errorf("expected reason %q, got %q", tt.wantReason, reason)
+			}
+		})
+	}
+}
+
+func TestScaleTTL(t *testing.T) {
+	ttl := models.TTL{Fresh: time.Minute, Stale: 2 * time.Minute}
+
+	if got := ScaleTTL(ttl, 0); got != ttl {
+		t.Errorf("expected zero scale to leave TTL unchanged, got %+v", got)
+	}
+	if got := ScaleTTL(ttl, 0.5); got != (models.TTL{Fresh: 30 * time.Second, Stale: time.Minute}) {
+		t.Errorf("expected TTL to be halved, got %+v", got)
+	}
+}
+
+func TestConfig_TTLScaleDefaults(t *testing.T) {
+	bigCacheConfig := &BigCacheConfig{}
+	bigCacheConfig.ApplyDefaults()
+	if bigCacheConfig.TTLScale != 1 {
+		t.Errorf("expected BigCache TTLScale to be 1, got %f", bigCacheConfig.TTLScale)
+	}
+
+	keyDBConfig := &KeyDBConfig{}
+	keyDBConfig.ApplyDefaults()
+	if keyDBConfig.Cache.TTLScale != 1 {
+		t.Errorf("expected KeyDB TTLScale to be 1, got %f", keyDBConfig.Cache.TTLScale)
+	}
+}
+
+func TestTTLJitterConfig_Apply(t *testing.T) {
+	ttl := models.TTL{Fresh: 100 * time.Second, Stale: 50 * time.Second}
+
+	t.Run("disabled", func(t *testing.T) {
+		if got := (TTLJitterConfig{}).Apply("key", ttl); got != ttl {
+			t.Errorf("expected TTL to be unchanged, got %+v", got)
+		}
+	})
+
+	t.Run("shortens within percent", func(t *testing.T) {
+		jitter := TTLJitterConfig{Percent: 10}
+		for i := 0; i < 100; i++ {
+			got := jitter.Apply("key", ttl)
+			if got.Fresh > ttl.Fresh || got.Fresh < 90*time.Second {
+				t.Fatalf("expected fresh within [90s, 100s], got %v", got.Fresh)
+			}
+			if got.Stale > ttl.Stale || got.Stale < 45*time.Second {
+				t.Fatalf("expected stale within [45s, 50s], got %v", got.Stale)
+			}
+		}
+	})
+
+	t.Run("deterministic per key", func(t *testing.T) {
+		jitter := TTLJitterConfig{Percent: 50, Deterministic: true}
+
+		first := jitter.Apply("key-a", ttl)
+		if got := jitter.Apply("key-a", ttl); got != first {
+			t.Errorf("expected the same TTL for the same key, got %+v and %+v", first, got)
+		}
+		if got := jitter.Apply("key-b", ttl); got == first {
+			t.Errorf("expected different keys to get different TTLs, both got %+v", got)
+		}
+	})
+
+	t.Run("percent capped at 100", func(t *testing.T) {
+		got := TTLJitterConfig{Percent: 500, Deterministic: true}.Apply("key", ttl)
+		if got.Fresh < 0 || got.Stale < 0 {
+			t.Errorf("expected non-negative TTL, got %+v", got)
+		}
+	})
+}
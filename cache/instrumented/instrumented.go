@@ -0,0 +1,495 @@
+package instrumented
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/clock"
+	"github.com/status-im/proxy-common/models"
+)
+
+// Ensure Cache implements cache.LevelAwareCache, cache.NegativeCache and the batch and
+// tagged interfaces of the caches it wraps
+var _ cache.LevelAwareCache = (*Cache)(nil)
+var _ cache.BindableCache = (*Cache)(nil)
+var _ cache.NegativeCache = (*Cache)(nil)
+var _ cache.LevelAwareBatchCache = (*Cache)(nil)
+var _ cache.NegativeBatchCache = (*Cache)(nil)
+var _ cache.TaggedCache = (*Cache)(nil)
+
+// unknownCacheType labels operations made without a cache type in their request labels
+const unknownCacheType = "unknown"
+
+// Cache wraps a LevelAwareCache and records hits, negative hits, misses, item age, bytes
+// read and written and operation durations for every call, and optionally a span per
+// call. Labels and the parent span come from the request context bound with
+// WithContext; calls made on Cache itself carry no labels and start root spans.
+//
+// Batch and tagged operations are passed through when the wrapped cache implements
+// cache.BatchCache, cache.LevelAwareBatchCache or cache.TaggedCache, such as
+// multi.MultiCache, and otherwise fall back to one call per key.
+type Cache struct {
+	cache   cache.LevelAwareCache
+	metrics cache.MetricsRecorder
+	clock   clock.Clock
+	tracer  trace.Tracer
+	level   string
+}
+
+// Option is a functional option for configuring Cache
+type Option func(*Cache)
+
+// WithClock sets the clock used to compute item age on hits
+func WithClock(c clock.Clock) Option {
+	return func(ic *Cache) {
+		ic.clock = c
+	}
+}
+
+// WithTracer sets the tracer used to create a span for every get, set and delete. Read
+// spans carry the level that served the entry, so they stand in for the per-level spans
+// of multi.MultiCacheV2.
+func WithTracer(tracer trace.Tracer) Option {
+	return func(ic *Cache) {
+		ic.tracer = tracer
+	}
+}
+
+// WithLevel sets the level label for sets and operation durations, "multi" by default.
+// Hits are always labelled with the level reported by the wrapped cache.
+func WithLevel(level string) Option {
+	return func(ic *Cache) {
+		ic.level = level
+	}
+}
+
+// New wraps c so that every operation is reported to metrics
+func New(c cache.LevelAwareCache, metrics cache.MetricsRecorder, opts ...Option) *Cache {
+	ic := &Cache{
+		cache:   c,
+		metrics: metrics,
+		clock:   clock.Real{},
+		tracer:  noop.NewTracerProvider().Tracer(""),
+		level:   "multi",
+	}
+
+	for _, opt := range opts {
+		opt(ic)
+	}
+
+	return ic
+}
+
+// WithContext returns a view of the cache whose operations are labelled with the
+// RequestLabels stored in ctx, and whose spans are children of the span in ctx. If the wrapped cache is a cache.BindableCache, such as
+// multi.MultiCache applying per-cache-type level policies, the view wraps the cache it
+// returns for ctx.
+func (ic *Cache) WithContext(ctx context.Context) cache.LevelAwareCache {
+	inner := ic
+	if binder, ok := ic.cache.(cache.BindableCache); ok {
+		bound := *ic
+		bound.cache = binder.WithContext(ctx)
+		inner = &bound
+	}
+	return &boundCache{Cache: inner, ctx: ctx}
+}
+
+// ForCacheType returns a view of the cache whose operations are labelled with cacheType
+// alone, as WithContext does for a context carrying only that cache type
+func (ic *Cache) ForCacheType(cacheType models.CacheType) cache.LevelAwareCache {
+	return ic.WithContext(cache.ContextWithRequestLabels(context.Background(), cache.RequestLabels{CacheType: cacheType}))
+}
+
+func (ic *Cache) Get(key string) (*models.CacheEntry, bool) {
+	return ic.get(context.Background(), key)
+}
+
+func (ic *Cache) GetStale(key string) (*models.CacheEntry, bool) {
+	return ic.getStale(context.Background(), key)
+}
+
+// GetEntry retrieves a positive or negative entry
+func (ic *Cache) GetEntry(key string) (*models.CacheEntry, bool) {
+	return ic.getEntry(context.Background(), key)
+}
+
+// GetStaleEntry retrieves a positive or negative entry regardless of freshness
+func (ic *Cache) GetStaleEntry(key string) (*models.CacheEntry, bool) {
+	return ic.getStaleEntry(context.Background(), key)
+}
+
+func (ic *Cache) Set(key string, val []byte, ttl models.TTL) {
+	ic.set(context.Background(), key, val, ttl)
+}
+
+// SetNegative stores a negative entry if the wrapped cache supports them, and otherwise
+// deletes the key
+func (ic *Cache) SetNegative(key string, errPayload []byte, ttl models.TTL) {
+	ic.setNegative(context.Background(), key, errPayload, ttl)
+}
+
+func (ic *Cache) Delete(key string) {
+	ic.delete(context.Background(), key)
+}
+
+func (ic *Cache) GetWithLevel(key string) *models.CacheResult {
+	return ic.getWithLevel(context.Background(), key)
+}
+
+func (ic *Cache) GetStaleWithLevel(key string) *models.CacheResult {
+	return ic.getStaleWithLevel(context.Background(), key)
+}
+
+// GetMany retrieves several values, returning only the keys found
+func (ic *Cache) GetMany(keys []string) map[string]*models.CacheEntry {
+	return ic.getMany(context.Background(), keys)
+}
+
+// GetManyEntries retrieves several positive or negative entries, returning only the keys found
+func (ic *Cache) GetManyEntries(keys []string) map[string]*models.CacheEntry {
+	return ic.getManyEntries(context.Background(), keys)
+}
+
+// GetManyWithLevel retrieves several values with per-key level information
+func (ic *Cache) GetManyWithLevel(keys []string) map[string]*models.CacheResult {
+	return ic.getManyWithLevel(context.Background(), keys)
+}
+
+// SetMany stores several values
+func (ic *Cache) SetMany(items []cache.BatchItem) {
+	ic.setMany(context.Background(), items)
+}
+
+// SetWithTags stores a value with tags if the wrapped cache supports them, and
+// otherwise stores it untagged
+func (ic *Cache) SetWithTags(key string, val []byte, ttl models.TTL, tags ...string) {
+	ic.setWithTags(context.Background(), key, val, ttl, tags...)
+}
+
+// InvalidateTag removes every entry stored with tag, returning the removed keys. It
+// removes nothing if the wrapped cache does not support tags.
+func (ic *Cache) InvalidateTag(tag string) []string {
+	return ic.invalidate(context.Background(), "invalidate_tag", func(tc cache.TaggedCache) []string {
+		return tc.InvalidateTag(tag)
+	})
+}
+
+// InvalidatePrefix removes every entry whose key starts with prefix, returning the
+// removed keys. It removes nothing if the wrapped cache does not support tags.
+func (ic *Cache) InvalidatePrefix(prefix string) []string {
+	return ic.invalidate(context.Background(), "invalidate_prefix", func(tc cache.TaggedCache) []string {
+		return tc.InvalidatePrefix(prefix)
+	})
+}
+
+func (ic *Cache) get(ctx context.Context, key string) (*models.CacheEntry, bool) {
+	if result := ic.getWithLevel(ctx, key); result.Found {
+		return result.Entry, true
+	}
+	return nil, false
+}
+
+func (ic *Cache) getStale(ctx context.Context, key string) (*models.CacheEntry, bool) {
+	if result := ic.getStaleWithLevel(ctx, key); result.Found {
+		return result.Entry, true
+	}
+	return nil, false
+}
+
+func (ic *Cache) getEntry(ctx context.Context, key string) (*models.CacheEntry, bool) {
+	if result := ic.getWithLevel(ctx, key); result.Found || result.IsNegative() {
+		return result.Entry, true
+	}
+	return nil, false
+}
+
+func (ic *Cache) getStaleEntry(ctx context.Context, key string) (*models.CacheEntry, bool) {
+	if result := ic.getStaleWithLevel(ctx, key); result.Found || result.IsNegative() {
+		return result.Entry, true
+	}
+	return nil, false
+}
+
+func (ic *Cache) getWithLevel(ctx context.Context, key string) *models.CacheResult {
+	_, span := ic.startSpan(ctx, "get")
+	stop := ic.metrics.TimeCacheOperation("get", ic.level)
+	result := ic.cache.GetWithLevel(key)
+	stop()
+	endReadSpan(span, result)
+
+	ic.recordResult(result, cache.RequestLabelsFromContext(ctx))
+	return result
+}
+
+func (ic *Cache) getStaleWithLevel(ctx context.Context, key string) *models.CacheResult {
+	_, span := ic.startSpan(ctx, "get_stale")
+	stop := ic.metrics.TimeCacheOperation("get_stale", ic.level)
+	result := ic.cache.GetStaleWithLevel(key)
+	stop()
+	endReadSpan(span, result)
+
+	ic.recordResult(result, cache.RequestLabelsFromContext(ctx))
+	return result
+}
+
+func (ic *Cache) set(ctx context.Context, key string, val []byte, ttl models.TTL) {
+	_, span := ic.startSpan(ctx, "set")
+	stop := ic.metrics.TimeCacheOperation("set", ic.level)
+	ic.cache.Set(key, val, ttl)
+	stop()
+	span.End()
+
+	labels := cache.RequestLabelsFromContext(ctx)
+	ic.metrics.RecordCacheSet(ic.level, cacheTypeLabel(labels), labels.Chain, labels.Network, len(val))
+}
+
+func (ic *Cache) setNegative(ctx context.Context, key string, errPayload []byte, ttl models.TTL) {
+	_, span := ic.startSpan(ctx, "set_negative")
+	stop := ic.metrics.TimeCacheOperation("set_negative", ic.level)
+	if nc, ok := ic.cache.(cache.NegativeCache); ok {
+		nc.SetNegative(key, errPayload, ttl)
+	} else {
+		ic.cache.Delete(key)
+	}
+	stop()
+	span.End()
+
+	labels := cache.RequestLabelsFromContext(ctx)
+	ic.metrics.RecordCacheSet(ic.level, cacheTypeLabel(labels), labels.Chain, labels.Network, len(errPayload))
+}
+
+func (ic *Cache) getMany(ctx context.Context, keys []string) map[string]*models.CacheEntry {
+	entries := make(map[string]*models.CacheEntry)
+	for key, result := range ic.getManyWithLevel(ctx, keys) {
+		if result.Found {
+			entries[key] = result.Entry
+		}
+	}
+	return entries
+}
+
+func (ic *Cache) getManyEntries(ctx context.Context, keys []string) map[string]*models.CacheEntry {
+	entries := make(map[string]*models.CacheEntry)
+	for key, result := range ic.getManyWithLevel(ctx, keys) {
+		if result.Found || result.IsNegative() {
+			entries[key] = result.Entry
+		}
+	}
+	return entries
+}
+
+func (ic *Cache) getManyWithLevel(ctx context.Context, keys []string) map[string]*models.CacheResult {
+	_, span := ic.startSpan(ctx, "get_many")
+	stop := ic.metrics.TimeCacheOperation("get_many", ic.level)
+	var results map[string]*models.CacheResult
+	if bc, ok := ic.cache.(cache.LevelAwareBatchCache); ok {
+		results = bc.GetManyWithLevel(keys)
+	} else {
+		results = make(map[string]*models.CacheResult, len(keys))
+		for _, key := range keys {
+			results[key] = ic.cache.GetWithLevel(key)
+		}
+	}
+	stop()
+
+	labels := cache.RequestLabelsFromContext(ctx)
+	hits := 0
+	for _, key := range keys {
+		result := results[key]
+		if result != nil && result.Found {
+			hits++
+		}
+		ic.recordResult(result, labels)
+	}
+	span.SetAttributes(attribute.Int("cache.keys", len(keys)), attribute.Int("cache.hits", hits))
+	span.End()
+
+	return results
+}
+
+func (ic *Cache) setMany(ctx context.Context, items []cache.BatchItem) {
+	_, span := ic.startSpan(ctx, "set_many")
+	stop := ic.metrics.TimeCacheOperation("set_many", ic.level)
+	if bc, ok := ic.cache.(cache.BatchCache); ok {
+		bc.SetMany(items)
+	} else {
+		for _, item := range items {
+			ic.cache.Set(item.Key, item.Val, item.TTL)
+		}
+	}
+	stop()
+	span.SetAttributes(attribute.Int("cache.keys", len(items)))
+	span.End()
+
+	labels := cache.RequestLabelsFromContext(ctx)
+	for _, item := range items {
+		ic.metrics.RecordCacheSet(ic.level, cacheTypeLabel(labels), labels.Chain, labels.Network, len(item.Val))
+	}
+}
+
+func (ic *Cache) setWithTags(ctx context.Context, key string, val []byte, ttl models.TTL, tags ...string) {
+	_, span := ic.startSpan(ctx, "set")
+	stop := ic.metrics.TimeCacheOperation("set", ic.level)
+	if tc, ok := ic.cache.(cache.TaggedCache); ok {
+		tc.SetWithTags(key, val, ttl, tags...)
+	} else {
+		ic.cache.Set(key, val, ttl)
+	}
+	stop()
+	span.End()
+
+	labels := cache.RequestLabelsFromContext(ctx)
+	ic.metrics.RecordCacheSet(ic.level, cacheTypeLabel(labels), labels.Chain, labels.Network, len(val))
+}
+
+// invalidate runs fn on the wrapped cache if it supports tags
+func (ic *Cache) invalidate(ctx context.Context, operation string, fn func(tc cache.TaggedCache) []string) []string {
+	tc, ok := ic.cache.(cache.TaggedCache)
+	if !ok {
+		return nil
+	}
+
+	_, span := ic.startSpan(ctx, operation)
+	stop := ic.metrics.TimeCacheOperation(operation, ic.level)
+	removed := fn(tc)
+	stop()
+	span.SetAttributes(attribute.Int("cache.keys", len(removed)))
+	span.End()
+
+	return removed
+}
+
+func (ic *Cache) delete(ctx context.Context, key string) {
+	_, span := ic.startSpan(ctx, "delete")
+	defer span.End()
+	defer ic.metrics.TimeCacheOperation("delete", ic.level)()
+	ic.cache.Delete(key)
+}
+
+// startSpan starts the span of an operation as a child of the span in ctx, if any
+func (ic *Cache) startSpan(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return ic.tracer.Start(ctx, "cache."+operation, trace.WithAttributes(
+		attribute.String("cache.type", cacheTypeLabel(cache.RequestLabelsFromContext(ctx))),
+	))
+}
+
+// endReadSpan records the level that served a read, and whether it hit, then ends span
+func endReadSpan(span trace.Span, result *models.CacheResult) {
+	if result != nil {
+		span.SetAttributes(
+			attribute.String("cache.level", strings.ToLower(result.Level.String())),
+			attribute.Bool("cache.hit", result.Found),
+		)
+	}
+	span.End()
+}
+
+// recordResult records a read as a hit or negative hit at the level that served it,
+// or as a miss
+func (ic *Cache) recordResult(result *models.CacheResult, labels cache.RequestLabels) {
+	cacheType := cacheTypeLabel(labels)
+
+	if result != nil && result.IsNegative() {
+		level := strings.ToLower(result.Level.String())
+		cache.OptionalMetrics[cache.NegativeCacheMetricsRecorder](ic.metrics).RecordCacheNegativeHit(cacheType, level, labels.Chain, labels.Network, labels.Method)
+		return
+	}
+
+	if result == nil || !result.Found || result.Entry == nil {
+		ic.metrics.RecordCacheMiss(cacheType, labels.Chain, labels.Network, labels.Method)
+		return
+	}
+
+	level := strings.ToLower(result.Level.String())
+
+	age := ic.clock.Now().Sub(result.Entry.CreatedTime())
+	ic.metrics.RecordCacheHit(cacheType, level, labels.Chain, labels.Network, labels.Method, age)
+	ic.metrics.RecordCacheBytesRead(level, cacheType, labels.Chain, labels.Network, len(result.Entry.Data))
+}
+
+// cacheTypeLabel returns the cache type label for labels
+func cacheTypeLabel(labels cache.RequestLabels) string {
+	if labels.CacheType == "" {
+		return unknownCacheType
+	}
+	return string(labels.CacheType)
+}
+
+// boundCache is a view of Cache bound to a single request's context
+type boundCache struct {
+	*Cache
+	ctx context.Context
+}
+
+func (b *boundCache) Get(key string) (*models.CacheEntry, bool) {
+	return b.get(b.ctx, key)
+}
+
+func (b *boundCache) GetStale(key string) (*models.CacheEntry, bool) {
+	return b.getStale(b.ctx, key)
+}
+
+func (b *boundCache) GetEntry(key string) (*models.CacheEntry, bool) {
+	return b.getEntry(b.ctx, key)
+}
+
+func (b *boundCache) GetStaleEntry(key string) (*models.CacheEntry, bool) {
+	return b.getStaleEntry(b.ctx, key)
+}
+
+func (b *boundCache) Set(key string, val []byte, ttl models.TTL) {
+	b.set(b.ctx, key, val, ttl)
+}
+
+func (b *boundCache) SetNegative(key string, errPayload []byte, ttl models.TTL) {
+	b.setNegative(b.ctx, key, errPayload, ttl)
+}
+
+func (b *boundCache) GetWithLevel(key string) *models.CacheResult {
+	return b.getWithLevel(b.ctx, key)
+}
+
+func (b *boundCache) GetStaleWithLevel(key string) *models.CacheResult {
+	return b.getStaleWithLevel(b.ctx, key)
+}
+
+func (b *boundCache) Delete(key string) {
+	b.delete(b.ctx, key)
+}
+
+func (b *boundCache) GetMany(keys []string) map[string]*models.CacheEntry {
+	return b.getMany(b.ctx, keys)
+}
+
+func (b *boundCache) GetManyEntries(keys []string) map[string]*models.CacheEntry {
+	return b.getManyEntries(b.ctx, keys)
+}
+
+func (b *boundCache) GetManyWithLevel(keys []string) map[string]*models.CacheResult {
+	return b.getManyWithLevel(b.ctx, keys)
+}
+
+func (b *boundCache) SetMany(items []cache.BatchItem) {
+	b.setMany(b.ctx, items)
+}
+
+func (b *boundCache) SetWithTags(key string, val []byte, ttl models.TTL, tags ...string) {
+	b.setWithTags(b.ctx, key, val, ttl, tags...)
+}
+
+func (b *boundCache) InvalidateTag(tag string) []string {
+	return b.invalidate(b.ctx, "invalidate_tag", func(tc cache.TaggedCache) []string {
+		return tc.InvalidateTag(tag)
+	})
+}
+
+func (b *boundCache) InvalidatePrefix(prefix string) []string {
+	return b.invalidate(b.ctx, "invalidate_prefix", func(tc cache.TaggedCache) []string {
+		return tc.InvalidatePrefix(prefix)
+	})
+}
@@ -0,0 +1,291 @@
+package instrumented
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/mock/gomock"
+
+	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/cache/l1"
+	"github.com/status-im/proxy-common/cache/mock"
+	"github.com/status-im/proxy-common/cache/multi"
+	"github.com/status-im/proxy-common/clock"
+	"github.com/status-im/proxy-common/models"
+)
+
+var testLabels = cache.RequestLabels{
+	CacheType: models.CacheTypeShort,
+	Chain:     "ethereum",
+	Network:   "mainnet",
+	Method:    "eth_getBalance",
+}
+
+// newTestLevels returns two in-memory levels and a MultiCache over them
+func newTestLevels(t *testing.T, c clock.Clock) (cache.Cache, cache.Cache, cache.LevelAwareCache) {
+	levels := make([]cache.Cache, 2)
+	for i := range levels {
+		bc, err := l1.NewBigCache(&cache.BigCacheConfig{Enabled: true, Size: 10}, l1.WithClock(c))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = bc.(*l1.BigCache).Close() })
+		levels[i] = bc
+	}
+
+	return levels[0], levels[1], multi.NewMultiCache(levels, false, multi.WithClock(c))
+}
+
+func expectTimer(m *mock.MockMetricsRecorder, operation string) {
+	m.EXPECT().TimeCacheOperation(operation, "multi").Return(func() {})
+}
+
+func TestCache_RecordsHitsPerLevel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockMetrics := mock.NewMockMetricsRecorder(ctrl)
+	mockClock := clock.NewMock(time.Unix(1700000000, 0))
+
+	first, second, mc := newTestLevels(t, mockClock)
+	ttl := models.TTL{Fresh: time.Minute}
+	first.Set("k1", []byte("one"), ttl)
+	second.Set("k2", []byte("second"), ttl)
+	mockClock.Advance(5 * time.Second)
+
+	ic := New(mc, mockMetrics, WithClock(mockClock))
+	view := ic.WithContext(cache.ContextWithRequestLabels(context.Background(), testLabels))
+
+	expectTimer(mockMetrics, "get")
+	mockMetrics.EXPECT().RecordCacheHit("short", "l1", "ethereum", "mainnet", "eth_getBalance", 5*time.Second)
+	mockMetrics.EXPECT().RecordCacheBytesRead("l1", "short", "ethereum", "mainnet", 3)
+
+	entry, found := view.Get("k1")
+	require.True(t, found)
+	assert.Equal(t, []byte("one"), entry.Data)
+
+	expectTimer(mockMetrics, "get")
+	mockMetrics.EXPECT().RecordCacheHit("short", "l2", "ethereum", "mainnet", "eth_getBalance", 5*time.Second)
+	mockMetrics.EXPECT().RecordCacheBytesRead("l2", "short", "ethereum", "mainnet", 6)
+
+	result := view.GetWithLevel("k2")
+	require.True(t, result.Found)
+	assert.Equal(t, models.CacheLevelL2, result.Level)
+}
+
+func TestCache_RecordsMiss(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockMetrics := mock.NewMockMetricsRecorder(ctrl)
+	_, _, mc := newTestLevels(t, clock.Real{})
+
+	ic := New(mc, mockMetrics)
+	view := ic.WithContext(cache.ContextWithRequestLabels(context.Background(), testLabels))
+
+	expectTimer(mockMetrics, "get_stale")
+	mockMetrics.EXPECT().RecordCacheMiss("short", "ethereum", "mainnet", "eth_getBalance")
+
+	result := view.GetStaleWithLevel("missing")
+	assert.False(t, result.Found)
+	assert.Equal(t, models.CacheLevelMiss, result.Level)
+}
+
+func TestCache_RecordsSetAndDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockMetrics := mock.NewMockMetricsRecorder(ctrl)
+	_, _, mc := newTestLevels(t, clock.Real{})
+
+	ic := New(mc, mockMetrics)
+	view := ic.WithContext(cache.ContextWithRequestLabels(context.Background(), testLabels))
+
+	expectTimer(mockMetrics, "set")
+	mockMetrics.EXPECT().RecordCacheSet("multi", "short", "ethereum", "mainnet", 5)
+	view.Set("key", []byte("value"), models.TTL{Fresh: time.Minute})
+
+	expectTimer(mockMetrics, "delete")
+	view.Delete("key")
+
+	_, found := mc.Get("key")
+	assert.False(t, found)
+}
+
+func TestCache_RecordsNegativeHit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockMetrics := mock.NewMockMetricsRecorder(ctrl)
+	negativeMetrics := mock.NewMockNegativeCacheMetricsRecorder(ctrl)
+	_, second, mc := newTestLevels(t, clock.Real{})
+
+	ic := New(mc, struct {
+		*mock.MockMetricsRecorder
+		*mock.MockNegativeCacheMetricsRecorder
+	}{mockMetrics, negativeMetrics})
+	view := ic.WithContext(cache.ContextWithRequestLabels(context.Background(), testLabels))
+
+	expectTimer(mockMetrics, "set_negative")
+	mockMetrics.EXPECT().RecordCacheSet("multi", "short", "ethereum", "mainnet", 9)
+	view.(cache.NegativeCache).SetNegative("k", []byte("not found"), models.TTL{Fresh: time.Minute})
+
+	entry, found := second.(cache.NegativeCache).GetEntry("k")
+	require.True(t, found)
+	assert.True(t, entry.IsNegative())
+
+	expectTimer(mockMetrics, "get")
+	negativeMetrics.EXPECT().RecordCacheNegativeHit("short", "l1", "ethereum", "mainnet", "eth_getBalance")
+
+	result := view.GetWithLevel("k")
+	assert.True(t, result.IsNegative())
+	assert.False(t, result.Found)
+
+	expectTimer(mockMetrics, "get")
+	negativeMetrics.EXPECT().RecordCacheNegativeHit("short", "l1", "ethereum", "mainnet", "eth_getBalance")
+
+	_, found = view.Get("k")
+	assert.False(t, found, "Get must report negative entries as misses")
+}
+
+func TestCache_WithoutLabels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockMetrics := mock.NewMockMetricsRecorder(ctrl)
+	_, _, mc := newTestLevels(t, clock.Real{})
+
+	ic := New(mc, mockMetrics, WithLevel("l1"))
+
+	mockMetrics.EXPECT().TimeCacheOperation("set", "l1").Return(func() {})
+	mockMetrics.EXPECT().RecordCacheSet("l1", "unknown", "", "", 5)
+	ic.Set("key", []byte("value"), models.TTL{Fresh: time.Minute})
+
+	mockMetrics.EXPECT().TimeCacheOperation("get", "l1").Return(func() {})
+	mockMetrics.EXPECT().RecordCacheHit("unknown", "l1", "", "", "", gomock.Any())
+	mockMetrics.EXPECT().RecordCacheBytesRead("l1", "unknown", "", "", 5)
+	_, found := ic.Get("key")
+	assert.True(t, found)
+}
+
+func TestCache_WithTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	_, second, mc := newTestLevels(t, clock.Real{})
+	second.Set("key", []byte("value"), models.TTL{Fresh: time.Minute})
+
+	ic := New(mc, cache.NoopMetrics{}, WithTracer(tracer))
+	ctx, parent := tracer.Start(cache.ContextWithRequestLabels(context.Background(), testLabels), "request")
+	view := ic.WithContext(ctx)
+
+	view.GetWithLevel("key")
+	view.Get("missing")
+	view.Set("other", []byte("v"), models.TTL{Fresh: time.Minute})
+	view.Delete("other")
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 5)
+
+	tests := []struct {
+		name  string
+		attrs []attribute.KeyValue
+	}{
+		{name: "cache.get", attrs: []attribute.KeyValue{attribute.String("cache.level", "l2"), attribute.Bool("cache.hit", true)}},
+		{name: "cache.get", attrs: []attribute.KeyValue{attribute.String("cache.level", "miss"), attribute.Bool("cache.hit", false)}},
+		{name: "cache.set"},
+		{name: "cache.delete"},
+	}
+	for i, tt := range tests {
+		span := spans[i]
+		assert.Equal(t, tt.name, span.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Contains(t, span.Attributes(), attribute.String("cache.type", "short"))
+		for _, attr := range tt.attrs {
+			assert.Contains(t, span.Attributes(), attr)
+		}
+	}
+}
+
+func TestCache_WithContext_AppliesLevelPolicies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockMetrics := mock.NewMockMetricsRecorder(ctrl)
+	first, second, _ := newTestLevels(t, clock.Real{})
+	mc := multi.NewMultiCache([]cache.Cache{first, second}, false, multi.WithLevelPolicies([]cache.LevelPolicy{
+		{},
+		{SkipCacheTypes: []models.CacheType{models.CacheTypeShort}},
+	}))
+
+	ic := New(mc, mockMetrics)
+	view := ic.WithContext(cache.ContextWithRequestLabels(context.Background(), testLabels))
+
+	expectTimer(mockMetrics, "set")
+	mockMetrics.EXPECT().RecordCacheSet("multi", "short", "ethereum", "mainnet", 5)
+	view.Set("key", []byte("value"), models.TTL{Fresh: time.Minute})
+
+	_, found := first.Get("key")
+	assert.True(t, found)
+	_, found = second.Get("key")
+	assert.False(t, found)
+}
+
+func TestCache_RecordsBatchOperations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockMetrics := mock.NewMockMetricsRecorder(ctrl)
+	_, _, mc := newTestLevels(t, clock.Real{})
+
+	ic := New(mc, mockMetrics)
+	view, ok := ic.WithContext(cache.ContextWithRequestLabels(context.Background(), testLabels)).(cache.LevelAwareBatchCache)
+	require.True(t, ok, "bound view must expose the batch operations of MultiCache")
+
+	expectTimer(mockMetrics, "set_many")
+	mockMetrics.EXPECT().RecordCacheSet("multi", "short", "ethereum", "mainnet", 3)
+	mockMetrics.EXPECT().RecordCacheSet("multi", "short", "ethereum", "mainnet", 5)
+	view.SetMany([]cache.BatchItem{
+		{Key: "k1", Val: []byte("one"), TTL: models.TTL{Fresh: time.Minute}},
+		{Key: "k2", Val: []byte("three"), TTL: models.TTL{Fresh: time.Minute}},
+	})
+
+	expectTimer(mockMetrics, "get_many")
+	mockMetrics.EXPECT().RecordCacheHit("short", "l1", "ethereum", "mainnet", "eth_getBalance", gomock.Any()).Times(2)
+	mockMetrics.EXPECT().RecordCacheBytesRead("l1", "short", "ethereum", "mainnet", gomock.Any()).Times(2)
+	mockMetrics.EXPECT().RecordCacheMiss("short", "ethereum", "mainnet", "eth_getBalance")
+
+	results := view.GetManyWithLevel([]string{"k1", "k2", "missing"})
+	require.Len(t, results, 3)
+	assert.Equal(t, models.CacheLevelL1, results["k1"].Level)
+	assert.False(t, results["missing"].Found)
+
+	expectTimer(mockMetrics, "get_many")
+	mockMetrics.EXPECT().RecordCacheHit("short", "l1", "ethereum", "mainnet", "eth_getBalance", gomock.Any())
+	mockMetrics.EXPECT().RecordCacheBytesRead("l1", "short", "ethereum", "mainnet", 3)
+	mockMetrics.EXPECT().RecordCacheMiss("short", "ethereum", "mainnet", "eth_getBalance")
+
+	entries := view.GetMany([]string{"k1", "missing"})
+	require.Len(t, entries, 1)
+	assert.Equal(t, []byte("one"), entries["k1"].Data)
+}
+
+func TestCache_PassesThroughTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockMetrics := mock.NewMockMetricsRecorder(ctrl)
+	first, second, mc := newTestLevels(t, clock.Real{})
+
+	ic := New(mc, mockMetrics)
+
+	expectTimer(mockMetrics, "set")
+	mockMetrics.EXPECT().RecordCacheSet("multi", "unknown", "", "", 5)
+	ic.SetWithTags("block:1:a", []byte("value"), models.TTL{Fresh: time.Minute}, "block:1")
+
+	expectTimer(mockMetrics, "set")
+	mockMetrics.EXPECT().RecordCacheSet("multi", "unknown", "", "", 5)
+	ic.Set("block:2:a", []byte("value"), models.TTL{Fresh: time.Minute})
+
+	expectTimer(mockMetrics, "invalidate_tag")
+	assert.Equal(t, []string{"block:1:a"}, ic.InvalidateTag("block:1"))
+
+	expectTimer(mockMetrics, "invalidate_prefix")
+	assert.Equal(t, []string{"block:2:a"}, ic.InvalidatePrefix("block:2:"))
+
+	for _, level := range []cache.Cache{first, second} {
+		_, found := level.Get("block:1:a")
+		assert.False(t, found)
+		_, found = level.Get("block:2:a")
+		assert.False(t, found)
+	}
+}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -25,6 +26,163 @@ type LevelAwareCache interface {
 	GetStaleWithLevel(key string) *models.CacheResult // stale-if-error
 }
 
+// BindableCache extends LevelAwareCache with WithContext, which returns a view of the
+// cache whose operations use the RequestLabels stored in ctx, e.g. to apply the level
+// policies for the request's cache type. ForCacheType binds a cache type alone.
+type BindableCache interface {
+	LevelAwareCache
+	WithContext(ctx context.Context) LevelAwareCache
+	ForCacheType(cacheType models.CacheType) LevelAwareCache
+}
+
+// SizeLimitedCache is implemented by cache levels that encode entries before storing
+// them. WithMaxEntrySize returns a view of the level, sharing its storage, that skips
+// entries whose encoded, possibly compressed, size exceeds size bytes.
+type SizeLimitedCache interface {
+	WithMaxEntrySize(size int) Cache
+}
+
+// TaggedCache extends Cache with bulk invalidation, e.g. dropping every entry for a
+// block or chain after a reorg. Invalidate methods return the keys they removed.
+type TaggedCache interface {
+	Cache
+	SetWithTags(key string, val []byte, ttl models.TTL, tags ...string)
+	InvalidateTag(tag string) []string
+	InvalidatePrefix(prefix string) []string
+}
+
+// NegativeCache extends Cache with negative entries, which record that a key has no
+// value (e.g. an unknown transaction hash) or that upstream answered with a deterministic
+// error. errPayload is the optional serialized error; ttl is usually much shorter than
+// the TTL of positive entries. Get and GetStale report negative entries as misses, so
+// only GetEntry and GetStaleEntry return them.
+type NegativeCache interface {
+	Cache
+	SetNegative(key string, errPayload []byte, ttl models.TTL)
+	GetEntry(key string) (*models.CacheEntry, bool)
+	GetStaleEntry(key string) (*models.CacheEntry, bool) // stale-if-error
+}
+
+// NegativeBatchCache extends BatchCache with a multi-key read that also returns
+// negative entries, which GetMany leaves out
+type NegativeBatchCache interface {
+	BatchCache
+	GetManyEntries(keys []string) map[string]*models.CacheEntry
+}
+
+// HealthChecker is implemented by cache levels that track their own health, such as
+// L2 behind a circuit breaker. MultiCache skips levels that are not Available for reads
+// and writes, but still sends them deletes, which such a level records and replays.
+type HealthChecker interface {
+	Available() bool
+	HealthCheck(ctx context.Context) error
+}
+
+// StatsReporter is implemented by cache levels that can describe their current state,
+// e.g. for the admin handler
+type StatsReporter interface {
+	Stats() LevelStats
+}
+
+// LevelStats describes the current state of a cache level. Fields a level does not
+// track are left zero.
+type LevelStats struct {
+	Keys            int64  `json:"keys,omitempty"`
+	CapacityBytes   int64  `json:"capacity_bytes,omitempty"`
+	UsedBytes       int64  `json:"used_bytes,omitempty"`
+	Hits            int64  `json:"hits,omitempty"`
+	Misses          int64  `json:"misses,omitempty"`
+	Collisions      int64  `json:"collisions,omitempty"`
+	DeleteHits      int64  `json:"delete_hits,omitempty"`
+	CircuitState    string `json:"circuit_state,omitempty"`
+	WriteQueueDepth int    `json:"write_queue_depth,omitempty"`
+}
+
+// BatchItem is a single value written by SetMany
+type BatchItem struct {
+	Key string
+	Val []byte
+	TTL models.TTL
+}
+
+// BatchCache extends Cache with multi-key operations that avoid a round trip per key.
+// GetMany returns only the keys that were found with a positive entry.
+type BatchCache interface {
+	Cache
+	GetMany(keys []string) map[string]*models.CacheEntry
+	SetMany(items []BatchItem)
+}
+
+// LevelAwareBatchCache extends BatchCache with per-key level reporting.
+// Every requested key has a result; misses have Level MISS and negative entries are
+// reported as described on models.CacheResult.
+type LevelAwareBatchCache interface {
+	BatchCache
+	GetManyWithLevel(keys []string) map[string]*models.CacheResult
+}
+
+// InvalidationMessage describes entries to drop from every replica's local cache level
+type InvalidationMessage struct {
+	Source string   `json:"source"` // publishing instance, so it can skip its own messages
+	Keys   []string `json:"keys,omitempty"`
+	Tag    string   `json:"tag,omitempty"`
+	Prefix string   `json:"prefix,omitempty"`
+}
+
+// InvalidationHandler is called for every message received from an InvalidationBus
+type InvalidationHandler func(msg InvalidationMessage)
+
+// InvalidationBus broadcasts invalidations between replicas that share an L2, so each
+// replica can drop the affected keys from its own L1. Subscribe returns a function
+// that unregisters the handler.
+type InvalidationBus interface {
+	Publish(ctx context.Context, msg InvalidationMessage) error
+	Subscribe(handler InvalidationHandler) (unsubscribe func())
+	Close() error
+}
+
+// ErrCacheMiss is returned by CacheV2 reads when the key is absent or expired
+var ErrCacheMiss = errors.New("cache miss")
+
+// CacheV2 is the context-aware counterpart of Cache. Implementations honour the
+// context's deadline and cancellation and report failures instead of swallowing them,
+// so a miss (ErrCacheMiss) can be told apart from an unavailable backend.
+type CacheV2 interface {
+	Get(ctx context.Context, key string) (*models.CacheEntry, error)
+	GetStale(ctx context.Context, key string) (*models.CacheEntry, error) // stale-if-error
+	Set(ctx context.Context, key string, val []byte, ttl models.TTL) error
+	Delete(ctx context.Context, key string) error
+}
+
+// LevelAwareCacheV2 interface extends CacheV2 with level-aware operations.
+// On a miss the returned result has Level MISS and the error is ErrCacheMiss. A negative
+// entry is also returned with ErrCacheMiss, in a result whose IsNegative reports true.
+type LevelAwareCacheV2 interface {
+	CacheV2
+	GetWithLevel(ctx context.Context, key string) (*models.CacheResult, error)
+	GetStaleWithLevel(ctx context.Context, key string) (*models.CacheResult, error) // stale-if-error
+}
+
+// NegativeCacheV2 is the context-aware counterpart of NegativeCache. Get and GetStale
+// return ErrCacheMiss for negative entries, so only GetEntry and GetStaleEntry return them.
+type NegativeCacheV2 interface {
+	CacheV2
+	SetNegative(ctx context.Context, key string, errPayload []byte, ttl models.TTL) error
+	GetEntry(ctx context.Context, key string) (*models.CacheEntry, error)
+	GetStaleEntry(ctx context.Context, key string) (*models.CacheEntry, error) // stale-if-error
+}
+
+// SizeLimitedCacheV2 is the context-aware counterpart of SizeLimitedCache
+type SizeLimitedCacheV2 interface {
+	WithMaxEntrySize(size int) CacheV2
+}
+
+// Codec serializes cache entries for storage in a cache level
+type Codec interface {
+	Encode(entry *models.CacheEntry) ([]byte, error)
+	Decode(data []byte) (*models.CacheEntry, error)
+}
+
 // KeyDbClient defines the interface for KeyDB/Redis client operations
 type KeyDbClient interface {
 	Get(ctx context.Context, key string) *redis.StringCmd
@@ -34,6 +192,20 @@ type KeyDbClient interface {
 	Close() error
 }
 
+// ExtendedKeyDbClient is implemented by clients that also support batching, tag sets,
+// key scans and pub/sub. The L2 cache detects it with a type assertion; with a plain
+// KeyDbClient it sends one request per key, does not record tags and rejects tag and
+// prefix invalidation.
+type ExtendedKeyDbClient interface {
+	KeyDbClient
+	MGet(ctx context.Context, keys ...string) *redis.SliceCmd
+	Pipeline() redis.Pipeliner
+	SMembers(ctx context.Context, key string) *redis.StringSliceCmd
+	Scan(ctx context.Context, cursor uint64, match string, count int64) *redis.ScanCmd
+	Publish(ctx context.Context, channel string, message interface{}) *redis.IntCmd
+	Subscribe(ctx context.Context, channels ...string) *redis.PubSub
+}
+
 // Logger defines the interface for logging operations
 // This allows users to plug in their own logger (zap, logrus, etc.)
 type Logger interface {
@@ -43,6 +215,13 @@ type Logger interface {
 	Error(msg string, keysAndValues ...interface{})
 }
 
+// Eviction reasons reported to L1StatsMetricsRecorder.RecordCacheEvictionReason
+const (
+	EvictionExpired = "expired"  // the entry outlived the level's lifetime window
+	EvictionNoSpace = "no_space" // the entry was the oldest when the level ran out of space
+	EvictionDeleted = "deleted"  // the entry was deleted, including expired entries dropped on read
+)
+
 // MetricsRecorder defines the interface for recording cache metrics
 // Users can implement this to integrate with their metrics system (Prometheus, etc.)
 type MetricsRecorder interface {
@@ -56,6 +235,58 @@ type MetricsRecorder interface {
 	TimeCacheOperation(operation, level string) func()
 }
 
+// The recorders below are optional extensions of MetricsRecorder. Cache components
+// detect them with a type assertion, so a MetricsRecorder that does not implement one
+// keeps working and does not receive its metrics.
+
+// CoalescingMetricsRecorder records loads and refreshes that waited on an in-flight one
+type CoalescingMetricsRecorder interface {
+	RecordCacheCoalesced(operation string)
+}
+
+// CompressionMetricsRecorder records the size of compressed cache values
+type CompressionMetricsRecorder interface {
+	RecordCacheCompression(level, algorithm string, rawBytes, compressedBytes int)
+}
+
+// TTLMetricsRecorder records TTLs adjusted by the configured limits
+type TTLMetricsRecorder interface {
+	RecordCacheTTLAdjusted(level, reason string)
+}
+
+// L1StatsMetricsRecorder records L1 evictions, why entries leave L1 and the L1 internal
+// counters
+type L1StatsMetricsRecorder interface {
+	RecordCacheEviction(level, cacheType, chain, network string)
+	RecordCacheEvictionReason(level, reason string)
+	RecordL1CacheStats(collisions, deleteHits int64)
+}
+
+// NegativeCacheMetricsRecorder records hits on negative entries
+type NegativeCacheMetricsRecorder interface {
+	RecordCacheNegativeHit(cacheType, level, chain, network, rpcMethod string)
+}
+
+// CircuitBreakerMetricsRecorder records L2 circuit breaker state changes
+type CircuitBreakerMetricsRecorder interface {
+	UpdateCircuitBreakerState(level string, state CircuitState)
+}
+
+// WriteBehindMetricsRecorder records the L2 write-behind queue depth and lost writes
+type WriteBehindMetricsRecorder interface {
+	UpdateWriteQueueDepth(level string, depth int)
+	RecordWriteDropped(level, reason string)
+}
+
+// OptionalMetrics returns m as the optional recorder T, or NoopMetrics if m does not
+// implement it
+func OptionalMetrics[T any](m MetricsRecorder) T {
+	if r, ok := m.(T); ok {
+		return r
+	}
+	return any(NoopMetrics{}).(T)
+}
+
 // NoopLogger is a no-operation logger that discards all log messages
 type NoopLogger struct{}
 
@@ -64,15 +295,37 @@ func (NoopLogger) Info(msg string, keysAndValues ...interface{})  {}
 func (NoopLogger) Warn(msg string, keysAndValues ...interface{})  {}
 func (NoopLogger) Error(msg string, keysAndValues ...interface{}) {}
 
-// NoopMetrics is a no-operation metrics recorder that discards all metrics
+// NoopMetrics is a no-operation metrics recorder that discards all metrics, including
+// those of every optional recorder
 type NoopMetrics struct{}
 
+// Ensure NoopMetrics implements MetricsRecorder and the optional recorders
+var _ MetricsRecorder = NoopMetrics{}
+var _ CoalescingMetricsRecorder = NoopMetrics{}
+var _ CompressionMetricsRecorder = NoopMetrics{}
+var _ TTLMetricsRecorder = NoopMetrics{}
+var _ L1StatsMetricsRecorder = NoopMetrics{}
+var _ NegativeCacheMetricsRecorder = NoopMetrics{}
+var _ CircuitBreakerMetricsRecorder = NoopMetrics{}
+var _ WriteBehindMetricsRecorder = NoopMetrics{}
+
 func (NoopMetrics) RecordCacheError(level, kind string)        {}
 func (NoopMetrics) UpdateL1CacheCapacity(capacity, used int64) {}
 func (NoopMetrics) UpdateCacheKeys(level string, count int64)  {}
 func (NoopMetrics) RecordCacheHit(cacheType, level, chain, network, rpcMethod string, itemAge time.Duration) {
 }
 func (NoopMetrics) RecordCacheMiss(cacheType, chain, network, rpcMethod string)                 {}
+func (NoopMetrics) RecordCacheNegativeHit(cacheType, level, chain, network, rpcMethod string)   {}
 func (NoopMetrics) RecordCacheSet(level, cacheType, chain, network string, dataSize int)        {}
 func (NoopMetrics) RecordCacheBytesRead(level, cacheType, chain, network string, bytesRead int) {}
 func (NoopMetrics) TimeCacheOperation(operation, level string) func()                           { return func() {} }
+func (NoopMetrics) RecordCacheCoalesced(operation string)                                       {}
+func (NoopMetrics) RecordCacheCompression(level, algorithm string, rawBytes, compressedBytes int) {
+}
+func (NoopMetrics) RecordCacheTTLAdjusted(level, reason string)                 {}
+func (NoopMetrics) RecordCacheEviction(level, cacheType, chain, network string) {}
+func (NoopMetrics) RecordCacheEvictionReason(level, reason string)              {}
+func (NoopMetrics) RecordL1CacheStats(collisions, deleteHits int64)             {}
+func (NoopMetrics) UpdateCircuitBreakerState(level string, state CircuitState)  {}
+func (NoopMetrics) UpdateWriteQueueDepth(level string, depth int)               {}
+func (NoopMetrics) RecordWriteDropped(level, reason string)                     {}
@@ -0,0 +1,60 @@
+package invalidation
+
+import (
+	"context"
+	"sync"
+
+	"github.com/status-im/proxy-common/cache"
+)
+
+// Ensure MemoryBus implements cache.InvalidationBus
+var _ cache.InvalidationBus = (*MemoryBus)(nil)
+
+// MemoryBus is an in-process cache.InvalidationBus. Messages are delivered
+// synchronously to every subscriber, which makes it suitable for tests and for
+// several caches sharing one process.
+type MemoryBus struct {
+	mu     sync.RWMutex
+	closed bool
+
+	subscribers subscribers
+}
+
+// NewMemoryBus creates a new in-memory invalidation bus
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{}
+}
+
+// Publish delivers msg to every subscriber before returning
+func (b *MemoryBus) Publish(ctx context.Context, msg cache.InvalidationMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.mu.RLock()
+	closed := b.closed
+	b.mu.RUnlock()
+	if closed {
+		return ErrClosed
+	}
+
+	b.subscribers.dispatch(msg)
+	return nil
+}
+
+// Subscribe registers handler for all subsequently published messages until the
+// returned function is called
+func (b *MemoryBus) Subscribe(handler cache.InvalidationHandler) func() {
+	return b.subscribers.add(handler)
+}
+
+// Close stops delivery; later publishes return ErrClosed
+func (b *MemoryBus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	b.subscribers.clear()
+
+	return nil
+}
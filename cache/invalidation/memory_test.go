@@ -0,0 +1,63 @@
+package invalidation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/proxy-common/cache"
+)
+
+func TestMemoryBus_Publish(t *testing.T) {
+	bus := NewMemoryBus()
+
+	var first, second []cache.InvalidationMessage
+	bus.Subscribe(func(msg cache.InvalidationMessage) { first = append(first, msg) })
+	bus.Subscribe(func(msg cache.InvalidationMessage) { second = append(second, msg) })
+
+	msg := cache.InvalidationMessage{Source: "a", Keys: []string{"key"}, Tag: "tag"}
+	require.NoError(t, bus.Publish(context.Background(), msg))
+
+	assert.Equal(t, []cache.InvalidationMessage{msg}, first)
+	assert.Equal(t, []cache.InvalidationMessage{msg}, second)
+}
+
+func TestMemoryBus_Unsubscribe(t *testing.T) {
+	bus := NewMemoryBus()
+
+	var kept int
+	unsubscribe := bus.Subscribe(func(cache.InvalidationMessage) {
+		t.Fatal("unsubscribed handlers should not be called")
+	})
+	bus.Subscribe(func(cache.InvalidationMessage) { kept++ })
+
+	unsubscribe()
+	unsubscribe()
+	require.NoError(t, bus.Publish(context.Background(), cache.InvalidationMessage{Keys: []string{"key"}}))
+
+	assert.Equal(t, 1, kept)
+}
+
+func TestMemoryBus_Closed(t *testing.T) {
+	bus := NewMemoryBus()
+	bus.Subscribe(func(cache.InvalidationMessage) {
+		t.Fatal("handlers should not be called after Close")
+	})
+
+	require.NoError(t, bus.Close())
+
+	err := bus.Publish(context.Background(), cache.InvalidationMessage{Keys: []string{"key"}})
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestMemoryBus_CancelledContext(t *testing.T) {
+	bus := NewMemoryBus()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := bus.Publish(ctx, cache.InvalidationMessage{Keys: []string{"key"}})
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,130 @@
+package invalidation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/status-im/proxy-common/cache"
+)
+
+// Ensure RedisBus implements cache.InvalidationBus
+var _ cache.InvalidationBus = (*RedisBus)(nil)
+
+// DefaultChannel is the pub/sub channel used when none is configured
+const DefaultChannel = "cache:invalidation"
+
+// ErrClosed is returned when publishing on a closed bus
+var ErrClosed = errors.New("invalidation bus closed")
+
+// PubSubClient is the part of cache.ExtendedKeyDbClient RedisBus uses
+type PubSubClient interface {
+	Publish(ctx context.Context, channel string, message interface{}) *redis.IntCmd
+	Subscribe(ctx context.Context, channels ...string) *redis.PubSub
+}
+
+// RedisBus is a cache.InvalidationBus backed by KeyDB/Redis pub/sub. Delivery is
+// at-most-once: replicas that are disconnected when a message is published miss it
+// and keep serving their L1 copy until it expires.
+type RedisBus struct {
+	client  PubSubClient
+	channel string
+	pubsub  *redis.PubSub
+	logger  cache.Logger
+
+	subscribers subscribers
+
+	done chan struct{}
+}
+
+// Option is a functional option for configuring RedisBus
+type Option func(*RedisBus)
+
+// WithLogger sets the logger for RedisBus
+func WithLogger(logger cache.Logger) Option {
+	return func(b *RedisBus) {
+		b.logger = logger
+	}
+}
+
+// WithChannel sets the pub/sub channel, e.g. to separate environments sharing a KeyDB
+func WithChannel(channel string) Option {
+	return func(b *RedisBus) {
+		b.channel = channel
+	}
+}
+
+// NewRedisBus subscribes to the invalidation channel and starts dispatching messages
+func NewRedisBus(ctx context.Context, client PubSubClient, opts ...Option) (*RedisBus, error) {
+	b := &RedisBus{
+		client:  client,
+		channel: DefaultChannel,
+		logger:  cache.NoopLogger{},
+		done:    make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	b.pubsub = client.Subscribe(ctx, b.channel)
+
+	// Wait for the subscription confirmation so a failure surfaces here
+	if _, err := b.pubsub.Receive(ctx); err != nil {
+		_ = b.pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", b.channel, err)
+	}
+
+	go b.run()
+
+	b.logger.Info("Subscribed to cache invalidation channel", "channel", b.channel)
+
+	return b, nil
+}
+
+// Publish broadcasts msg to every subscribed replica, including this one
+func (b *RedisBus) Publish(ctx context.Context, msg cache.InvalidationMessage) error {
+	select {
+	case <-b.done:
+		return ErrClosed
+	default:
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode invalidation message: %w", err)
+	}
+
+	return b.client.Publish(ctx, b.channel, payload).Err()
+}
+
+// Subscribe registers handler for all subsequently received messages until the
+// returned function is called
+func (b *RedisBus) Subscribe(handler cache.InvalidationHandler) func() {
+	return b.subscribers.add(handler)
+}
+
+// Close unsubscribes and waits for the dispatch loop to exit
+func (b *RedisBus) Close() error {
+	err := b.pubsub.Close()
+	<-b.done
+	return err
+}
+
+// run dispatches received messages until the subscription is closed
+func (b *RedisBus) run() {
+	defer close(b.done)
+
+	for message := range b.pubsub.Channel() {
+		var msg cache.InvalidationMessage
+		if err := json.Unmarshal([]byte(message.Payload), &msg); err != nil {
+			b.logger.Warn("Failed to decode invalidation message", "channel", b.channel, "error", err)
+			continue
+		}
+
+		b.subscribers.dispatch(msg)
+	}
+}
@@ -0,0 +1,97 @@
+package invalidation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/cache/l2"
+)
+
+// newTestClient connects a KeyDB client to server
+func newTestClient(t *testing.T, server *miniredis.Miniredis) cache.ExtendedKeyDbClient {
+	t.Helper()
+
+	client, err := l2.NewRedisKeyDbClient(&cache.KeyDBConfig{}, "redis://"+server.Addr())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client
+}
+
+func TestRedisBus_PublishSubscribe(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.Background()
+
+	publisher, err := NewRedisBus(ctx, newTestClient(t, server))
+	require.NoError(t, err)
+	defer publisher.Close()
+
+	subscriber, err := NewRedisBus(ctx, newTestClient(t, server))
+	require.NoError(t, err)
+	defer subscriber.Close()
+
+	received := make(chan cache.InvalidationMessage, 1)
+	subscriber.Subscribe(func(msg cache.InvalidationMessage) { received <- msg })
+
+	msg := cache.InvalidationMessage{Source: "replica-1", Keys: []string{"a", "b"}, Tag: "block:100"}
+	require.NoError(t, publisher.Publish(ctx, msg))
+
+	select {
+	case got := <-received:
+		assert.Equal(t, msg, got)
+	case <-time.After(time.Second):
+		t.Fatal("invalidation message not received")
+	}
+}
+
+func TestRedisBus_Channel(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.Background()
+
+	bus, err := NewRedisBus(ctx, newTestClient(t, server), WithChannel("staging:invalidation"))
+	require.NoError(t, err)
+	defer bus.Close()
+
+	assert.Equal(t, []string{"staging:invalidation"}, server.PubSubChannels(""))
+}
+
+func TestRedisBus_IgnoresMalformedMessages(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.Background()
+
+	bus, err := NewRedisBus(ctx, newTestClient(t, server))
+	require.NoError(t, err)
+	defer bus.Close()
+
+	received := make(chan cache.InvalidationMessage, 2)
+	bus.Subscribe(func(msg cache.InvalidationMessage) { received <- msg })
+
+	server.Publish(DefaultChannel, "not json")
+	require.NoError(t, bus.Publish(ctx, cache.InvalidationMessage{Keys: []string{"key"}}))
+
+	select {
+	case got := <-received:
+		assert.Equal(t, []string{"key"}, got.Keys)
+	case <-time.After(time.Second):
+		t.Fatal("invalidation message not received")
+	}
+}
+
+func TestRedisBus_Closed(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.Background()
+
+	bus, err := NewRedisBus(ctx, newTestClient(t, server))
+	require.NoError(t, err)
+	require.NoError(t, bus.Close())
+
+	err = bus.Publish(ctx, cache.InvalidationMessage{Keys: []string{"key"}})
+
+	assert.ErrorIs(t, err, ErrClosed)
+}
@@ -0,0 +1,63 @@
+package invalidation
+
+import (
+	"sync"
+
+	"github.com/status-im/proxy-common/cache"
+)
+
+// subscription wraps a handler so it can be told apart from others on unsubscribe
+type subscription struct {
+	handler cache.InvalidationHandler
+}
+
+// subscribers is a copy-on-write list of handlers, so messages are dispatched to a
+// snapshot without holding the lock
+type subscribers struct {
+	mu   sync.RWMutex
+	subs []*subscription
+}
+
+// add registers handler and returns a function removing it again
+func (s *subscribers) add(handler cache.InvalidationHandler) func() {
+	sub := &subscription{handler: handler}
+
+	s.mu.Lock()
+	s.subs = append(s.subs[:len(s.subs):len(s.subs)], sub)
+	s.mu.Unlock()
+
+	return func() { s.remove(sub) }
+}
+
+// remove unregisters sub; removing it twice is a no-op
+func (s *subscribers) remove(sub *subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs := make([]*subscription, 0, len(s.subs))
+	for _, other := range s.subs {
+		if other != sub {
+			subs = append(subs, other)
+		}
+	}
+	s.subs = subs
+}
+
+// clear unregisters every handler
+func (s *subscribers) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subs = nil
+}
+
+// dispatch calls every registered handler with msg
+func (s *subscribers) dispatch(msg cache.InvalidationMessage) {
+	s.mu.RLock()
+	subs := s.subs
+	s.mu.RUnlock()
+
+	for _, sub := range subs {
+		sub.handler(msg)
+	}
+}
@@ -0,0 +1,277 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/status-im/proxy-common/internal/hexutil"
+)
+
+// hashedParamsPrefix marks keys whose params were hashed. Canonical params always
+// start with '[' or '{', so hashed and unhashed keys cannot collide.
+const hashedParamsPrefix = "sha256:"
+
+// maxNumberExponent bounds the decimal exponent of numbers that are normalized, so a
+// param such as 1e1000000000 cannot make the key builder expand it. Larger exponents
+// are kept as written.
+const maxNumberExponent = 1000
+
+// blockFields are the object fields holding block references, e.g. in eth_getLogs
+// filters and EIP-1898 block parameters
+var blockFields = map[string]bool{
+	"fromBlock":   true,
+	"toBlock":     true,
+	"blockNumber": true,
+}
+
+// BlockResolver maps a block tag such as "latest" to the block number it currently
+// refers to. It returns false if the tag should be kept as is.
+type BlockResolver func(tag string) (uint64, bool)
+
+// KeyBuilder turns JSON-RPC requests into cache keys of the form
+// [namespace:][version:]chain:network:method:params. Params are canonicalized so that
+// semantically equal requests from different proxies share a key:
+//   - object keys are sorted and insignificant whitespace is dropped
+//   - 0x-prefixed hex strings are lowercased
+//   - hex block numbers in fromBlock, toBlock and blockNumber fields lose leading zeros
+//   - numbers are normalized exactly, e.g. 1.0 and 1e0 both become 1 and 1e20 becomes
+//     100000000000000000000
+//   - with a BlockResolver, block tags become the block number they resolve to
+//
+// Keys longer than MaxKeyLength have their params replaced by a SHA-256 digest.
+type KeyBuilder struct {
+	prefix        string
+	maxKeyLength  int
+	blockResolver BlockResolver
+}
+
+// KeyBuilderOption is a functional option for configuring KeyBuilder
+type KeyBuilderOption func(*KeyBuilder)
+
+// WithBlockResolver resolves block tags in params, so a request for "latest" shares its
+// key with the same request for the explicit head block
+func WithBlockResolver(resolver BlockResolver) KeyBuilderOption {
+	return func(kb *KeyBuilder) {
+		kb.blockResolver = resolver
+	}
+}
+
+// NewKeyBuilder creates a new KeyBuilder instance
+func NewKeyBuilder(cfg KeyBuilderConfig, opts ...KeyBuilderOption) *KeyBuilder {
+	cfg.ApplyDefaults()
+
+	var prefix strings.Builder
+	for _, part := range []string{cfg.Namespace, cfg.Version} {
+		if part != "" {
+			prefix.WriteString(part)
+			prefix.WriteByte(':')
+		}
+	}
+
+	kb := &KeyBuilder{
+		prefix:       prefix.String(),
+		maxKeyLength: cfg.MaxKeyLength,
+	}
+
+	for _, opt := range opts {
+		opt(kb)
+	}
+
+	return kb
+}
+
+// Prefix returns the key prefix shared by every request for chain and network,
+// suitable for prefix invalidation
+func (kb *KeyBuilder) Prefix(chain, network string) string {
+	return kb.prefix + chain + ":" + network + ":"
+}
+
+// Build returns the cache key for a JSON-RPC request. Empty or null params are
+// equivalent to an empty params array.
+func (kb *KeyBuilder) Build(chain, network, method string, params json.RawMessage) (string, error) {
+	canonical, err := kb.canonicalParams(params)
+	if err != nil {
+		return "", fmt.Errorf("failed to canonicalize params for %s: %w", method, err)
+	}
+
+	key := kb.Prefix(chain, network) + method + ":" + canonical
+	if len(key) <= kb.maxKeyLength {
+		return key, nil
+	}
+
+	digest := sha256.Sum256([]byte(canonical))
+	return kb.Prefix(chain, network) + method + ":" + hashedParamsPrefix + hex.EncodeToString(digest[:]), nil
+}
+
+// canonicalParams decodes params and re-encodes them in canonical form
+func (kb *KeyBuilder) canonicalParams(params json.RawMessage) (string, error) {
+	trimmed := bytes.TrimSpace(params)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return "[]", nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", err
+	}
+	if decoder.More() {
+		return "", fmt.Errorf("unexpected data after params")
+	}
+
+	if list, ok := value.([]interface{}); ok {
+		for i, param := range list {
+			list[i] = kb.resolveBlock(param)
+		}
+	}
+
+	var b strings.Builder
+	if err := writeCanonical(&b, value, kb); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// resolveBlock replaces a block tag with its resolved number, if a resolver is set
+func (kb *KeyBuilder) resolveBlock(value interface{}) interface{} {
+	tag, ok := value.(string)
+	if !ok || kb.blockResolver == nil {
+		return value
+	}
+
+	switch tag {
+	case "latest", "pending", "earliest", "safe", "finalized":
+		if number, ok := kb.blockResolver(tag); ok {
+			return "0x" + strconv.FormatUint(number, 16)
+		}
+	}
+	return value
+}
+
+// writeCanonical encodes value with sorted object keys and normalized scalars
+func writeCanonical(b *strings.Builder, value interface{}, kb *KeyBuilder) error {
+	switch v := value.(type) {
+	case nil:
+		b.WriteString("null")
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	case json.Number:
+		b.WriteString(canonicalNumber(v))
+	case string:
+		if hexutil.IsHex(v) {
+			v = strings.ToLower(v)
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		b.Write(data)
+	case []interface{}:
+		b.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := writeCanonical(b, item, kb); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		b.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			data, err := json.Marshal(key)
+			if err != nil {
+				return err
+			}
+			b.Write(data)
+			b.WriteByte(':')
+
+			item := v[key]
+			if blockFields[key] {
+				item = canonicalQuantity(kb.resolveBlock(item))
+			}
+			if err := writeCanonical(b, item, kb); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+	default:
+		return fmt.Errorf("unsupported JSON value %T", value)
+	}
+
+	return nil
+}
+
+// canonicalNumber formats numbers exactly and in a single form: integers without
+// exponent, fraction or sign of zero, and other numbers as their shortest exact
+// decimal, e.g. 1e-3 becomes 0.001
+func canonicalNumber(n json.Number) string {
+	s := n.String()
+	if exponent, ok := numberExponent(s); !ok || exponent > maxNumberExponent || exponent < -maxNumberExponent {
+		return s
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return s
+	}
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	return r.FloatString(decimalPlaces(r.Denom()))
+}
+
+// numberExponent returns the exponent of a JSON number, 0 if it has none. It fails
+// if the exponent does not fit an int.
+func numberExponent(s string) (int, bool) {
+	i := strings.IndexAny(s, "eE")
+	if i < 0 {
+		return 0, true
+	}
+	exponent, err := strconv.Atoi(s[i+1:])
+	return exponent, err == nil
+}
+
+// decimalPlaces returns the number of decimal places needed to write 1/denom exactly.
+// denom is the denominator of a reduced decimal fraction, so its only prime factors are
+// 2 and 5.
+func decimalPlaces(denom *big.Int) int {
+	twos := int(denom.TrailingZeroBits())
+
+	fives := 0
+	d := new(big.Int).Rsh(denom, uint(twos))
+	five := big.NewInt(5)
+	for d.Cmp(big.NewInt(1)) > 0 {
+		d.Quo(d, five)
+		fives++
+	}
+
+	return max(twos, fives)
+}
+
+// canonicalQuantity strips leading zeros from a hex block number
+func canonicalQuantity(value interface{}) interface{} {
+	if s, ok := value.(string); ok && hexutil.IsHex(s) {
+		return hexutil.TrimQuantity(s)
+	}
+	return value
+}
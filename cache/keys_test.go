@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyBuilder_Build(t *testing.T) {
+	kb := NewKeyBuilder(KeyBuilderConfig{Namespace: "proxy", Version: "v1"})
+
+	key, err := kb.Build("ethereum", "mainnet", "eth_getBalance", json.RawMessage(`["0xABCDEF", "latest"]`))
+
+	require.NoError(t, err)
+	assert.Equal(t, `proxy:v1:ethereum:mainnet:eth_getBalance:["0xabcdef","latest"]`, key)
+	assert.True(t, strings.HasPrefix(key, kb.Prefix("ethereum", "mainnet")))
+}
+
+func TestKeyBuilder_Canonicalization(t *testing.T) {
+	kb := NewKeyBuilder(KeyBuilderConfig{})
+
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{name: "object key order", a: `[{"to":"0x1","data":"0x2"}]`, b: `[{"data":"0x2","to":"0x1"}]`},
+		{name: "whitespace", a: `[ "0x1" , true ]`, b: `["0x1",true]`},
+		{name: "hex case", a: `["0xAbCd"]`, b: `["0xabcd"]`},
+		{name: "hex prefix case", a: `["0XABCD"]`, b: `["0xabcd"]`},
+		{name: "nested hex case", a: `[{"address":["0xDEAD"]}]`, b: `[{"address":["0xdead"]}]`},
+		{name: "integral numbers", a: `[1.0, 1e2, -0]`, b: `[1, 100, 0]`},
+		{name: "fractional numbers", a: `[1.50]`, b: `[1.5]`},
+		{name: "large integers", a: `[1e20, 12345678901234567890e2]`, b: `[100000000000000000000, 1234567890123456789000]`},
+		{name: "small fractions", a: `[1e-3, 25E-4]`, b: `[0.001, 0.0025]`},
+		{name: "block quantity zeros", a: `[{"fromBlock":"0x0010","toBlock":"0x000"}]`, b: `[{"fromBlock":"0x10","toBlock":"0x0"}]`},
+		{name: "empty params", a: ``, b: `[]`},
+		{name: "null params", a: `null`, b: `[]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyA, err := kb.Build("ethereum", "mainnet", "eth_call", json.RawMessage(tt.a))
+			require.NoError(t, err)
+			keyB, err := kb.Build("ethereum", "mainnet", "eth_call", json.RawMessage(tt.b))
+			require.NoError(t, err)
+
+			assert.Equal(t, keyA, keyB)
+		})
+	}
+}
+
+func TestKeyBuilder_DistinctRequests(t *testing.T) {
+	kb := NewKeyBuilder(KeyBuilderConfig{})
+
+	keys := map[string]bool{}
+	for _, req := range []struct{ chain, network, method, params string }{
+		{"ethereum", "mainnet", "eth_getBalance", `["0x1","latest"]`},
+		{"ethereum", "mainnet", "eth_getBalance", `["0x1","0x10"]`},
+		{"ethereum", "sepolia", "eth_getBalance", `["0x1","latest"]`},
+		{"polygon", "mainnet", "eth_getBalance", `["0x1","latest"]`},
+		{"ethereum", "mainnet", "eth_getCode", `["0x1","latest"]`},
+		{"ethereum", "mainnet", "eth_getBalance", `["latest"]`},
+		{"ethereum", "mainnet", "eth_getBalance", `[1]`},
+		{"ethereum", "mainnet", "eth_getBalance", `["1"]`},
+		{"ethereum", "mainnet", "eth_getBalance", `["0x0001","latest"]`},
+		{"ethereum", "mainnet", "eth_call", `[1e2000]`},
+		{"ethereum", "mainnet", "eth_call", `[1e2001]`},
+		{"ethereum", "mainnet", "eth_call", `[0.1000000000000000001]`},
+		{"ethereum", "mainnet", "eth_call", `[0.1]`},
+	} {
+		key, err := kb.Build(req.chain, req.network, req.method, json.RawMessage(req.params))
+		require.NoError(t, err)
+		assert.False(t, keys[key], "duplicate key %s", key)
+		keys[key] = true
+	}
+}
+
+func TestKeyBuilder_BlockResolver(t *testing.T) {
+	kb := NewKeyBuilder(KeyBuilderConfig{}, WithBlockResolver(func(tag string) (uint64, bool) {
+		if tag == "latest" {
+			return 100, true
+		}
+		return 0, false
+	}))
+
+	build := func(params string) string {
+		key, err := kb.Build("ethereum", "mainnet", "eth_getLogs", json.RawMessage(params))
+		require.NoError(t, err)
+		return key
+	}
+
+	assert.Equal(t, build(`["0x64"]`), build(`["latest"]`))
+	assert.Equal(t, build(`[{"fromBlock":"0x1","toBlock":"0x64"}]`), build(`[{"fromBlock":"0x1","toBlock":"latest"}]`))
+	assert.Equal(t, build(`[{"blockNumber":"0x64"}]`), build(`[{"blockNumber":"latest"}]`))
+
+	// Unresolved tags and tags outside block positions are kept
+	assert.Contains(t, build(`["pending"]`), `"pending"`)
+	assert.Contains(t, build(`[{"data":"latest"}]`), `"latest"`)
+}
+
+func TestKeyBuilder_HashesLongKeys(t *testing.T) {
+	kb := NewKeyBuilder(KeyBuilderConfig{Namespace: "proxy", MaxKeyLength: 64})
+
+	params := json.RawMessage(`[{"data":"0x` + strings.Repeat("AB", 100) + `"}]`)
+	key, err := kb.Build("ethereum", "mainnet", "eth_call", params)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(key, "proxy:ethereum:mainnet:eth_call:sha256:"))
+	assert.Len(t, key, len("proxy:ethereum:mainnet:eth_call:sha256:")+64)
+
+	// Hashing happens after canonicalization
+	lower, err := kb.Build("ethereum", "mainnet", "eth_call", json.RawMessage(strings.ToLower(string(params))))
+	require.NoError(t, err)
+	assert.Equal(t, key, lower)
+}
+
+func TestKeyBuilder_InvalidParams(t *testing.T) {
+	kb := NewKeyBuilder(KeyBuilderConfig{})
+
+	for _, params := range []string{`[`, `["0x1"] ["0x2"]`, `{"a":}`} {
+		_, err := kb.Build("ethereum", "mainnet", "eth_call", json.RawMessage(params))
+		assert.Error(t, err, params)
+	}
+}
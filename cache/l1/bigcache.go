@@ -2,18 +2,33 @@ package l1
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
 	"time"
 
 	"github.com/allegro/bigcache/v3"
 
 	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/cache/codec"
+	"github.com/status-im/proxy-common/clock"
 	"github.com/status-im/proxy-common/models"
 	"github.com/status-im/proxy-common/scheduler"
 )
 
-// Ensure BigCache implements cache.Cache
+// Ensure BigCache implements cache.Cache, cache.TaggedCache and cache.BatchCache
 var _ cache.Cache = (*BigCache)(nil)
+var _ cache.TaggedCache = (*BigCache)(nil)
+var _ cache.BatchCache = (*BigCache)(nil)
+var _ cache.NegativeCache = (*BigCache)(nil)
+var _ cache.NegativeBatchCache = (*BigCache)(nil)
+var _ cache.StatsReporter = (*BigCache)(nil)
+var _ cache.SizeLimitedCache = (*BigCache)(nil)
+
+// ErrEntryTooLarge is returned when an encoded entry exceeds the configured MaxEntrySize
+// or the limit of a WithMaxEntrySize view
+var ErrEntryTooLarge = errors.New("cache entry too large")
 
 // BigCache implements L1 cache using BigCache
 type BigCache struct {
@@ -22,6 +37,14 @@ type BigCache struct {
 	metrics          cache.MetricsRecorder
 	metricsScheduler *scheduler.Scheduler
 	maxEntrySize     int
+	maxBytes         int64
+	lastStats        bigcache.Stats
+	ttlScale         float64
+	ttlJitter        cache.TTLJitterConfig
+	clock            clock.Clock
+	codec            cache.Codec
+	tags             *tagIndex
+	snapshotPath     string
 }
 
 // Option is a functional option for configuring BigCache
@@ -41,6 +64,31 @@ func WithMetrics(metrics cache.MetricsRecorder) Option {
 	}
 }
 
+// WithCodec sets the codec used to serialize entries for BigCache. It replaces the
+// codec built from the config, so Format and Compression are ignored and compression
+// is only reported through RecordCacheCompression if c does so itself.
+func WithCodec(c cache.Codec) Option {
+	return func(bc *BigCache) {
+		bc.codec = c
+	}
+}
+
+// WithClock sets the clock used to timestamp entries, check their expiry and
+// schedule metrics collection
+func WithClock(c clock.Clock) Option {
+	return func(bc *BigCache) {
+		bc.clock = c
+	}
+}
+
+// WithSnapshotFile restores the cache from the snapshot at path when it is created, if
+// the file exists, and writes a new snapshot there on Close
+func WithSnapshotFile(path string) Option {
+	return func(bc *BigCache) {
+		bc.snapshotPath = path
+	}
+}
+
 // NewBigCache creates a new BigCache instance
 func NewBigCache(cfg *cache.BigCacheConfig, opts ...Option) (cache.Cache, error) {
 	cfg.ApplyDefaults()
@@ -51,22 +99,56 @@ func NewBigCache(cfg *cache.BigCacheConfig, opts ...Option) (cache.Cache, error)
 	config.MaxEntrySize = cfg.MaxEntrySize
 	config.Shards = cfg.Shards
 
-	c, err := bigcache.New(context.Background(), config)
-	if err != nil {
-		return nil, err
-	}
-
 	bc := &BigCache{
-		cache:        c,
 		logger:       cache.NoopLogger{},
 		metrics:      cache.NoopMetrics{},
 		maxEntrySize: cfg.MaxEntrySize,
+		maxBytes:     int64(cfg.Size) * 1024 * 1024,
+		ttlScale:     cfg.TTLScale,
+		ttlJitter:    cfg.TTLJitter,
+		clock:        clock.Real{},
+		tags:         newTagIndex(),
 	}
 
 	for _, opt := range opts {
 		opt(bc)
 	}
 
+	if bc.codec == nil {
+		c, err := codec.New(cfg.Format, cfg.Compression, func(algorithm cache.CompressionAlgorithm, rawBytes, compressedBytes int) {
+			cache.OptionalMetrics[cache.CompressionMetricsRecorder](bc.metrics).
+				RecordCacheCompression("l1", string(algorithm), rawBytes, compressedBytes)
+		})
+		if err != nil {
+			return nil, err
+		}
+		bc.codec = c
+	}
+
+	// Evicted, expired and deleted entries leave the tag index with the entry itself.
+	// Only removals made by BigCache itself count as evictions; the entry's cache type
+	// and network are not known here.
+	statsMetrics := cache.OptionalMetrics[cache.L1StatsMetricsRecorder](bc.metrics)
+	config.OnRemoveWithReason = func(key string, entry []byte, reason bigcache.RemoveReason) {
+		bc.tags.removeEntry(key, entry)
+		statsMetrics.RecordCacheEvictionReason("l1", evictionReason(reason))
+		if reason != bigcache.Deleted {
+			statsMetrics.RecordCacheEviction("l1", "", "", "")
+		}
+	}
+
+	c, err := bigcache.New(context.Background(), config)
+	if err != nil {
+		return nil, err
+	}
+	bc.cache = c
+
+	if bc.snapshotPath != "" {
+		if _, err := bc.RestoreFromFile(bc.snapshotPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			bc.logger.Warn("Failed to restore L1 snapshot", "path", bc.snapshotPath, "error", err)
+		}
+	}
+
 	bc.startMetricsCollection()
 
 	return bc, nil
@@ -74,113 +156,282 @@ func NewBigCache(cfg *cache.BigCacheConfig, opts ...Option) (cache.Cache, error)
 
 // Get retrieves value from cache with freshness information
 func (bc *BigCache) Get(key string) (*models.CacheEntry, bool) {
-	data, err := bc.cache.Get(key)
+	entry, err := bc.get(key)
 	if err != nil {
 		return nil, false
 	}
 
-	var entry models.CacheEntry
-	if err := json.Unmarshal(data, &entry); err != nil {
-		bc.logger.Warn("Failed to unmarshal L1 cache entry", "key", key, "error", err)
-		bc.metrics.RecordCacheError("l1", "decode")
-		_ = bc.cache.Delete(key)
+	return entry, true
+}
+
+// GetStale retrieves value from cache regardless of freshness (for stale-if-error)
+func (bc *BigCache) GetStale(key string) (*models.CacheEntry, bool) {
+	entry, err := bc.get(key)
+	if err != nil {
 		return nil, false
 	}
 
-	if entry.IsExpired() {
-		_ = bc.cache.Delete(key)
+	return entry, true
+}
+
+// GetEntry retrieves a positive or negative entry with freshness information
+func (bc *BigCache) GetEntry(key string) (*models.CacheEntry, bool) {
+	entry, err := bc.getEntry(key)
+	if err != nil {
 		return nil, false
 	}
 
-	return &entry, true
+	return entry, true
 }
 
-// GetStale retrieves value from cache regardless of freshness (for stale-if-error)
-func (bc *BigCache) GetStale(key string) (*models.CacheEntry, bool) {
-	data, err := bc.cache.Get(key)
+// GetStaleEntry retrieves a positive or negative entry regardless of freshness
+func (bc *BigCache) GetStaleEntry(key string) (*models.CacheEntry, bool) {
+	entry, err := bc.getEntry(key)
 	if err != nil {
 		return nil, false
 	}
 
-	var entry models.CacheEntry
-	if err := json.Unmarshal(data, &entry); err != nil {
+	return entry, true
+}
+
+// Set stores value in cache with TTL, clearing any tags the key had
+func (bc *BigCache) Set(key string, val []byte, ttl models.TTL) {
+	if bc.set(key, val, ttl) == nil {
+		bc.tags.remove(key)
+	}
+}
+
+// SetNegative stores a negative entry with TTL, clearing any tags the key had
+func (bc *BigCache) SetNegative(key string, errPayload []byte, ttl models.TTL) {
+	if bc.setNegative(key, errPayload, ttl) == nil {
+		bc.tags.remove(key)
+	}
+}
+
+// GetMany retrieves several values, returning only the keys found
+func (bc *BigCache) GetMany(keys []string) map[string]*models.CacheEntry {
+	return bc.getMany(keys, bc.get)
+}
+
+// GetManyEntries retrieves several positive or negative entries, returning only the keys found
+func (bc *BigCache) GetManyEntries(keys []string) map[string]*models.CacheEntry {
+	return bc.getMany(keys, bc.getEntry)
+}
+
+// getMany reads keys with get, returning only the keys found
+func (bc *BigCache) getMany(keys []string, get func(string) (*models.CacheEntry, error)) map[string]*models.CacheEntry {
+	entries := make(map[string]*models.CacheEntry, len(keys))
+	for _, key := range keys {
+		if entry, err := get(key); err == nil {
+			entries[key] = entry
+		}
+	}
+	return entries
+}
+
+// SetMany stores several values, clearing any tags the keys had
+func (bc *BigCache) SetMany(items []cache.BatchItem) {
+	for _, item := range items {
+		bc.Set(item.Key, item.Val, item.TTL)
+	}
+}
+
+// SetWithTags stores value in cache with TTL and records it under each tag. Tags are
+// only recorded while the entry is still cached, so eviction cannot leave them behind.
+func (bc *BigCache) SetWithTags(key string, val []byte, ttl models.TTL, tags ...string) {
+	data, err := bc.encode(key, models.NewCacheEntry(val, bc.levelTTL(key, ttl), bc.clock.Now()))
+	if err != nil {
+		return
+	}
+
+	p := bc.tags.begin(key, data)
+	err = bc.put(key, data)
+	bc.tags.commit(key, p, tags, err == nil)
+}
+
+// InvalidateTag removes every entry stored with tag, returning the removed keys
+func (bc *BigCache) InvalidateTag(tag string) []string {
+	keys := bc.tags.take(tag)
+	for _, key := range keys {
 		_ = bc.cache.Delete(key)
-		return nil, false
 	}
+	return keys
+}
+
+// InvalidatePrefix removes every entry whose key starts with prefix, returning the removed keys
+func (bc *BigCache) InvalidatePrefix(prefix string) []string {
+	var keys []string
+	iter := bc.cache.Iterator()
+	for iter.SetNext() {
+		info, err := iter.Value()
+		if err == nil && strings.HasPrefix(info.Key(), prefix) {
+			keys = append(keys, info.Key())
+		}
+	}
+
+	removed := keys[:0]
+	for _, key := range keys {
+		if bc.cache.Delete(key) == nil {
+			removed = append(removed, key)
+		}
+	}
+	return removed
+}
+
+// Delete removes entry from cache
+func (bc *BigCache) Delete(key string) {
+	_ = bc.cache.Delete(key)
+}
+
+// V2 returns a view of this cache implementing the context-aware cache.CacheV2 interface
+func (bc *BigCache) V2() *BigCacheV2 {
+	return &BigCacheV2{bc: bc}
+}
+
+// WithMaxEntrySize returns a view of the cache, sharing its storage and tags, that skips
+// entries whose encoded size exceeds size bytes, or the configured MaxEntrySize if lower
+func (bc *BigCache) WithMaxEntrySize(size int) cache.Cache {
+	return bc.withMaxEntrySize(size)
+}
+
+func (bc *BigCache) withMaxEntrySize(size int) *BigCache {
+	limited := *bc
+	limited.maxEntrySize = min(bc.maxEntrySize, size)
+	return &limited
+}
+
+// get reads a positive entry, returning cache.ErrCacheMiss if absent, expired or negative
+func (bc *BigCache) get(key string) (*models.CacheEntry, error) {
+	entry, err := bc.getEntry(key)
+	if err == nil && entry.IsNegative() {
+		return nil, cache.ErrCacheMiss
+	}
+	return entry, err
+}
 
-	if entry.IsExpired() {
+// getEntry reads and decodes an entry, returning cache.ErrCacheMiss if absent or expired
+func (bc *BigCache) getEntry(key string) (*models.CacheEntry, error) {
+	data, err := bc.cache.Get(key)
+	if err != nil {
+		if errors.Is(err, bigcache.ErrEntryNotFound) {
+			return nil, cache.ErrCacheMiss
+		}
+		return nil, err
+	}
+
+	entry, err := bc.codec.Decode(data)
+	if err != nil {
+		bc.logger.Warn("Failed to decode L1 cache entry", "key", key, "error", err)
+		bc.metrics.RecordCacheError("l1", "decode")
 		_ = bc.cache.Delete(key)
-		return nil, false
+		return nil, fmt.Errorf("failed to decode L1 cache entry: %w", err)
+	}
+
+	if entry.IsExpiredAt(bc.clock.Now()) {
+		_ = bc.cache.Delete(key)
+		return nil, cache.ErrCacheMiss
 	}
 
-	return &entry, true
+	return entry, nil
 }
 
-// Set stores value in cache with TTL
-func (bc *BigCache) Set(key string, val []byte, ttl models.TTL) {
-	now := time.Now().Unix()
+// set stores val as a positive entry
+func (bc *BigCache) set(key string, val []byte, ttl models.TTL) error {
+	return bc.store(key, models.NewCacheEntry(val, bc.levelTTL(key, ttl), bc.clock.Now()))
+}
+
+// setNegative stores a negative entry carrying errPayload
+func (bc *BigCache) setNegative(key string, errPayload []byte, ttl models.TTL) error {
+	return bc.store(key, models.NewNegativeCacheEntry(errPayload, bc.levelTTL(key, ttl), bc.clock.Now()))
+}
+
+// levelTTL applies this level's TTL scale and jitter to ttl
+func (bc *BigCache) levelTTL(key string, ttl models.TTL) models.TTL {
+	return bc.ttlJitter.Apply(key, cache.ScaleTTL(ttl, bc.ttlScale))
+}
 
-	entry := models.CacheEntry{
-		Data:      val,
-		CreatedAt: now,
-		StaleAt:   now + int64(ttl.Fresh.Seconds()),
-		ExpiresAt: now + int64(ttl.Fresh.Seconds()) + int64(ttl.Stale.Seconds()),
+// store encodes and stores an entry, logging and recording any failure
+func (bc *BigCache) store(key string, entry *models.CacheEntry) error {
+	data, err := bc.encode(key, entry)
+	if err != nil {
+		return err
 	}
+	return bc.put(key, data)
+}
 
-	data, err := json.Marshal(entry)
+// encode serializes an entry, logging and recording any failure
+func (bc *BigCache) encode(key string, entry *models.CacheEntry) ([]byte, error) {
+	data, err := bc.codec.Encode(entry)
 	if err != nil {
-		bc.logger.Error("Failed to marshal cache entry", "key", key, "error", err)
+		bc.logger.Error("Failed to encode cache entry", "key", key, "error", err)
 		bc.metrics.RecordCacheError("l1", "encode")
-		return
+		return nil, fmt.Errorf("failed to encode L1 cache entry: %w", err)
 	}
+	return data, nil
+}
 
+// put stores an encoded entry, logging and recording any failure
+func (bc *BigCache) put(key string, data []byte) error {
 	if len(data) > bc.maxEntrySize {
 		bc.logger.Warn("Cache entry too large, skipping L1 cache",
 			"key", key,
 			"size", len(data),
 			"max_size", bc.maxEntrySize)
 		bc.metrics.RecordCacheError("l1", "entry_too_large")
-		return
+		return ErrEntryTooLarge
 	}
 
-	err = bc.cache.Set(key, data)
-	if err != nil {
+	if err := bc.cache.Set(key, data); err != nil {
 		bc.logger.Error("Failed to set cache entry", "key", key, "error", err)
 		bc.metrics.RecordCacheError("l1", "upstream")
-		return
+		return err
 	}
-}
 
-// Delete removes entry from cache
-func (bc *BigCache) Delete(key string) {
-	_ = bc.cache.Delete(key)
+	return nil
 }
 
-// Close closes the cache
+// Close writes the snapshot file, if configured, and closes the cache
 func (bc *BigCache) Close() error {
 	bc.stopMetricsCollection()
 
-	return bc.cache.Close()
+	var snapshotErr error
+	if bc.snapshotPath != "" {
+		if _, snapshotErr = bc.SnapshotToFile(bc.snapshotPath); snapshotErr != nil {
+			bc.logger.Warn("Failed to write L1 snapshot", "path", bc.snapshotPath, "error", snapshotErr)
+		}
+	}
+
+	return errors.Join(snapshotErr, bc.cache.Close())
 }
 
-// GetStats returns cache statistics for metrics
+// GetStats returns the configured capacity and the bytes currently allocated to entries
 func (bc *BigCache) GetStats() (capacity, used int64) {
-	stats := bc.cache.Stats()
-	// BigCache doesn't expose exact capacity, but we can use the configured size
-	// Convert from MB to bytes
-	capacity = int64(bc.cache.Capacity())   // This returns the configured size in bytes
-	used = int64(stats.Hits + stats.Misses) // Approximate usage based on operations
+	return bc.maxBytes, int64(bc.cache.Capacity())
+}
 
-	return capacity, used
+// Stats returns the key count, capacity, allocated bytes and BigCache's hit, miss,
+// collision and delete counters since the cache was created
+func (bc *BigCache) Stats() cache.LevelStats {
+	capacity, used := bc.GetStats()
+	stats := bc.cache.Stats()
+	return cache.LevelStats{
+		Keys:          int64(bc.cache.Len()),
+		CapacityBytes: capacity,
+		UsedBytes:     used,
+		Hits:          stats.Hits,
+		Misses:        stats.Misses,
+		Collisions:    stats.Collisions,
+		DeleteHits:    stats.DelHits,
+	}
 }
 
 // startMetricsCollection starts periodic metrics collection
 func (bc *BigCache) startMetricsCollection() {
-	bc.metricsScheduler = scheduler.New(30*time.Second, bc.updateMetrics)
-	bc.metricsScheduler.Start()
-
 	bc.updateMetrics()
 
+	bc.metricsScheduler = scheduler.New(30*time.Second, bc.updateMetrics, scheduler.WithClock(bc.clock))
+	bc.metricsScheduler.Start()
+
 	bc.logger.Debug("Started L1 cache metrics collection")
 }
 
@@ -192,13 +443,126 @@ func (bc *BigCache) stopMetricsCollection() {
 	}
 }
 
-// updateMetrics updates cache metrics
+// updateMetrics reports capacity, key count and the collisions and delete hits
+// since the previous update. It only runs on the metrics scheduler.
 func (bc *BigCache) updateMetrics() {
 	capacity, used := bc.GetStats()
-
 	bc.metrics.UpdateL1CacheCapacity(capacity, used)
+	bc.metrics.UpdateCacheKeys("l1", int64(bc.cache.Len()))
 
 	stats := bc.cache.Stats()
-	totalOps := stats.Hits + stats.Misses
-	bc.metrics.UpdateCacheKeys("l1", int64(totalOps))
+	cache.OptionalMetrics[cache.L1StatsMetricsRecorder](bc.metrics).
+		RecordL1CacheStats(stats.Collisions-bc.lastStats.Collisions, stats.DelHits-bc.lastStats.DelHits)
+	bc.lastStats = stats
+}
+
+// evictionReason maps a BigCache removal reason to a cache.Eviction* reason
+func evictionReason(reason bigcache.RemoveReason) string {
+	switch reason {
+	case bigcache.Expired:
+		return cache.EvictionExpired
+	case bigcache.NoSpace:
+		return cache.EvictionNoSpace
+	default:
+		return cache.EvictionDeleted
+	}
+}
+
+// BigCacheV2 exposes a BigCache through the context-aware cache.CacheV2 interface.
+// Operations are in-memory, so the context is only checked for cancellation up front.
+type BigCacheV2 struct {
+	bc *BigCache
+}
+
+// Ensure BigCacheV2 implements cache.CacheV2
+var _ cache.CacheV2 = (*BigCacheV2)(nil)
+var _ cache.NegativeCacheV2 = (*BigCacheV2)(nil)
+var _ cache.SizeLimitedCacheV2 = (*BigCacheV2)(nil)
+
+// NewBigCacheV2 creates a new context-aware BigCache instance
+func NewBigCacheV2(cfg *cache.BigCacheConfig, opts ...Option) (cache.CacheV2, error) {
+	c, err := NewBigCache(cfg, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.(*BigCache).V2(), nil
+}
+
+// Get retrieves value from cache, returning cache.ErrCacheMiss if absent or expired
+func (c *BigCacheV2) Get(ctx context.Context, key string) (*models.CacheEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.bc.get(key)
+}
+
+// GetStale retrieves value from cache regardless of freshness (for stale-if-error)
+func (c *BigCacheV2) GetStale(ctx context.Context, key string) (*models.CacheEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.bc.get(key)
+}
+
+// GetEntry retrieves a positive or negative entry, returning cache.ErrCacheMiss if
+// absent or expired
+func (c *BigCacheV2) GetEntry(ctx context.Context, key string) (*models.CacheEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.bc.getEntry(key)
+}
+
+// GetStaleEntry retrieves a positive or negative entry regardless of freshness
+func (c *BigCacheV2) GetStaleEntry(ctx context.Context, key string) (*models.CacheEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.bc.getEntry(key)
+}
+
+// Set stores value in cache with TTL
+func (c *BigCacheV2) Set(ctx context.Context, key string, val []byte, ttl models.TTL) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := c.bc.set(key, val, ttl); err != nil {
+		return err
+	}
+	c.bc.tags.remove(key)
+	return nil
+}
+
+// SetNegative stores a negative entry with TTL
+func (c *BigCacheV2) SetNegative(ctx context.Context, key string, errPayload []byte, ttl models.TTL) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := c.bc.setNegative(key, errPayload, ttl); err != nil {
+		return err
+	}
+	c.bc.tags.remove(key)
+	return nil
+}
+
+// Delete removes entry from cache. Deleting a missing key is not an error.
+func (c *BigCacheV2) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := c.bc.cache.Delete(key); err != nil && !errors.Is(err, bigcache.ErrEntryNotFound) {
+		return err
+	}
+	return nil
+}
+
+// WithMaxEntrySize is the context-aware counterpart of BigCache.WithMaxEntrySize
+func (c *BigCacheV2) WithMaxEntrySize(size int) cache.CacheV2 {
+	return c.bc.withMaxEntrySize(size).V2()
+}
+
+// Close closes the cache
+func (c *BigCacheV2) Close() error {
+	return c.bc.Close()
 }
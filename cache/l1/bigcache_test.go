@@ -1,14 +1,22 @@
 package l1
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"math/rand"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/allegro/bigcache/v3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 
 	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/cache/mock"
+	"github.com/status-im/proxy-common/clock"
 	"github.com/status-im/proxy-common/models"
 )
 
@@ -316,3 +324,387 @@ func TestBigCache_Edge_Cases(t *testing.T) {
 		assert.Nil(t, result.Data)
 	})
 }
+
+func TestBigCacheV2(t *testing.T) {
+	c, err := NewBigCacheV2(createTestBigCacheConfig())
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	testTTL := models.TTL{Fresh: 60 * time.Second, Stale: 30 * time.Second}
+
+	t.Run("miss returns ErrCacheMiss", func(t *testing.T) {
+		result, err := c.Get(ctx, "missing-key")
+		assert.ErrorIs(t, err, cache.ErrCacheMiss)
+		assert.Nil(t, result)
+	})
+
+	t.Run("set then get", func(t *testing.T) {
+		assert.NoError(t, c.Set(ctx, "test-key", []byte("test-value"), testTTL))
+
+		result, err := c.Get(ctx, "test-key")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("test-value"), result.Data)
+
+		result, err = c.GetStale(ctx, "test-key")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("test-value"), result.Data)
+	})
+
+	t.Run("delete missing key is not an error", func(t *testing.T) {
+		assert.NoError(t, c.Delete(ctx, "test-key"))
+		assert.NoError(t, c.Delete(ctx, "test-key"))
+	})
+
+	t.Run("entry too large", func(t *testing.T) {
+		small, err := NewBigCacheV2(&cache.BigCacheConfig{Size: 10, MaxEntrySize: 64})
+		assert.NoError(t, err)
+
+		err = small.Set(ctx, "large-key", make([]byte, 128), testTTL)
+		assert.ErrorIs(t, err, ErrEntryTooLarge)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := c.Get(cancelled, "test-key")
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestBigCache_Compression_FitsLargeEntries(t *testing.T) {
+	cfg := &cache.BigCacheConfig{
+		Size:         10,
+		MaxEntrySize: 1024,
+		Format:       cache.EntryFormatBinary,
+		Compression:  cache.CompressionConfig{Algorithm: cache.CompressionZstd, Threshold: 256},
+	}
+	c, err := NewBigCache(cfg)
+	assert.NoError(t, err)
+
+	largeValue := []byte(strings.Repeat(`{"blockNumber":"0x10d4f","logIndex":"0x1"},`, 100))
+	assert.Greater(t, len(largeValue), cfg.MaxEntrySize)
+
+	c.Set("large-key", largeValue, models.TTL{Fresh: 60 * time.Second})
+	result, found := c.Get("large-key")
+
+	assert.True(t, found)
+	assert.Equal(t, largeValue, result.Data)
+}
+
+func TestBigCache_WithMaxEntrySize(t *testing.T) {
+	cfg := &cache.BigCacheConfig{
+		Size:         10,
+		MaxEntrySize: 4096,
+		Format:       cache.EntryFormatBinary,
+		Compression:  cache.CompressionConfig{Algorithm: cache.CompressionZstd, Threshold: 256},
+	}
+	c, err := NewBigCache(cfg)
+	require.NoError(t, err)
+	view := c.(*BigCache).WithMaxEntrySize(512)
+	ttl := models.TTL{Fresh: time.Minute}
+
+	// The limit applies to the compressed entry, not the raw value
+	compressible := []byte(strings.Repeat(`{"blockNumber":"0x10d4f","logIndex":"0x1"},`, 50))
+	assert.Greater(t, len(compressible), 512)
+	view.Set("compressible", compressible, ttl)
+	_, found := c.Get("compressible")
+	assert.True(t, found)
+
+	incompressible := make([]byte, 1024)
+	rand.New(rand.NewSource(1)).Read(incompressible)
+	view.Set("incompressible", incompressible, ttl)
+	_, found = c.Get("incompressible")
+	assert.False(t, found)
+
+	// The cache itself keeps its configured limit
+	c.Set("incompressible", incompressible, ttl)
+	_, found = view.Get("incompressible")
+	assert.True(t, found)
+}
+
+func TestNewBigCache_RejectsInvalidCompression(t *testing.T) {
+	cfg := createTestBigCacheConfig()
+	cfg.Format = cache.EntryFormatBinary
+	cfg.Compression.Algorithm = "lz4"
+
+	c, err := NewBigCache(cfg)
+
+	assert.Error(t, err)
+	assert.Nil(t, c)
+}
+
+func TestBigCache_InvalidateTag(t *testing.T) {
+	c, err := NewBigCache(createTestBigCacheConfig())
+	assert.NoError(t, err)
+	bc := c.(*BigCache)
+
+	ttl := models.TTL{Fresh: time.Minute}
+	bc.SetWithTags("block:100:a", []byte("a"), ttl, "block:100", "chain:1")
+	bc.SetWithTags("block:100:b", []byte("b"), ttl, "block:100")
+	bc.SetWithTags("block:101:a", []byte("c"), ttl, "block:101", "chain:1")
+
+	removed := bc.InvalidateTag("block:100")
+
+	assert.ElementsMatch(t, []string{"block:100:a", "block:100:b"}, removed)
+	_, found := bc.Get("block:100:a")
+	assert.False(t, found)
+	_, found = bc.Get("block:100:b")
+	assert.False(t, found)
+	_, found = bc.Get("block:101:a")
+	assert.True(t, found)
+
+	// Removed keys no longer belong to their other tags
+	assert.Equal(t, []string{"block:101:a"}, bc.InvalidateTag("chain:1"))
+	assert.Empty(t, bc.InvalidateTag("block:100"))
+}
+
+func TestBigCache_InvalidateTag_UntaggedByOverwriteAndDelete(t *testing.T) {
+	c, err := NewBigCache(createTestBigCacheConfig())
+	assert.NoError(t, err)
+	bc := c.(*BigCache)
+
+	ttl := models.TTL{Fresh: time.Minute}
+	bc.SetWithTags("overwritten", []byte("a"), ttl, "tag")
+	bc.SetWithTags("deleted", []byte("b"), ttl, "tag")
+
+	bc.Set("overwritten", []byte("untagged"), ttl)
+	bc.Delete("deleted")
+
+	assert.Empty(t, bc.InvalidateTag("tag"))
+	result, found := bc.Get("overwritten")
+	assert.True(t, found)
+	assert.Equal(t, []byte("untagged"), result.Data)
+}
+
+func TestTagIndex_EntryRemovedBeforeCommit(t *testing.T) {
+	ti := newTagIndex()
+
+	// The entry is evicted between being stored and its tags being recorded
+	p := ti.begin("key", []byte("new"))
+	ti.removeEntry("key", []byte("new"))
+	ti.commit("key", p, []string{"tag"}, true)
+	assert.Empty(t, ti.take("tag"))
+
+	// Removing the value the write replaces does not drop the new tags
+	p = ti.begin("key", []byte("new"))
+	ti.removeEntry("key", []byte("old"))
+	ti.commit("key", p, []string{"tag"}, true)
+	assert.Equal(t, []string{"key"}, ti.take("tag"))
+
+	// Only the latest tagged write of a key commits
+	first := ti.begin("key", []byte("first"))
+	second := ti.begin("key", []byte("second"))
+	ti.commit("key", second, []string{"second"}, true)
+	ti.commit("key", first, []string{"first"}, true)
+	assert.Empty(t, ti.take("first"))
+	assert.Equal(t, []string{"key"}, ti.take("second"))
+	assert.Empty(t, ti.pending)
+}
+
+func TestBigCache_InvalidatePrefix(t *testing.T) {
+	c, err := NewBigCache(createTestBigCacheConfig())
+	assert.NoError(t, err)
+	bc := c.(*BigCache)
+
+	ttl := models.TTL{Fresh: time.Minute}
+	bc.Set("chain:1:block:100", []byte("a"), ttl)
+	bc.Set("chain:1:block:101", []byte("b"), ttl)
+	bc.Set("chain:10:block:100", []byte("c"), ttl)
+
+	removed := bc.InvalidatePrefix("chain:1:")
+
+	assert.ElementsMatch(t, []string{"chain:1:block:100", "chain:1:block:101"}, removed)
+	_, found := bc.Get("chain:1:block:100")
+	assert.False(t, found)
+	_, found = bc.Get("chain:10:block:100")
+	assert.True(t, found)
+}
+
+func TestBigCache_GetMany_SetMany(t *testing.T) {
+	c, err := NewBigCache(createTestBigCacheConfig())
+	assert.NoError(t, err)
+	bc := c.(*BigCache)
+
+	ttl := models.TTL{Fresh: time.Minute}
+	bc.SetMany([]cache.BatchItem{
+		{Key: "a", Val: []byte("1"), TTL: ttl},
+		{Key: "b", Val: []byte("2"), TTL: ttl},
+	})
+
+	entries := bc.GetMany([]string{"a", "b", "missing"})
+
+	assert.Len(t, entries, 2)
+	assert.Equal(t, []byte("1"), entries["a"].Data)
+	assert.Equal(t, []byte("2"), entries["b"].Data)
+	assert.NotContains(t, entries, "missing")
+}
+
+func TestBigCache_TTLScale(t *testing.T) {
+	cfg := createTestBigCacheConfig()
+	cfg.TTLScale = 0.5
+	c, err := NewBigCache(cfg)
+	assert.NoError(t, err)
+
+	c.Set("test-key", []byte("value"), models.TTL{Fresh: 2 * time.Minute, Stale: 4 * time.Minute})
+
+	entry, found := c.Get("test-key")
+	assert.True(t, found)
+	assert.Equal(t, int64(60000), entry.StaleAtMs-entry.CreatedAtMs)
+	assert.Equal(t, int64(180000), entry.ExpiresAtMs-entry.CreatedAtMs)
+}
+
+func TestBigCache_TTLJitter(t *testing.T) {
+	mockClock := clock.NewMock(time.Unix(1700000000, 0))
+	cfg := createTestBigCacheConfig()
+	cfg.TTLJitter = cache.TTLJitterConfig{Percent: 20, Deterministic: true}
+	c, err := NewBigCache(cfg, WithClock(mockClock))
+	assert.NoError(t, err)
+
+	ttl := models.TTL{Fresh: time.Hour, Stale: time.Hour}
+	for _, key := range []string{"key-a", "key-b", "key-c"} {
+		c.Set(key, []byte("value"), ttl)
+
+		want := cfg.TTLJitter.Apply(key, ttl)
+		entry, found := c.Get(key)
+		assert.True(t, found)
+		assert.Equal(t, mockClock.Now(), entry.CreatedTime())
+		assert.Equal(t, want.Fresh.Milliseconds(), entry.StaleAtMs-entry.CreatedAtMs)
+		assert.Equal(t, want.Stale.Milliseconds(), entry.ExpiresAtMs-entry.StaleAtMs)
+		assert.LessOrEqual(t, entry.StaleAtMs, mockClock.Now().Add(ttl.Fresh).UnixMilli())
+		assert.GreaterOrEqual(t, entry.StaleAtMs, mockClock.Now().Add(48*time.Minute).UnixMilli())
+	}
+}
+
+func TestBigCache_SubSecondTTL(t *testing.T) {
+	mockClock := clock.NewMock(time.Unix(1700000000, 0))
+	c, err := NewBigCache(createTestBigCacheConfig(), WithClock(mockClock))
+	assert.NoError(t, err)
+
+	c.Set("test-key", []byte("value"), models.TTL{Fresh: 200 * time.Millisecond, Stale: 300 * time.Millisecond})
+
+	mockClock.Advance(100 * time.Millisecond)
+	entry, found := c.Get("test-key")
+	assert.True(t, found)
+	assert.True(t, entry.IsFreshAt(mockClock.Now()))
+	assert.Equal(t, models.TTL{Fresh: 100 * time.Millisecond, Stale: 300 * time.Millisecond}, entry.RemainingTTLAt(mockClock.Now()))
+
+	mockClock.Advance(200 * time.Millisecond)
+	entry, found = c.Get("test-key")
+	assert.True(t, found)
+	assert.False(t, entry.IsFreshAt(mockClock.Now()))
+
+	mockClock.Advance(201 * time.Millisecond)
+	_, found = c.Get("test-key")
+	assert.False(t, found)
+}
+
+func TestBigCache_SetNegative(t *testing.T) {
+	mockClock := clock.NewMock(time.Unix(1700000000, 0))
+	c, err := NewBigCache(createTestBigCacheConfig(), WithClock(mockClock))
+	require.NoError(t, err)
+	bc := c.(*BigCache)
+
+	errPayload := []byte(`{"code":-32000,"message":"not found"}`)
+	bc.SetWithTags("tx-key", []byte("old"), models.TTL{Fresh: time.Minute}, "block:1")
+	bc.SetNegative("tx-key", errPayload, models.TTL{Fresh: 5 * time.Second})
+
+	_, found := bc.Get("tx-key")
+	assert.False(t, found, "Get must report negative entries as misses")
+	assert.Empty(t, bc.GetMany([]string{"tx-key"}))
+
+	entry, found := bc.GetEntry("tx-key")
+	require.True(t, found)
+	assert.True(t, entry.IsNegative())
+	assert.Equal(t, errPayload, entry.Error)
+	assert.Nil(t, entry.Data)
+	assert.Contains(t, bc.GetManyEntries([]string{"tx-key"}), "tx-key")
+	assert.Empty(t, bc.InvalidateTag("block:1"), "negative entry should clear old tags")
+
+	mockClock.Advance(6 * time.Second)
+	_, found = bc.GetStaleEntry("tx-key")
+	assert.False(t, found)
+
+	t.Run("v2", func(t *testing.T) {
+		v2 := bc.V2()
+		require.NoError(t, v2.SetNegative(context.Background(), "v2-key", nil, models.TTL{Fresh: time.Second}))
+
+		_, err := v2.Get(context.Background(), "v2-key")
+		assert.ErrorIs(t, err, cache.ErrCacheMiss)
+
+		entry, err := v2.GetEntry(context.Background(), "v2-key")
+		require.NoError(t, err)
+		assert.True(t, entry.IsNegative())
+		assert.Nil(t, entry.Error)
+	})
+}
+
+// l1Metrics is a mock metrics recorder that also records L1 stats
+type l1Metrics struct {
+	*mock.MockMetricsRecorder
+	*mock.MockL1StatsMetricsRecorder
+}
+
+func TestBigCache_Metrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockMetrics := mock.NewMockMetricsRecorder(ctrl)
+	statsMetrics := mock.NewMockL1StatsMetricsRecorder(ctrl)
+	capacity := int64(10 * 1024 * 1024)
+
+	// Collected once on construction
+	mockMetrics.EXPECT().UpdateL1CacheCapacity(capacity, gomock.Any())
+	mockMetrics.EXPECT().UpdateCacheKeys("l1", int64(0))
+	statsMetrics.EXPECT().RecordL1CacheStats(int64(0), int64(0))
+
+	c, err := NewBigCache(createTestBigCacheConfig(), WithMetrics(l1Metrics{mockMetrics, statsMetrics}))
+	require.NoError(t, err)
+	bc := c.(*BigCache)
+	defer bc.Close()
+
+	bc.Set("a", []byte("1"), models.TTL{Fresh: time.Minute})
+	bc.Set("b", []byte("2"), models.TTL{Fresh: time.Minute})
+
+	statsMetrics.EXPECT().RecordCacheEvictionReason("l1", cache.EvictionDeleted)
+	bc.Delete("a")
+
+	gotCapacity, used := bc.GetStats()
+	assert.Equal(t, capacity, gotCapacity)
+	assert.Greater(t, used, int64(0))
+
+	mockMetrics.EXPECT().UpdateL1CacheCapacity(capacity, used)
+	mockMetrics.EXPECT().UpdateCacheKeys("l1", int64(1))
+	statsMetrics.EXPECT().RecordL1CacheStats(int64(0), int64(1))
+	bc.updateMetrics()
+
+	// Only the delete hits since the previous update are recorded
+	mockMetrics.EXPECT().UpdateL1CacheCapacity(capacity, used)
+	mockMetrics.EXPECT().UpdateCacheKeys("l1", int64(1))
+	statsMetrics.EXPECT().RecordL1CacheStats(int64(0), int64(0))
+	bc.updateMetrics()
+}
+
+func TestBigCache_EvictionNoSpace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockMetrics := mock.NewMockMetricsRecorder(ctrl)
+	statsMetrics := mock.NewMockL1StatsMetricsRecorder(ctrl)
+	mockMetrics.EXPECT().UpdateL1CacheCapacity(gomock.Any(), gomock.Any()).AnyTimes()
+	mockMetrics.EXPECT().UpdateCacheKeys(gomock.Any(), gomock.Any()).AnyTimes()
+	statsMetrics.EXPECT().RecordL1CacheStats(gomock.Any(), gomock.Any()).AnyTimes()
+	statsMetrics.EXPECT().RecordCacheEvictionReason("l1", cache.EvictionNoSpace).MinTimes(1)
+	statsMetrics.EXPECT().RecordCacheEviction("l1", "", "", "").MinTimes(1)
+
+	c, err := NewBigCache(&cache.BigCacheConfig{Size: 1, Shards: 1, MaxEntrySize: 4096}, WithMetrics(l1Metrics{mockMetrics, statsMetrics}))
+	require.NoError(t, err)
+	defer c.(*BigCache).Close()
+
+	value := make([]byte, 1024)
+	for i := 0; i < 2048; i++ {
+		c.Set(fmt.Sprintf("key-%d", i), value, models.TTL{Fresh: time.Minute})
+	}
+}
+
+func TestEvictionReason(t *testing.T) {
+	assert.Equal(t, cache.EvictionExpired, evictionReason(bigcache.Expired))
+	assert.Equal(t, cache.EvictionNoSpace, evictionReason(bigcache.NoSpace))
+	assert.Equal(t, cache.EvictionDeleted, evictionReason(bigcache.Deleted))
+}
@@ -0,0 +1,254 @@
+package l1
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/models"
+)
+
+const (
+	// snapshotMagic starts every snapshot stream
+	snapshotMagic = "PCL1SNAP"
+	// snapshotVersion is the snapshot stream format version
+	snapshotVersion byte = 1
+	// maxSnapshotKeySize bounds the key length accepted from a snapshot
+	maxSnapshotKeySize = 64 * 1024
+	// warmUpBatchSize is the number of keys read from the source per GetMany
+	warmUpBatchSize = 100
+)
+
+// ErrInvalidSnapshot is returned when a snapshot stream is not in the expected format
+var ErrInvalidSnapshot = errors.New("invalid L1 snapshot")
+
+// Snapshot writes every unexpired entry to w and returns the number written. The stream
+// is a header followed by one record per entry: uvarint key length, key, uvarint entry
+// length and the entry encoded by the cache's codec. Tags are not included.
+func (bc *BigCache) Snapshot(w io.Writer) (int, error) {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(snapshotMagic); err != nil {
+		return 0, err
+	}
+	if err := bw.WriteByte(snapshotVersion); err != nil {
+		return 0, err
+	}
+
+	now := bc.clock.Now()
+	written := 0
+	iter := bc.cache.Iterator()
+	for iter.SetNext() {
+		info, err := iter.Value()
+		if err != nil {
+			continue
+		}
+
+		data := info.Value()
+		entry, err := bc.codec.Decode(data)
+		if err != nil || entry.IsExpiredAt(now) {
+			continue
+		}
+
+		if err := writeSnapshotRecord(bw, info.Key(), data); err != nil {
+			return written, err
+		}
+		written++
+	}
+
+	return written, bw.Flush()
+}
+
+// writeSnapshotRecord writes one key and encoded entry
+func writeSnapshotRecord(bw *bufio.Writer, key string, data []byte) error {
+	var lenBuf [binary.MaxVarintLen64]byte
+	if _, err := bw.Write(lenBuf[:binary.PutUvarint(lenBuf[:], uint64(len(key)))]); err != nil {
+		return err
+	}
+	if _, err := bw.WriteString(key); err != nil {
+		return err
+	}
+	if _, err := bw.Write(lenBuf[:binary.PutUvarint(lenBuf[:], uint64(len(data)))]); err != nil {
+		return err
+	}
+	_, err := bw.Write(data)
+	return err
+}
+
+// Restore loads the entries of a snapshot written by Snapshot, keeping their original
+// timestamps, and returns the number restored. Entries that expired since the snapshot
+// was taken, or no longer fit in the cache, are skipped.
+func (bc *BigCache) Restore(r io.Reader) (int, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, len(snapshotMagic)+1)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	if string(header[:len(snapshotMagic)]) != snapshotMagic {
+		return 0, fmt.Errorf("%w: bad magic", ErrInvalidSnapshot)
+	}
+	if header[len(snapshotMagic)] != snapshotVersion {
+		return 0, fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, header[len(snapshotMagic)])
+	}
+
+	restored := 0
+	for {
+		key, data, err := bc.readSnapshotRecord(br)
+		if errors.Is(err, io.EOF) {
+			return restored, nil
+		}
+		if err != nil {
+			return restored, err
+		}
+		if data == nil {
+			continue
+		}
+
+		entry, err := bc.codec.Decode(data)
+		if err != nil || entry.IsExpiredAt(bc.clock.Now()) {
+			continue
+		}
+		if bc.store(key, entry) == nil {
+			restored++
+		}
+	}
+}
+
+// readSnapshotRecord reads one key and encoded entry, returning io.EOF at the end of
+// the stream. Entries larger than MaxEntrySize are skipped and returned as nil data.
+func (bc *BigCache) readSnapshotRecord(br *bufio.Reader) (string, []byte, error) {
+	keyLen, err := binary.ReadUvarint(br)
+	if errors.Is(err, io.EOF) {
+		return "", nil, io.EOF
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	if keyLen > maxSnapshotKeySize {
+		return "", nil, fmt.Errorf("%w: key length %d", ErrInvalidSnapshot, keyLen)
+	}
+	key := make([]byte, keyLen)
+	if _, err := io.ReadFull(br, key); err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+
+	dataLen, err := binary.ReadUvarint(br)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	if dataLen > uint64(bc.maxEntrySize) {
+		if _, err := io.CopyN(io.Discard, br, int64(dataLen)); err != nil {
+			return "", nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+		}
+		return string(key), nil, nil
+	}
+	data := make([]byte, dataLen)
+	if _, err := io.ReadFull(br, data); err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+
+	return string(key), data, nil
+}
+
+// SnapshotToFile writes a snapshot to path, replacing it atomically once complete
+func (bc *BigCache) SnapshotToFile(path string) (int, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	written, err := bc.Snapshot(f)
+	if err != nil {
+		_ = f.Close()
+		return 0, err
+	}
+	if err := f.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return 0, err
+	}
+
+	bc.logger.Info("Wrote L1 snapshot", "path", path, "entries", written)
+	return written, nil
+}
+
+// RestoreFromFile loads a snapshot written by SnapshotToFile. A missing file is
+// reported as an error matching fs.ErrNotExist.
+func (bc *BigCache) RestoreFromFile(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	restored, err := bc.Restore(f)
+	if err != nil {
+		return restored, fmt.Errorf("failed to restore L1 snapshot %s: %w", path, err)
+	}
+
+	bc.logger.Info("Restored L1 snapshot", "path", path, "entries", restored)
+	return restored, nil
+}
+
+// WarmUp copies the given hot keys from source, typically the L2 cache, and returns the
+// number of entries stored. Entries are written like propagated ones, with their
+// remaining TTL and this level's TTL scale and jitter applied. Keys are read in
+// batches through GetMany when source implements cache.BatchCache. Missing and expired
+// entries are skipped, and ctx is checked between batches.
+func (bc *BigCache) WarmUp(ctx context.Context, source cache.Cache, keys []string) (int, error) {
+	warmed := 0
+	for start := 0; start < len(keys); start += warmUpBatchSize {
+		if err := ctx.Err(); err != nil {
+			return warmed, err
+		}
+
+		batch := keys[start:min(start+warmUpBatchSize, len(keys))]
+		now := bc.clock.Now()
+		for key, entry := range fetch(source, batch) {
+			if entry == nil || entry.IsExpiredAt(now) {
+				continue
+			}
+			remaining := entry.RemainingTTLAt(now)
+			if remaining.Fresh <= 0 && remaining.Stale <= 0 {
+				continue
+			}
+
+			var err error
+			if entry.IsNegative() {
+				err = bc.setNegative(key, entry.Error, remaining)
+			} else {
+				err = bc.set(key, entry.Data, remaining)
+			}
+			if err == nil {
+				warmed++
+			}
+		}
+	}
+
+	bc.logger.Info("Warmed up L1 cache", "keys", len(keys), "entries", warmed)
+	return warmed, nil
+}
+
+// fetch reads keys from source with one GetMany if it supports batches, and with
+// per-key Get otherwise
+func fetch(source cache.Cache, keys []string) map[string]*models.CacheEntry {
+	if batch, ok := source.(cache.BatchCache); ok {
+		return batch.GetMany(keys)
+	}
+
+	entries := make(map[string]*models.CacheEntry, len(keys))
+	for _, key := range keys {
+		if entry, found := source.Get(key); found {
+			entries[key] = entry
+		}
+	}
+	return entries
+}
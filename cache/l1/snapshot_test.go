@@ -0,0 +1,190 @@
+package l1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/cache/mock"
+	"github.com/status-im/proxy-common/clock"
+	"github.com/status-im/proxy-common/models"
+)
+
+func newTestBigCache(t *testing.T, opts ...Option) *BigCache {
+	t.Helper()
+
+	c, err := NewBigCache(createTestBigCacheConfig(), opts...)
+	require.NoError(t, err)
+	return c.(*BigCache)
+}
+
+func TestBigCache_SnapshotRestore(t *testing.T) {
+	clk := clock.NewMock(time.Now())
+	src := newTestBigCache(t, WithClock(clk))
+
+	src.Set("a", []byte("1"), models.TTL{Fresh: time.Minute})
+	src.SetNegative("b", []byte("not found"), models.TTL{Fresh: time.Minute})
+	src.Set("short", []byte("2"), models.TTL{Fresh: time.Second})
+	original, found := src.Get("a")
+	require.True(t, found)
+
+	var buf bytes.Buffer
+	written, err := src.Snapshot(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, 3, written)
+
+	// "short" expires between the snapshot and the restore
+	clk.Advance(2 * time.Second)
+	dst := newTestBigCache(t, WithClock(clk))
+	restored, err := dst.Restore(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, 2, restored)
+
+	entry, found := dst.Get("a")
+	require.True(t, found)
+	assert.Equal(t, original, entry)
+
+	entry, found = dst.GetEntry("b")
+	require.True(t, found)
+	assert.True(t, entry.IsNegative())
+	assert.Equal(t, []byte("not found"), entry.Error)
+
+	_, found = dst.Get("short")
+	assert.False(t, found)
+}
+
+func TestBigCache_Snapshot_SkipsExpired(t *testing.T) {
+	clk := clock.NewMock(time.Now())
+	c := newTestBigCache(t, WithClock(clk))
+
+	c.Set("a", []byte("1"), models.TTL{Fresh: time.Second})
+	c.Set("b", []byte("2"), models.TTL{Fresh: time.Minute})
+	clk.Advance(2 * time.Second)
+
+	var buf bytes.Buffer
+	written, err := c.Snapshot(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, 1, written)
+}
+
+func TestBigCache_Restore_Invalid(t *testing.T) {
+	c := newTestBigCache(t)
+
+	_, err := c.Restore(bytes.NewReader([]byte("not a snapshot")))
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+
+	src := newTestBigCache(t)
+	src.Set("a", []byte("1"), models.TTL{Fresh: time.Minute})
+	var buf bytes.Buffer
+	_, err = src.Snapshot(&buf)
+	require.NoError(t, err)
+
+	truncated := buf.Bytes()[:buf.Len()-1]
+	restored, err := c.Restore(bytes.NewReader(truncated))
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+	assert.Equal(t, 0, restored)
+}
+
+func TestBigCache_WithSnapshotFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "l1.snapshot")
+
+	// A missing snapshot file is not an error
+	first := newTestBigCache(t, WithSnapshotFile(path))
+	first.Set("a", []byte("1"), models.TTL{Fresh: time.Minute})
+	require.NoError(t, first.Close())
+
+	_, err := os.Stat(path)
+	require.NoError(t, err)
+
+	second := newTestBigCache(t, WithSnapshotFile(path))
+	defer second.Close()
+
+	entry, found := second.Get("a")
+	require.True(t, found)
+	assert.Equal(t, []byte("1"), entry.Data)
+}
+
+func TestBigCache_WarmUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	clk := clock.NewMock(now)
+	c := newTestBigCache(t, WithClock(clk))
+
+	hot := models.NewCacheEntry([]byte("hot"), models.TTL{Fresh: time.Minute}, now.Add(-10*time.Second))
+	expired := models.NewCacheEntry([]byte("old"), models.TTL{Fresh: time.Second}, now.Add(-time.Minute))
+
+	source := mock.NewMockCache(ctrl)
+	source.EXPECT().Get("hot").Return(hot, true)
+	source.EXPECT().Get("expired").Return(expired, true)
+	source.EXPECT().Get("missing").Return(nil, false)
+
+	warmed, err := c.WarmUp(context.Background(), source, []string{"hot", "expired", "missing"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, warmed)
+
+	entry, found := c.Get("hot")
+	require.True(t, found)
+	assert.Equal(t, now.UnixMilli(), entry.CreatedTime().UnixMilli())
+	assert.Equal(t, hot.StaleTime(), entry.StaleTime())
+
+	_, found = c.Get("expired")
+	assert.False(t, found)
+}
+
+func TestBigCache_WarmUp_TTLScale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	cfg := createTestBigCacheConfig()
+	cfg.TTLScale = 0.5
+	c, err := NewBigCache(cfg, WithClock(clock.NewMock(now)))
+	require.NoError(t, err)
+
+	// 50s of the minute remain in L2, halved by the L1 scale
+	hot := models.NewCacheEntry([]byte("hot"), models.TTL{Fresh: time.Minute}, now.Add(-10*time.Second))
+	source := mock.NewMockCache(ctrl)
+	source.EXPECT().Get("hot").Return(hot, true)
+
+	warmed, err := c.(*BigCache).WarmUp(context.Background(), source, []string{"hot"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, warmed)
+
+	entry, found := c.Get("hot")
+	require.True(t, found)
+	assert.Equal(t, 25*time.Second, entry.StaleTime().Sub(entry.CreatedTime()))
+	assert.True(t, entry.StaleTime().Before(hot.StaleTime()))
+}
+
+func TestBigCache_WarmUp_BatchSource(t *testing.T) {
+	source := newTestBigCache(t)
+	var items []cache.BatchItem
+	var keys []string
+	for i := 0; i < 250; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		items = append(items, cache.BatchItem{Key: key, Val: []byte("v"), TTL: models.TTL{Fresh: time.Minute}})
+		keys = append(keys, key)
+	}
+	source.SetMany(items)
+
+	c := newTestBigCache(t)
+	warmed, err := c.WarmUp(context.Background(), source, keys)
+	require.NoError(t, err)
+	assert.Equal(t, 250, warmed)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = c.WarmUp(ctx, source, keys)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,124 @@
+package l1
+
+import (
+	"bytes"
+	"sync"
+)
+
+// tagIndex tracks which keys were stored with which tags. BigCache has no secondary
+// indexes, so the index lives beside it and is pruned from BigCache's removal callback.
+type tagIndex struct {
+	mu      sync.Mutex
+	tagKeys map[string]map[string]struct{}
+	keyTags map[string][]string
+	pending map[string]*pendingTags
+}
+
+// pendingTags is a tagged write whose entry is being stored. BigCache runs its removal
+// callback under a shard lock, so the index cannot be locked across the store; instead
+// a removal of the entry before its tags are committed is recorded here.
+type pendingTags struct {
+	data    []byte
+	removed bool
+}
+
+// newTagIndex creates an empty tag index
+func newTagIndex() *tagIndex {
+	return &tagIndex{
+		tagKeys: make(map[string]map[string]struct{}),
+		keyTags: make(map[string][]string),
+		pending: make(map[string]*pendingTags),
+	}
+}
+
+// begin registers a tagged write of data to key, before the entry is stored
+func (ti *tagIndex) begin(key string, data []byte) *pendingTags {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	p := &pendingTags{data: data}
+	ti.pending[key] = p
+	return p
+}
+
+// commit replaces the tags of key once the write registered as p was stored. The tags
+// are dropped if the entry was not stored, was removed in the meantime or a later
+// tagged write of key has begun, which commits its own tags.
+func (ti *tagIndex) commit(key string, p *pendingTags, tags []string, stored bool) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	if ti.pending[key] != p {
+		return
+	}
+	delete(ti.pending, key)
+
+	if stored && !p.removed {
+		ti.setLocked(key, tags)
+	}
+}
+
+// setLocked replaces the tags of key; callers hold mu
+func (ti *tagIndex) setLocked(key string, tags []string) {
+	ti.removeLocked(key)
+	if len(tags) == 0 {
+		return
+	}
+
+	ti.keyTags[key] = tags
+	for _, tag := range tags {
+		keys, ok := ti.tagKeys[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			ti.tagKeys[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+// remove drops key from every tag it was stored with
+func (ti *tagIndex) remove(key string) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	ti.removeLocked(key)
+}
+
+// removeEntry drops key from every tag it was stored with when BigCache removes the
+// entry holding data, including an entry whose tags are not committed yet
+func (ti *tagIndex) removeEntry(key string, data []byte) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	if p, ok := ti.pending[key]; ok && bytes.Equal(p.data, data) {
+		p.removed = true
+	}
+	ti.removeLocked(key)
+}
+
+// take removes tag from the index and returns its keys
+func (ti *tagIndex) take(tag string) []string {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	keys := make([]string, 0, len(ti.tagKeys[tag]))
+	for key := range ti.tagKeys[tag] {
+		keys = append(keys, key)
+	}
+	for _, key := range keys {
+		ti.removeLocked(key)
+	}
+
+	return keys
+}
+
+func (ti *tagIndex) removeLocked(key string) {
+	for _, tag := range ti.keyTags[key] {
+		keys := ti.tagKeys[tag]
+		delete(keys, key)
+		if len(keys) == 0 {
+			delete(ti.tagKeys, tag)
+		}
+	}
+	delete(ti.keyTags, key)
+}
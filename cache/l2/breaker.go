@@ -0,0 +1,224 @@
+package l2
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/clock"
+)
+
+// ErrCircuitOpen is returned by L2 operations rejected because the circuit breaker is open
+var ErrCircuitOpen = errors.New("L2 circuit breaker is open")
+
+// circuitBreaker tracks the error rate and latency of KeyDB requests over a fixed
+// window and rejects requests while KeyDB looks unhealthy. A nil breaker allows
+// every request.
+type circuitBreaker struct {
+	cfg          cache.CircuitBreakerConfig
+	probe        func(ctx context.Context) error
+	probeTimeout time.Duration
+	recovery     func(ctx context.Context) error
+	clock        clock.Clock
+	logger       cache.Logger
+	metrics      cache.CircuitBreakerMetricsRecorder
+
+	mu          sync.Mutex
+	state       cache.CircuitState
+	windowStart time.Time
+	requests    int
+	failures    int
+	slow        int
+	openedAt    time.Time
+	probes      sync.WaitGroup
+}
+
+// newCircuitBreaker creates a closed breaker that probes with probe, bounded by
+// probeTimeout. After a successful probe it runs recovery, if set, and closes only if
+// recovery succeeds.
+func newCircuitBreaker(cfg cache.CircuitBreakerConfig, probe func(ctx context.Context) error, probeTimeout time.Duration,
+	recovery func(ctx context.Context) error, c clock.Clock, logger cache.Logger, metrics cache.MetricsRecorder) *circuitBreaker {
+	b := &circuitBreaker{
+		cfg:          cfg,
+		probe:        probe,
+		probeTimeout: probeTimeout,
+		recovery:     recovery,
+		clock:        c,
+		logger:       logger,
+		metrics:      cache.OptionalMetrics[cache.CircuitBreakerMetricsRecorder](metrics),
+		windowStart:  c.Now(),
+	}
+	b.metrics.UpdateCircuitBreakerState("l2", cache.CircuitClosed)
+	return b
+}
+
+// State returns the current state
+func (b *circuitBreaker) State() cache.CircuitState {
+	if b == nil {
+		return cache.CircuitClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Allow reports whether a request may be sent. Once an open breaker has waited
+// OpenTimeout, the first call moves it to half-open and starts a probe in the background.
+func (b *circuitBreaker) Allow() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.allow()
+}
+
+// AllowDelete reports whether a delete may be sent. Deletes also pass while half-open,
+// since the recovery hook has taken the ones rejected earlier. An open breaker calls
+// rejected while holding its lock, so every rejected delete is recorded before the
+// breaker turns half-open and the recovery hook can take it.
+func (b *circuitBreaker) AllowDelete(rejected func()) bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.allow() || b.state == cache.CircuitHalfOpen {
+		return true
+	}
+	rejected()
+	return false
+}
+
+// allow implements Allow; callers hold mu
+func (b *circuitBreaker) allow() bool {
+	switch b.state {
+	case cache.CircuitClosed:
+		return true
+	case cache.CircuitOpen:
+		if b.clock.Now().Sub(b.openedAt) >= b.cfg.OpenTimeout {
+			b.setState(cache.CircuitHalfOpen)
+			b.probes.Add(1)
+			go b.runProbe()
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// Record counts the outcome of a request sent while the breaker was closed and opens
+// the breaker once the window's error or slow share crosses its threshold
+func (b *circuitBreaker) Record(failed bool, latency time.Duration) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != cache.CircuitClosed {
+		return
+	}
+
+	now := b.clock.Now()
+	if now.Sub(b.windowStart) >= b.cfg.Window {
+		b.resetWindow(now)
+	}
+
+	b.requests++
+	if failed {
+		b.failures++
+	}
+	if latency >= b.cfg.SlowThreshold {
+		b.slow++
+	}
+
+	if b.requests < b.cfg.MinRequests {
+		return
+	}
+
+	switch {
+	case float64(b.failures)/float64(b.requests) >= b.cfg.ErrorRate:
+		b.open("error_rate")
+	case float64(b.slow)/float64(b.requests) >= b.cfg.SlowRate:
+		b.open("latency")
+	}
+}
+
+// Wait blocks until in-flight probes finish
+func (b *circuitBreaker) Wait() {
+	if b != nil {
+		b.probes.Wait()
+	}
+}
+
+// runProbe pings KeyDB and closes the breaker if it answers within SlowThreshold and
+// the recovery hook succeeds
+func (b *circuitBreaker) runProbe() {
+	defer b.probes.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.probeTimeout)
+	start := b.clock.Now()
+	err := b.probe(ctx)
+	latency := b.clock.Now().Sub(start)
+	cancel()
+
+	if err != nil || latency >= b.cfg.SlowThreshold {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.logger.Warn("L2 circuit breaker probe failed", "latency", latency, "error", err)
+		b.open("probe")
+		return
+	}
+
+	// The recovery hook runs unlocked, since it sends deletes while the breaker is half-open
+	if b.recovery != nil {
+		err = b.recovery(context.Background())
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err != nil {
+		b.open("recovery")
+		return
+	}
+
+	b.resetWindow(b.clock.Now())
+	b.setState(cache.CircuitClosed)
+	b.logger.Info("L2 circuit breaker closed", "latency", latency)
+}
+
+// open moves the breaker to open; callers hold mu
+func (b *circuitBreaker) open(reason string) {
+	b.logger.Warn("L2 circuit breaker opened",
+		"reason", reason,
+		"requests", b.requests,
+		"failures", b.failures,
+		"slow", b.slow,
+		"open_timeout", b.cfg.OpenTimeout)
+
+	b.openedAt = b.clock.Now()
+	b.setState(cache.CircuitOpen)
+}
+
+// setState records a state change; callers hold mu
+func (b *circuitBreaker) setState(state cache.CircuitState) {
+	b.state = state
+	b.metrics.UpdateCircuitBreakerState("l2", state)
+}
+
+// resetWindow starts a new counting window at now; callers hold mu
+func (b *circuitBreaker) resetWindow(now time.Time) {
+	b.windowStart = now
+	b.requests = 0
+	b.failures = 0
+	b.slow = 0
+}
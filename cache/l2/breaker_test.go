@@ -0,0 +1,285 @@
+package l2
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/cache/mock"
+	"github.com/status-im/proxy-common/clock"
+	"github.com/status-im/proxy-common/models"
+)
+
+// stateRecorder records circuit breaker state changes
+type stateRecorder struct {
+	cache.NoopMetrics
+	mu     sync.Mutex
+	states []cache.CircuitState
+}
+
+func (r *stateRecorder) UpdateCircuitBreakerState(level string, state cache.CircuitState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states = append(r.states, state)
+}
+
+func (r *stateRecorder) recorded() []cache.CircuitState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]cache.CircuitState(nil), r.states...)
+}
+
+func newBreakerConfig() *cache.KeyDBConfig {
+	return &cache.KeyDBConfig{
+		CircuitBreaker: cache.CircuitBreakerConfig{
+			Enabled:       true,
+			Window:        10 * time.Second,
+			MinRequests:   4,
+			ErrorRate:     0.5,
+			SlowThreshold: 100 * time.Millisecond,
+			SlowRate:      0.5,
+			OpenTimeout:   5 * time.Second,
+		},
+	}
+}
+
+func TestKeyDBCache_CircuitBreaker_OpensOnErrorRate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockKeyDbClient(ctrl)
+	recorder := &stateRecorder{}
+	c := NewKeyDBCache(newBreakerConfig(), mockClient,
+		WithClock(clock.NewMock(time.Now())), WithMetrics(recorder)).(*KeyDBCache)
+
+	// Two misses and two errors reach MinRequests at a 50% error rate
+	mockClient.EXPECT().Get(gomock.Any(), "key").Return(redis.NewStringResult("", redis.Nil)).Times(2)
+	mockClient.EXPECT().Get(gomock.Any(), "key").Return(redis.NewStringResult("", errors.New("connection refused"))).Times(2)
+	for i := 0; i < 4; i++ {
+		_, found := c.Get("key")
+		assert.False(t, found)
+	}
+
+	assert.Equal(t, cache.CircuitOpen, c.CircuitState())
+	assert.False(t, c.Available())
+	assert.ErrorIs(t, c.HealthCheck(context.Background()), ErrCircuitOpen)
+	assert.Equal(t, []cache.CircuitState{cache.CircuitClosed, cache.CircuitOpen}, recorder.recorded())
+
+	// Requests fail fast without reaching KeyDB
+	_, found := c.Get("key")
+	assert.False(t, found)
+	_, err := c.V2().Get(context.Background(), "key")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+}
+
+func TestKeyDBCache_CircuitBreaker_OpensOnLatency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockKeyDbClient(ctrl)
+	clk := clock.NewMock(time.Now())
+	c := NewKeyDBCache(newBreakerConfig(), mockClient, WithClock(clk)).(*KeyDBCache)
+
+	mockClient.EXPECT().Set(gomock.Any(), "key", gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
+			clk.Advance(200 * time.Millisecond)
+			return redis.NewStatusResult("OK", nil)
+		}).Times(4)
+	for i := 0; i < 4; i++ {
+		c.Set("key", []byte("value"), models.TTL{Fresh: time.Minute})
+	}
+
+	assert.Equal(t, cache.CircuitOpen, c.CircuitState())
+}
+
+func TestKeyDBCache_CircuitBreaker_WindowResets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockKeyDbClient(ctrl)
+	clk := clock.NewMock(time.Now())
+	c := NewKeyDBCache(newBreakerConfig(), mockClient, WithClock(clk)).(*KeyDBCache)
+
+	mockClient.EXPECT().Del(gomock.Any(), "key").Return(redis.NewIntResult(0, errors.New("timeout"))).Times(6)
+	for i := 0; i < 3; i++ {
+		c.Delete("key")
+	}
+	clk.Advance(10 * time.Second)
+	for i := 0; i < 3; i++ {
+		c.Delete("key")
+	}
+
+	assert.Equal(t, cache.CircuitClosed, c.CircuitState())
+}
+
+func TestKeyDBCache_CircuitBreaker_HalfOpenProbe(t *testing.T) {
+	tests := []struct {
+		name      string
+		pingErr   error
+		wantState cache.CircuitState
+	}{
+		{name: "probe succeeds", wantState: cache.CircuitClosed},
+		{name: "probe fails", pingErr: errors.New("connection refused"), wantState: cache.CircuitOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mock.NewMockKeyDbClient(ctrl)
+			clk := clock.NewMock(time.Now())
+			recorder := &stateRecorder{}
+			c := NewKeyDBCache(newBreakerConfig(), mockClient, WithClock(clk), WithMetrics(recorder)).(*KeyDBCache)
+
+			mockClient.EXPECT().Get(gomock.Any(), "key").Return(redis.NewStringResult("", errors.New("timeout"))).Times(4)
+			for i := 0; i < 4; i++ {
+				c.Get("key")
+			}
+			require.Equal(t, cache.CircuitOpen, c.CircuitState())
+
+			// Before OpenTimeout no probe is sent
+			clk.Advance(4 * time.Second)
+			assert.False(t, c.Available())
+			c.breaker.Wait()
+			assert.Equal(t, cache.CircuitOpen, c.CircuitState())
+
+			mockClient.EXPECT().Ping(gomock.Any()).Return(redis.NewStatusResult("PONG", tt.pingErr))
+			clk.Advance(time.Second)
+			assert.False(t, c.Available())
+			c.breaker.Wait()
+
+			assert.Equal(t, tt.wantState, c.CircuitState())
+			assert.Equal(t, []cache.CircuitState{
+				cache.CircuitClosed, cache.CircuitOpen, cache.CircuitHalfOpen, tt.wantState,
+			}, recorder.recorded())
+		})
+	}
+}
+
+func TestKeyDBCache_CircuitBreaker_Disabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockKeyDbClient(ctrl)
+	c := NewKeyDBCache(&cache.KeyDBConfig{}, mockClient).(*KeyDBCache)
+
+	mockClient.EXPECT().Get(gomock.Any(), "key").Return(redis.NewStringResult("", errors.New("timeout"))).Times(10)
+	for i := 0; i < 10; i++ {
+		c.Get("key")
+	}
+
+	assert.True(t, c.Available())
+	assert.Equal(t, cache.CircuitClosed, c.CircuitState())
+
+	mockClient.EXPECT().Ping(gomock.Any()).Return(redis.NewStatusResult("PONG", nil))
+	assert.NoError(t, c.HealthCheck(context.Background()))
+}
+
+func TestKeyDBCache_CircuitBreaker_ReplaysDeletes(t *testing.T) {
+	clk := clock.NewMock(time.Now())
+	c, server := newMiniredisCache(t, WithClock(clk))
+	probe := func(ctx context.Context) error {
+		return c.client.Ping(ctx).Err()
+	}
+	c.missed = newMissedDeletes()
+	c.breaker = newCircuitBreaker(newBreakerConfig().CircuitBreaker, probe, time.Second, c.replayDeletes,
+		clk, c.logger, c.metrics)
+
+	ttl := models.TTL{Fresh: time.Minute}
+	for _, key := range []string{"a", "b", "c", "user:1", "kept"} {
+		c.Set(key, []byte("v"), ttl)
+	}
+	c.SetWithTags("tagged", []byte("v"), ttl, "block:100")
+
+	c.breaker.mu.Lock()
+	c.breaker.open("test")
+	c.breaker.mu.Unlock()
+
+	// Rejected deletes and negative writes are recorded, not sent
+	c.Delete("a")
+	c.SetNegative("b", []byte("error"), ttl)
+	assert.ErrorIs(t, c.V2().Delete(context.Background(), "c"), ErrCircuitOpen)
+	assert.Empty(t, c.InvalidateTag("block:100"))
+	assert.Empty(t, c.InvalidatePrefix("user:"))
+	for _, key := range []string{"a", "b", "c", "user:1", "tagged"} {
+		assert.True(t, server.Exists(key), key)
+	}
+
+	// The probe succeeds and the deletes land before the breaker closes
+	clk.Advance(5 * time.Second)
+	assert.False(t, c.Available())
+	c.breaker.Wait()
+
+	assert.Equal(t, cache.CircuitClosed, c.CircuitState())
+	for _, key := range []string{"a", "b", "c", "user:1", "tagged", tagKeyPrefix + "block:100"} {
+		assert.False(t, server.Exists(key), key)
+	}
+	assert.True(t, server.Exists("kept"))
+
+	missed, dropped := c.missed.take()
+	assert.Zero(t, missed.len())
+	assert.Zero(t, dropped)
+}
+
+func TestKeyDBCache_CircuitBreaker_ReplayFailureReopens(t *testing.T) {
+	clk := clock.NewMock(time.Now())
+	c, server := newMiniredisCache(t, WithClock(clk))
+	probe := func(ctx context.Context) error {
+		return nil
+	}
+	c.missed = newMissedDeletes()
+	c.breaker = newCircuitBreaker(newBreakerConfig().CircuitBreaker, probe, time.Second, c.replayDeletes,
+		clk, c.logger, c.metrics)
+
+	c.Set("a", []byte("v"), models.TTL{Fresh: time.Minute})
+	c.breaker.mu.Lock()
+	c.breaker.open("test")
+	c.breaker.mu.Unlock()
+	c.Delete("a")
+
+	server.SetError("LOADING")
+	clk.Advance(5 * time.Second)
+	c.Available()
+	c.breaker.Wait()
+	assert.Equal(t, cache.CircuitOpen, c.CircuitState())
+
+	// The failed replay is kept for the next recovery
+	server.SetError("")
+	clk.Advance(5 * time.Second)
+	c.Available()
+	c.breaker.Wait()
+	assert.Equal(t, cache.CircuitClosed, c.CircuitState())
+	assert.False(t, server.Exists("a"))
+}
+
+func TestCircuitBreaker_AllowDelete(t *testing.T) {
+	clk := clock.NewMock(time.Now())
+	b := newCircuitBreaker(newBreakerConfig().CircuitBreaker, func(ctx context.Context) error { return nil }, time.Second,
+		nil, clk, cache.NoopLogger{}, cache.NoopMetrics{})
+
+	rejected := 0
+	reject := func() { rejected++ }
+	assert.True(t, b.AllowDelete(reject))
+
+	b.mu.Lock()
+	b.open("test")
+	b.mu.Unlock()
+	assert.False(t, b.AllowDelete(reject))
+	assert.Equal(t, 1, rejected)
+
+	// Once OpenTimeout passed the delete passes and the breaker probes
+	clk.Advance(5 * time.Second)
+	assert.True(t, b.AllowDelete(reject))
+	assert.Equal(t, 1, rejected)
+	b.Wait()
+	assert.Equal(t, cache.CircuitClosed, b.State())
+}
@@ -2,25 +2,75 @@ package l2
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 
 	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/cache/codec"
+	"github.com/status-im/proxy-common/clock"
 	"github.com/status-im/proxy-common/models"
 )
 
-// Ensure KeyDBCache implements cache.Cache
+// Ensure KeyDBCache implements cache.Cache, cache.TaggedCache and cache.BatchCache
 var _ cache.Cache = (*KeyDBCache)(nil)
+var _ cache.TaggedCache = (*KeyDBCache)(nil)
+var _ cache.BatchCache = (*KeyDBCache)(nil)
+var _ cache.NegativeCache = (*KeyDBCache)(nil)
+var _ cache.NegativeBatchCache = (*KeyDBCache)(nil)
+var _ cache.HealthChecker = (*KeyDBCache)(nil)
+var _ cache.StatsReporter = (*KeyDBCache)(nil)
+var _ cache.SizeLimitedCache = (*KeyDBCache)(nil)
+
+const (
+	// tagKeyPrefix namespaces the Redis sets holding the keys of each tag
+	tagKeyPrefix = "__tag:"
+	// tagScript adds ARGV[1] to the tag set KEYS[1] and extends the set's expiry to
+	// ARGV[2] milliseconds, or removes it for 0, so the set outlives its longest-lived
+	// member. Running it as one script makes the read-modify-write of the expiry atomic.
+	tagScript = `local existed = redis.call('EXISTS', KEYS[1])
+redis.call('SADD', KEYS[1], ARGV[1])
+local ttl = tonumber(ARGV[2])
+if ttl <= 0 then
+	redis.call('PERSIST', KEYS[1])
+elseif existed == 0 then
+	redis.call('PEXPIRE', KEYS[1], ttl)
+else
+	local current = redis.call('PTTL', KEYS[1])
+	if current >= 0 and current < ttl then
+		redis.call('PEXPIRE', KEYS[1], ttl)
+	end
+end
+return 1`
+	// scanCount is the SCAN batch size hint used for prefix invalidation
+	scanCount = 1000
+)
+
+// errNotExtended is returned by operations that need a cache.ExtendedKeyDbClient
+var errNotExtended = fmt.Errorf("L2 client does not implement cache.ExtendedKeyDbClient: %w", errors.ErrUnsupported)
+
+// ErrEntryTooLarge is returned when an encoded entry exceeds the limit of a
+// WithMaxEntrySize view
+var ErrEntryTooLarge = errors.New("cache entry too large")
 
 // KeyDBCache implements L2 cache using Redis/KeyDB
 type KeyDBCache struct {
 	client  cache.KeyDbClient
+	ext     cache.ExtendedKeyDbClient // client, if it implements the extended interface
 	cfg     *cache.KeyDBConfig
 	logger  cache.Logger
 	metrics cache.MetricsRecorder
+	codec   cache.Codec
+	clock   clock.Clock
+	breaker *circuitBreaker // nil unless cfg.CircuitBreaker.Enabled
+	missed  *missedDeletes  // nil unless cfg.CircuitBreaker.Enabled
+	writer  *writeBehind    // nil unless cfg.WriteBehind.Enabled
+
+	maxEntrySize int // encoded bytes, 0 for no limit; set by WithMaxEntrySize
 }
 
 // Option is a functional option for configuring KeyDBCache
@@ -40,126 +90,836 @@ func WithMetrics(metrics cache.MetricsRecorder) Option {
 	}
 }
 
+// WithCodec sets the codec used to serialize entries for KeyDBCache. It replaces the
+// codec built from the config, so Format and Compression are ignored and compression
+// is only reported through RecordCacheCompression if c does so itself.
+func WithCodec(c cache.Codec) Option {
+	return func(kc *KeyDBCache) {
+		kc.codec = c
+	}
+}
+
+// WithClock sets the clock used to timestamp entries and check their expiry
+func WithClock(c clock.Clock) Option {
+	return func(kc *KeyDBCache) {
+		kc.clock = c
+	}
+}
+
 // NewKeyDBCache creates a new KeyDBCache instance with provided client
 func NewKeyDBCache(cfg *cache.KeyDBConfig, client cache.KeyDbClient, opts ...Option) cache.Cache {
 	cfg.ApplyDefaults()
 
+	ext, _ := client.(cache.ExtendedKeyDbClient)
 	kc := &KeyDBCache{
 		client:  client,
+		ext:     ext,
 		cfg:     cfg,
 		logger:  cache.NoopLogger{},
 		metrics: cache.NoopMetrics{},
+		clock:   clock.Real{},
 	}
 
 	for _, opt := range opts {
 		opt(kc)
 	}
 
+	if kc.codec == nil {
+		c, err := codec.New(cfg.Format, cfg.Compression, func(algorithm cache.CompressionAlgorithm, rawBytes, compressedBytes int) {
+			cache.OptionalMetrics[cache.CompressionMetricsRecorder](kc.metrics).
+				RecordCacheCompression("l2", string(algorithm), rawBytes, compressedBytes)
+		})
+		if err != nil {
+			kc.logger.Error("Invalid L2 entry encoding config, writing uncompressed JSON entries", "error", err)
+			c = codec.JSON{}
+		}
+		kc.codec = c
+	}
+	if ext == nil {
+		kc.logger.Info("L2 client does not implement cache.ExtendedKeyDbClient, " +
+			"sending batches one key at a time without tags or prefix invalidation")
+	}
+
+	if cfg.CircuitBreaker.Enabled {
+		probe := func(ctx context.Context) error {
+			return client.Ping(ctx).Err()
+		}
+		kc.missed = newMissedDeletes()
+		kc.breaker = newCircuitBreaker(cfg.CircuitBreaker, probe, cfg.Connection.ReadTimeout, kc.replayDeletes,
+			kc.clock, kc.logger, kc.metrics)
+	}
+	if cfg.WriteBehind.Enabled {
+		if err := cfg.WriteBehind.Validate(); err != nil {
+			kc.logger.Error("Invalid L2 write-behind config, writing synchronously", "error", err)
+		} else {
+			kc.writer = newWriteBehind(cfg.WriteBehind, kc.writeBatch, cfg.Connection.SendTimeout, kc.clock, kc.logger, kc.metrics)
+		}
+	}
+
 	return kc
 }
 
 // Get retrieves value from KeyDB cache with freshness information
 func (kc *KeyDBCache) Get(key string) (*models.CacheEntry, bool) {
-	ctx, cancel := context.WithTimeout(context.Background(), kc.cfg.Connection.ReadTimeout)
-	defer cancel()
+	entry, err := kc.get(context.Background(), key)
+	if err != nil {
+		if !errors.Is(err, cache.ErrCacheMiss) {
+			kc.warn("L2 cache get failed", err, "key", key)
+		}
+		return nil, false
+	}
 
-	data, err := kc.client.Get(ctx, key).Result()
+	return entry, true
+}
+
+// GetStale retrieves value from KeyDB cache regardless of freshness
+func (kc *KeyDBCache) GetStale(key string) (*models.CacheEntry, bool) {
+	entry, err := kc.get(context.Background(), key)
 	if err != nil {
-		if errors.Is(err, redis.Nil) {
-			return nil, false
+		if !errors.Is(err, cache.ErrCacheMiss) {
+			kc.warn("L2 cache stale get failed", err, "key", key)
 		}
-		kc.logger.Warn("L2 cache get failed", "key", key, "error", err)
 		return nil, false
 	}
 
-	var entry models.CacheEntry
-	if err := json.Unmarshal([]byte(data), &entry); err != nil {
-		kc.logger.Error("Failed to unmarshal L2 cache entry", "key", key, "error", err)
-		kc.metrics.RecordCacheError("l2", "decode")
-		kc.client.Del(context.Background(), key)
+	return entry, true
+}
+
+// GetEntry retrieves a positive or negative entry from KeyDB cache
+func (kc *KeyDBCache) GetEntry(key string) (*models.CacheEntry, bool) {
+	entry, err := kc.getEntry(context.Background(), key)
+	if err != nil {
+		if !errors.Is(err, cache.ErrCacheMiss) {
+			kc.warn("L2 cache get failed", err, "key", key)
+		}
 		return nil, false
 	}
 
-	if entry.IsExpired() {
-		kc.client.Del(context.Background(), key)
+	return entry, true
+}
+
+// GetStaleEntry retrieves a positive or negative entry from KeyDB cache regardless of freshness
+func (kc *KeyDBCache) GetStaleEntry(key string) (*models.CacheEntry, bool) {
+	entry, err := kc.getEntry(context.Background(), key)
+	if err != nil {
+		if !errors.Is(err, cache.ErrCacheMiss) {
+			kc.warn("L2 cache stale get failed", err, "key", key)
+		}
 		return nil, false
 	}
 
-	return &entry, true
+	return entry, true
 }
 
-// GetStale retrieves value from KeyDB cache regardless of freshness
-func (kc *KeyDBCache) GetStale(key string) (*models.CacheEntry, bool) {
-	ctx, cancel := context.WithTimeout(context.Background(), kc.cfg.Connection.ReadTimeout)
+// Set stores value in KeyDB cache with TTL
+func (kc *KeyDBCache) Set(key string, val []byte, ttl models.TTL) {
+	if err := kc.set(context.Background(), key, val, ttl); err != nil {
+		kc.warn("Failed to set L2 cache entry", err, "key", key)
+	}
+}
+
+// SetNegative stores a negative entry in KeyDB cache with TTL
+func (kc *KeyDBCache) SetNegative(key string, errPayload []byte, ttl models.TTL) {
+	if err := kc.setNegative(context.Background(), key, errPayload, ttl); err != nil {
+		kc.warn("Failed to set negative L2 cache entry", err, "key", key)
+	}
+}
+
+// Delete removes entry from KeyDB cache. A delete rejected by the circuit breaker is
+// replayed before it closes.
+func (kc *KeyDBCache) Delete(key string) {
+	if err := kc.delete(context.Background(), key); err != nil {
+		kc.warn("Failed to delete L2 cache entry", err, "key", key)
+	}
+}
+
+// GetMany retrieves several values with a single MGET, returning only the keys found
+func (kc *KeyDBCache) GetMany(keys []string) map[string]*models.CacheEntry {
+	entries := kc.GetManyEntries(keys)
+	for key, entry := range entries {
+		if entry.IsNegative() {
+			delete(entries, key)
+		}
+	}
+	return entries
+}
+
+// GetManyEntries retrieves several positive or negative entries with a single MGET,
+// returning only the keys found
+func (kc *KeyDBCache) GetManyEntries(keys []string) map[string]*models.CacheEntry {
+	entries, err := kc.getMany(context.Background(), keys)
+	if err != nil {
+		kc.warn("L2 cache multi-get failed", err, "keys", len(keys))
+	}
+	return entries
+}
+
+// SetMany stores several values in a single pipeline
+func (kc *KeyDBCache) SetMany(items []cache.BatchItem) {
+	if err := kc.setMany(context.Background(), items); err != nil {
+		kc.warn("Failed to set L2 cache entries", err, "keys", len(items))
+	}
+}
+
+// SetWithTags stores value in KeyDB cache and adds the key to a Redis set per tag
+func (kc *KeyDBCache) SetWithTags(key string, val []byte, ttl models.TTL, tags ...string) {
+	if err := kc.setWithTags(context.Background(), key, val, ttl, tags); err != nil {
+		kc.warn("Failed to set tagged L2 cache entry", err, "key", key, "tags", tags)
+	}
+}
+
+// InvalidateTag removes every entry stored with tag, returning the removed keys
+func (kc *KeyDBCache) InvalidateTag(tag string) []string {
+	keys, err := kc.invalidateTag(context.Background(), tag)
+	if err != nil {
+		kc.warn("Failed to invalidate L2 cache tag", err, "tag", tag)
+	}
+	return keys
+}
+
+// InvalidatePrefix removes every entry whose key starts with prefix, returning the removed keys
+func (kc *KeyDBCache) InvalidatePrefix(prefix string) []string {
+	keys, err := kc.invalidatePrefix(context.Background(), prefix)
+	if err != nil {
+		kc.warn("Failed to invalidate L2 cache prefix", err, "prefix", prefix)
+	}
+	return keys
+}
+
+// V2 returns a view of this cache implementing the context-aware cache.CacheV2 interface
+func (kc *KeyDBCache) V2() *KeyDBCacheV2 {
+	return &KeyDBCacheV2{kc: kc}
+}
+
+// WithMaxEntrySize returns a view of the cache, sharing its client, circuit breaker and
+// write-behind queue, that skips entries whose encoded size exceeds size bytes
+func (kc *KeyDBCache) WithMaxEntrySize(size int) cache.Cache {
+	return kc.withMaxEntrySize(size)
+}
+
+func (kc *KeyDBCache) withMaxEntrySize(size int) *KeyDBCache {
+	limited := *kc
+	limited.maxEntrySize = size
+	return &limited
+}
+
+// Available reports whether L2 accepts requests, i.e. its circuit breaker is closed.
+// Once an open breaker has waited OpenTimeout, calling Available starts a probe.
+func (kc *KeyDBCache) Available() bool {
+	return kc.breaker.Allow()
+}
+
+// CircuitState returns the state of the circuit breaker, closed if it is disabled
+func (kc *KeyDBCache) CircuitState() cache.CircuitState {
+	return kc.breaker.State()
+}
+
+// Stats returns the circuit breaker state and the write-behind queue depth
+func (kc *KeyDBCache) Stats() cache.LevelStats {
+	return cache.LevelStats{
+		CircuitState:    kc.breaker.State().String(),
+		WriteQueueDepth: kc.writer.depth(),
+	}
+}
+
+// HealthCheck returns ErrCircuitOpen while the circuit breaker is not closed and
+// otherwise pings KeyDB, bounding ctx by the configured read timeout
+func (kc *KeyDBCache) HealthCheck(ctx context.Context) error {
+	if kc.breaker.State() != cache.CircuitClosed {
+		return ErrCircuitOpen
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, kc.cfg.Connection.ReadTimeout)
+	defer cancel()
+
+	return kc.client.Ping(ctx).Err()
+}
+
+// observe reports the outcome of a KeyDB request started at start to the circuit breaker.
+// Missing keys and requests cancelled by their caller do not count as failures.
+func (kc *KeyDBCache) observe(start time.Time, err error) {
+	failed := err != nil && !errors.Is(err, redis.Nil) && !errors.Is(err, context.Canceled)
+	kc.breaker.Record(failed, kc.clock.Now().Sub(start))
+}
+
+// warn logs a failed operation unless the circuit breaker rejected it, so an unhealthy
+// KeyDB is reported once when the breaker opens rather than on every request
+func (kc *KeyDBCache) warn(msg string, err error, keysAndValues ...interface{}) {
+	if errors.Is(err, ErrCircuitOpen) {
+		return
+	}
+	kc.logger.Warn(msg, append(keysAndValues, "error", err)...)
+}
+
+// get fetches a positive entry, reporting negative entries as cache.ErrCacheMiss
+func (kc *KeyDBCache) get(ctx context.Context, key string) (*models.CacheEntry, error) {
+	entry, err := kc.getEntry(ctx, key)
+	if err == nil && entry.IsNegative() {
+		return nil, cache.ErrCacheMiss
+	}
+	return entry, err
+}
+
+// getEntry fetches and decodes an entry, bounding ctx by the configured read timeout.
+// Absent, expired and undecodable entries are reported as cache.ErrCacheMiss or a decode error.
+func (kc *KeyDBCache) getEntry(ctx context.Context, key string) (*models.CacheEntry, error) {
+	if !kc.breaker.Allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, kc.cfg.Connection.ReadTimeout)
 	defer cancel()
 
+	start := kc.clock.Now()
 	data, err := kc.client.Get(ctx, key).Result()
+	kc.observe(start, err)
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return nil, false
+			return nil, cache.ErrCacheMiss
 		}
-		kc.logger.Warn("L2 cache stale get failed", "key", key, "error", err)
-		return nil, false
+		return nil, err
 	}
 
-	var entry models.CacheEntry
-	if err := json.Unmarshal([]byte(data), &entry); err != nil {
-		kc.logger.Error("Failed to unmarshal L2 cache entry for stale get", "key", key, "error", err)
-		kc.client.Del(context.Background(), key)
-		return nil, false
+	entry, err := kc.codec.Decode([]byte(data))
+	if err != nil {
+		kc.metrics.RecordCacheError("l2", "decode")
+		kc.client.Del(ctx, key)
+		return nil, fmt.Errorf("failed to decode L2 cache entry: %w", err)
 	}
 
-	if entry.IsExpired() {
-		kc.client.Del(context.Background(), key)
-		return nil, false
+	if entry.IsExpiredAt(kc.clock.Now()) {
+		kc.client.Del(ctx, key)
+		return nil, cache.ErrCacheMiss
 	}
 
-	return &entry, true
+	return entry, nil
 }
 
-// Set stores value in KeyDB cache with TTL
-func (kc *KeyDBCache) Set(key string, val []byte, ttl models.TTL) {
-	ctx, cancel := context.WithTimeout(context.Background(), kc.cfg.Connection.SendTimeout)
-	defer cancel()
+// set enforces the configured TTL limits and writes val as a positive entry
+func (kc *KeyDBCache) set(ctx context.Context, key string, val []byte, ttl models.TTL) error {
+	ttl = kc.adjustTTL(key, ttl)
+	return kc.write(ctx, key, models.NewCacheEntry(val, ttl, kc.clock.Now()), ttl, nil)
+}
 
-	now := time.Now().Unix()
+// setNegative enforces the configured TTL limits and writes a negative entry
+func (kc *KeyDBCache) setNegative(ctx context.Context, key string, errPayload []byte, ttl models.TTL) error {
+	ttl = kc.adjustTTL(key, ttl)
+	return kc.write(ctx, key, models.NewNegativeCacheEntry(errPayload, ttl, kc.clock.Now()), ttl, nil)
+}
 
-	entry := models.CacheEntry{
-		Data:      val,
-		CreatedAt: now,
-		StaleAt:   now + int64(ttl.Fresh.Seconds()),
-		ExpiresAt: now + int64(ttl.Fresh.Seconds()) + int64(ttl.Stale.Seconds()),
+// write queues an entry and its tag updates on the write-behind queue if it is enabled,
+// and stores them otherwise
+func (kc *KeyDBCache) write(ctx context.Context, key string, entry *models.CacheEntry, ttl models.TTL, tags []string) error {
+	if kc.writer == nil && len(tags) == 0 {
+		return kc.store(ctx, key, entry, ttl)
 	}
 
-	data, err := json.Marshal(entry)
+	data, err := kc.encode(entry)
 	if err != nil {
-		kc.logger.Error("Failed to marshal L2 cache entry", "key", key, "error", err)
-		kc.metrics.RecordCacheError("l2", "encode")
-		return
+		return err
+	}
+	pw := pendingWrite{key: key, data: data, ttl: ttl.Fresh + ttl.Stale, tags: tags, negative: entry.IsNegative()}
+	if kc.writer == nil {
+		return kc.writeBatch(ctx, []pendingWrite{pw})
+	}
+	kc.writer.enqueue(pw)
+	return nil
+}
+
+// adjustTTL applies the configured TTL scale, default and maximum, recording any
+// adjustment, and then jitters the result for key
+func (kc *KeyDBCache) adjustTTL(key string, ttl models.TTL) models.TTL {
+	adjusted, reason := kc.cfg.Cache.ApplyTTL(ttl)
+	if reason != "" {
+		cache.OptionalMetrics[cache.TTLMetricsRecorder](kc.metrics).RecordCacheTTLAdjusted("l2", reason)
+	}
+	return kc.cfg.Cache.TTLJitter.Apply(key, adjusted)
+}
+
+// store encodes and stores an entry expiring after ttl, bounding ctx by the configured
+// send timeout
+func (kc *KeyDBCache) store(ctx context.Context, key string, entry *models.CacheEntry, ttl models.TTL) error {
+	if !kc.breaker.Allow() {
+		if entry.IsNegative() {
+			kc.dropRejected(ctx, []string{key})
+		}
+		return ErrCircuitOpen
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, kc.cfg.Connection.SendTimeout)
+	defer cancel()
+
+	data, err := kc.encode(entry)
+	if err != nil {
+		return err
 	}
 
 	totalTTL := ttl.Fresh + ttl.Stale
+	start := kc.clock.Now()
 	err = kc.client.Set(ctx, key, data, totalTTL).Err()
+	kc.observe(start, err)
 	if err != nil {
-		kc.logger.Warn("Failed to set L2 cache entry", "key", key, "error", err)
 		kc.metrics.RecordCacheError("l2", "redis")
+		return err
+	}
+
+	return nil
+}
+
+// dropRejected deletes the keys of negative writes rejected by the circuit breaker, so
+// the positive entries they replace cannot be served once it closes
+func (kc *KeyDBCache) dropRejected(ctx context.Context, keys []string) {
+	if len(keys) == 0 {
 		return
 	}
+	if _, err := kc.sendDeletes(ctx, keys); err != nil {
+		kc.warn("Failed to delete L2 entries replaced by rejected negative writes", err, "keys", len(keys))
+	}
 }
 
-// Delete removes entry from KeyDB cache
-func (kc *KeyDBCache) Delete(key string) {
-	ctx, cancel := context.WithTimeout(context.Background(), kc.cfg.Connection.SendTimeout)
+// encode serializes an entry, rejecting it if it exceeds the entry size limit
+func (kc *KeyDBCache) encode(entry *models.CacheEntry) ([]byte, error) {
+	data, err := kc.codec.Encode(entry)
+	if err != nil {
+		kc.metrics.RecordCacheError("l2", "encode")
+		return nil, fmt.Errorf("failed to encode L2 cache entry: %w", err)
+	}
+	if kc.maxEntrySize > 0 && len(data) > kc.maxEntrySize {
+		kc.metrics.RecordCacheError("l2", "entry_too_large")
+		return nil, fmt.Errorf("%w: %d bytes, limit %d", ErrEntryTooLarge, len(data), kc.maxEntrySize)
+	}
+
+	return data, nil
+}
+
+// getMany fetches and decodes several entries with one MGET, bounding ctx by the
+// configured read timeout. Expired and undecodable entries are deleted and left out.
+func (kc *KeyDBCache) getMany(ctx context.Context, keys []string) (map[string]*models.CacheEntry, error) {
+	entries := make(map[string]*models.CacheEntry, len(keys))
+	if len(keys) == 0 {
+		return entries, nil
+	}
+	if kc.ext == nil {
+		return kc.getEach(ctx, keys)
+	}
+	if !kc.breaker.Allow() {
+		return entries, ErrCircuitOpen
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, kc.cfg.Connection.ReadTimeout)
 	defer cancel()
 
+	start := kc.clock.Now()
+	vals, err := kc.ext.MGet(ctx, keys...).Result()
+	kc.observe(start, err)
+	if err != nil {
+		kc.metrics.RecordCacheError("l2", "redis")
+		return entries, err
+	}
+
+	var invalid []string
+	for i, val := range vals {
+		data, ok := val.(string)
+		if !ok {
+			continue
+		}
+
+		entry, err := kc.codec.Decode([]byte(data))
+		if err != nil {
+			kc.metrics.RecordCacheError("l2", "decode")
+			invalid = append(invalid, keys[i])
+			continue
+		}
+		if entry.IsExpiredAt(kc.clock.Now()) {
+			invalid = append(invalid, keys[i])
+			continue
+		}
+
+		entries[keys[i]] = entry
+	}
+
+	if len(invalid) > 0 {
+		pipe := kc.ext.Pipeline()
+		for _, key := range invalid {
+			pipe.Del(ctx, key)
+		}
+		_, _ = pipe.Exec(ctx)
+	}
+
+	return entries, nil
+}
+
+// getEach fetches entries one GET at a time, for clients without MGET
+func (kc *KeyDBCache) getEach(ctx context.Context, keys []string) (map[string]*models.CacheEntry, error) {
+	entries := make(map[string]*models.CacheEntry, len(keys))
+	var errs []error
+	for _, key := range keys {
+		entry, err := kc.getEntry(ctx, key)
+		switch {
+		case err == nil:
+			entries[key] = entry
+		case errors.Is(err, ErrCircuitOpen):
+			return entries, err
+		case !errors.Is(err, cache.ErrCacheMiss):
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	return entries, errors.Join(errs...)
+}
+
+// setMany encodes several entries and stores them in one pipeline, or queues them on
+// the write-behind queue if it is enabled. Entries that fail to encode are skipped.
+func (kc *KeyDBCache) setMany(ctx context.Context, items []cache.BatchItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	if kc.writer == nil && !kc.breaker.Allow() {
+		return ErrCircuitOpen
+	}
+
+	var errs []error
+	writes := make([]pendingWrite, 0, len(items))
+	for _, item := range items {
+		ttl := kc.adjustTTL(item.Key, item.TTL)
+		data, err := kc.encode(models.NewCacheEntry(item.Val, ttl, kc.clock.Now()))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", item.Key, err))
+			continue
+		}
+		writes = append(writes, pendingWrite{key: item.Key, data: data, ttl: ttl.Fresh + ttl.Stale})
+	}
+
+	if kc.writer != nil {
+		for _, pw := range writes {
+			kc.writer.enqueue(pw)
+		}
+	} else if len(writes) > 0 {
+		if err := kc.writeBatch(ctx, writes); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// writeBatch stores encoded entries and adds them to their tag sets in one pipeline,
+// bounding ctx by the configured send timeout. Clients without pipelines store the
+// entries one SET at a time and leave the tags out.
+func (kc *KeyDBCache) writeBatch(ctx context.Context, batch []pendingWrite) error {
+	if !kc.breaker.Allow() {
+		var negative []string
+		for _, pw := range batch {
+			if pw.negative {
+				negative = append(negative, pw.key)
+			}
+		}
+		kc.dropRejected(ctx, negative)
+		return ErrCircuitOpen
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, kc.cfg.Connection.SendTimeout)
+	defer cancel()
+
+	if kc.ext == nil {
+		for _, pw := range batch {
+			start := kc.clock.Now()
+			err := kc.client.Set(ctx, pw.key, pw.data, pw.ttl).Err()
+			kc.observe(start, err)
+			if err != nil {
+				kc.metrics.RecordCacheError("l2", "redis")
+				return err
+			}
+		}
+		return nil
+	}
+
+	pipe := kc.ext.Pipeline()
+	for _, pw := range batch {
+		pipe.Set(ctx, pw.key, pw.data, pw.ttl)
+		for _, tag := range pw.tags {
+			pipe.Eval(ctx, tagScript, []string{tagKeyPrefix + tag}, pw.key, pw.ttl.Milliseconds())
+		}
+	}
+
+	start := kc.clock.Now()
+	_, err := pipe.Exec(ctx)
+	kc.observe(start, err)
+	if err != nil {
+		kc.metrics.RecordCacheError("l2", "redis")
+		return err
+	}
+
+	return nil
+}
+
+// delete removes an entry after dropping its queued writes and waiting for an in-flight
+// one, bounding ctx by the configured send timeout
+func (kc *KeyDBCache) delete(ctx context.Context, key string) error {
+	if err := kc.writer.discard(ctx, func(pw pendingWrite) bool { return pw.key == key }); err != nil {
+		return err
+	}
+	if !kc.breaker.AllowDelete(func() { kc.missed.addKeys(key) }) {
+		return ErrCircuitOpen
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, kc.cfg.Connection.SendTimeout)
+	defer cancel()
+
+	start := kc.clock.Now()
 	err := kc.client.Del(ctx, key).Err()
+	kc.observe(start, err)
+	return err
+}
+
+// setWithTags stores an entry and records its key in each tag set, in the same pipeline
+// and through the write-behind queue like any other write. A tag set's expiry is only
+// ever extended, so it outlives its longest-lived member.
+func (kc *KeyDBCache) setWithTags(ctx context.Context, key string, val []byte, ttl models.TTL, tags []string) error {
+	ttl = kc.adjustTTL(key, ttl)
+	return kc.write(ctx, key, models.NewCacheEntry(val, ttl, kc.clock.Now()), ttl, tags)
+}
+
+// invalidateTag deletes the members of a tag set and then the set itself. Queued and
+// in-flight writes with the tag are dropped or waited for first, so they are either
+// gone or in the set.
+func (kc *KeyDBCache) invalidateTag(ctx context.Context, tag string) ([]string, error) {
+	tagKey := tagKeyPrefix + tag
+	if kc.ext == nil {
+		return nil, errNotExtended
+	}
+	if err := kc.writer.discard(ctx, func(pw pendingWrite) bool { return slices.Contains(pw.tags, tag) }); err != nil {
+		return nil, err
+	}
+	if !kc.breaker.AllowDelete(func() { kc.missed.addTag(tag) }) {
+		return nil, ErrCircuitOpen
+	}
+
+	readCtx, cancel := context.WithTimeout(ctx, kc.cfg.Connection.ReadTimeout)
+	start := kc.clock.Now()
+	keys, err := kc.ext.SMembers(readCtx, tagKey).Result()
+	kc.observe(start, err)
+	cancel()
 	if err != nil {
-		kc.logger.Warn("Failed to delete L2 cache entry", "key", key, "error", err)
-		return
+		kc.metrics.RecordCacheError("l2", "redis")
+		return nil, err
 	}
+
+	// The tag set goes in the same pipeline as its members, last
+	removed, err := kc.deleteKeys(ctx, append(keys, tagKey))
+	if len(removed) > 0 && removed[len(removed)-1] == tagKey {
+		removed = removed[:len(removed)-1]
+	}
+	return removed, err
 }
 
-// Close closes the KeyDB connection
+// invalidatePrefix scans for keys starting with prefix and deletes them, after dropping
+// the queued writes for such keys and waiting for in-flight ones
+func (kc *KeyDBCache) invalidatePrefix(ctx context.Context, prefix string) ([]string, error) {
+	if kc.ext == nil {
+		return nil, errNotExtended
+	}
+	if err := kc.writer.discard(ctx, func(pw pendingWrite) bool { return strings.HasPrefix(pw.key, prefix) }); err != nil {
+		return nil, err
+	}
+
+	match := escapeGlob(prefix) + "*"
+	seen := make(map[string]struct{})
+	var keys []string
+
+	var cursor uint64
+	for {
+		if !kc.breaker.AllowDelete(func() { kc.missed.addPrefix(prefix) }) {
+			return nil, ErrCircuitOpen
+		}
+
+		scanCtx, cancel := context.WithTimeout(ctx, kc.cfg.Connection.ReadTimeout)
+		start := kc.clock.Now()
+		batch, next, err := kc.ext.Scan(scanCtx, cursor, match, scanCount).Result()
+		kc.observe(start, err)
+		cancel()
+		if err != nil {
+			kc.metrics.RecordCacheError("l2", "redis")
+			return nil, err
+		}
+
+		// SCAN may return a key more than once
+		for _, key := range batch {
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	return kc.deleteKeys(ctx, keys)
+}
+
+// deleteKeys deletes keys, after dropping their queued writes and waiting for in-flight
+// ones, with one DEL per key, since a multi-key DEL fails across cluster slots, sent in
+// a single pipeline bounded by the configured send timeout. It returns the keys whose
+// DEL succeeded.
+func (kc *KeyDBCache) deleteKeys(ctx context.Context, keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return keys, nil
+	}
+
+	pending := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		pending[key] = struct{}{}
+	}
+	if err := kc.writer.discard(ctx, func(pw pendingWrite) bool {
+		_, ok := pending[pw.key]
+		return ok
+	}); err != nil {
+		return nil, err
+	}
+	return kc.sendDeletes(ctx, keys)
+}
+
+// sendDeletes sends the per-key DELs of deleteKeys, recording them for replay if the
+// circuit breaker is open
+func (kc *KeyDBCache) sendDeletes(ctx context.Context, keys []string) ([]string, error) {
+	if !kc.breaker.AllowDelete(func() { kc.missed.addKeys(keys...) }) {
+		return nil, ErrCircuitOpen
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, kc.cfg.Connection.SendTimeout)
+	defer cancel()
+
+	if kc.ext == nil {
+		for i, key := range keys {
+			start := kc.clock.Now()
+			err := kc.client.Del(ctx, key).Err()
+			kc.observe(start, err)
+			if err != nil {
+				kc.metrics.RecordCacheError("l2", "redis")
+				return keys[:i], err
+			}
+		}
+		return keys, nil
+	}
+
+	pipe := kc.ext.Pipeline()
+	cmds := make([]*redis.IntCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.Del(ctx, key)
+	}
+
+	start := kc.clock.Now()
+	_, err := pipe.Exec(ctx)
+	kc.observe(start, err)
+
+	removed := make([]string, 0, len(keys))
+	for i, cmd := range cmds {
+		if cmd.Err() == nil {
+			removed = append(removed, keys[i])
+		}
+	}
+	if err != nil {
+		kc.metrics.RecordCacheError("l2", "redis")
+		return removed, err
+	}
+
+	return removed, nil
+}
+
+// escapeGlob escapes the characters SCAN MATCH treats as glob syntax
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Close flushes the write-behind queue, waits for any circuit breaker probe and closes
+// the KeyDB connection
 func (kc *KeyDBCache) Close() error {
+	kc.writer.close()
+	kc.breaker.Wait()
 	return kc.client.Close()
 }
+
+// KeyDBCacheV2 exposes a KeyDBCache through the context-aware cache.CacheV2 interface.
+// Caller deadlines are honoured in addition to the configured connection timeouts.
+type KeyDBCacheV2 struct {
+	kc *KeyDBCache
+}
+
+// Ensure KeyDBCacheV2 implements cache.CacheV2
+var _ cache.CacheV2 = (*KeyDBCacheV2)(nil)
+var _ cache.NegativeCacheV2 = (*KeyDBCacheV2)(nil)
+var _ cache.SizeLimitedCacheV2 = (*KeyDBCacheV2)(nil)
+var _ cache.HealthChecker = (*KeyDBCacheV2)(nil)
+
+// NewKeyDBCacheV2 creates a new context-aware KeyDB cache with provided client
+func NewKeyDBCacheV2(cfg *cache.KeyDBConfig, client cache.KeyDbClient, opts ...Option) cache.CacheV2 {
+	return NewKeyDBCache(cfg, client, opts...).(*KeyDBCache).V2()
+}
+
+// Get retrieves value from KeyDB cache, returning cache.ErrCacheMiss if absent or expired
+func (c *KeyDBCacheV2) Get(ctx context.Context, key string) (*models.CacheEntry, error) {
+	return c.kc.get(ctx, key)
+}
+
+// GetStale retrieves value from KeyDB cache regardless of freshness
+func (c *KeyDBCacheV2) GetStale(ctx context.Context, key string) (*models.CacheEntry, error) {
+	return c.kc.get(ctx, key)
+}
+
+// GetEntry retrieves a positive or negative entry, returning cache.ErrCacheMiss if
+// absent or expired
+func (c *KeyDBCacheV2) GetEntry(ctx context.Context, key string) (*models.CacheEntry, error) {
+	return c.kc.getEntry(ctx, key)
+}
+
+// GetStaleEntry retrieves a positive or negative entry regardless of freshness
+func (c *KeyDBCacheV2) GetStaleEntry(ctx context.Context, key string) (*models.CacheEntry, error) {
+	return c.kc.getEntry(ctx, key)
+}
+
+// Set stores value in KeyDB cache with TTL. With write-behind enabled the write is
+// queued, so only encoding errors are returned.
+func (c *KeyDBCacheV2) Set(ctx context.Context, key string, val []byte, ttl models.TTL) error {
+	return c.kc.set(ctx, key, val, ttl)
+}
+
+// SetNegative stores a negative entry in KeyDB cache with TTL
+func (c *KeyDBCacheV2) SetNegative(ctx context.Context, key string, errPayload []byte, ttl models.TTL) error {
+	return c.kc.setNegative(ctx, key, errPayload, ttl)
+}
+
+// Delete removes entry from KeyDB cache
+func (c *KeyDBCacheV2) Delete(ctx context.Context, key string) error {
+	return c.kc.delete(ctx, key)
+}
+
+// Available reports whether L2 accepts requests, i.e. its circuit breaker is closed
+func (c *KeyDBCacheV2) Available() bool {
+	return c.kc.Available()
+}
+
+// HealthCheck returns ErrCircuitOpen while the circuit breaker is not closed and
+// otherwise pings KeyDB
+func (c *KeyDBCacheV2) HealthCheck(ctx context.Context) error {
+	return c.kc.HealthCheck(ctx)
+}
+
+// WithMaxEntrySize is the context-aware counterpart of KeyDBCache.WithMaxEntrySize
+func (c *KeyDBCacheV2) WithMaxEntrySize(size int) cache.CacheV2 {
+	return c.kc.withMaxEntrySize(size).V2()
+}
+
+// Close closes the KeyDB connection
+func (c *KeyDBCacheV2) Close() error {
+	return c.kc.Close()
+}
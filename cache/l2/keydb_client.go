@@ -2,9 +2,16 @@ package l2
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"net"
 	"net/url"
+	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -12,12 +19,35 @@ import (
 	"github.com/status-im/proxy-common/cache"
 )
 
-// Ensure RedisKeyDbClient implements cache.KeyDbClient
-var _ cache.KeyDbClient = (*RedisKeyDbClient)(nil)
+// Ensure RedisKeyDbClient implements cache.ExtendedKeyDbClient
+var _ cache.ExtendedKeyDbClient = (*RedisKeyDbClient)(nil)
 
-// RedisKeyDbClient wraps redis.Client to implement KeyDbClient interface
+const (
+	// SchemeTLS enables TLS; the rediss+sentinel and rediss+cluster variants do the same
+	// for the other topologies
+	SchemeTLS = "rediss"
+	// SchemeSentinel selects Redis Sentinel failover, e.g.
+	// redis+sentinel://:password@sentinel1:26379,sentinel2:26379/mymaster/0
+	SchemeSentinel = "redis+sentinel"
+	// SchemeCluster selects Redis Cluster, e.g. redis+cluster://node1:6379,node2:6379
+	SchemeCluster = "redis+cluster"
+
+	defaultPort         = "6379"
+	defaultSentinelPort = "26379"
+)
+
+// Mode represents the KeyDB deployment topology a client connects to
+type Mode string
+
+const (
+	ModeStandalone Mode = "standalone"
+	ModeSentinel   Mode = "sentinel"
+	ModeCluster    Mode = "cluster"
+)
+
+// RedisKeyDbClient wraps redis.UniversalClient to implement KeyDbClient interface
 type RedisKeyDbClient struct {
-	client *redis.Client
+	client redis.UniversalClient
 	logger cache.Logger
 }
 
@@ -31,44 +61,29 @@ func WithClientLogger(logger cache.Logger) ClientOption {
 	}
 }
 
-// NewRedisKeyDbClient creates a new RedisKeyDbClient instance
-func NewRedisKeyDbClient(cfg *cache.KeyDBConfig, keydbURL string, opts ...ClientOption) (cache.KeyDbClient, error) {
+// NewRedisKeyDbClient creates a new RedisKeyDbClient instance.
+// keydbURL may be a redis:// URL for a single node, a redis+sentinel:// URL listing
+// sentinels and the master name, or a redis+cluster:// URL listing cluster seeds.
+// Hosts may also come from cfg.Sentinel or cfg.Cluster when the URL has none.
+// A rediss scheme enables TLS and user:pass@ authenticates as a Redis 6 ACL user.
+func NewRedisKeyDbClient(cfg *cache.KeyDBConfig, keydbURL string, opts ...ClientOption) (cache.ExtendedKeyDbClient, error) {
 	cfg.ApplyDefaults()
 
-	parsedURL, err := url.Parse(keydbURL)
+	mode, redisOpts, err := parseKeyDBURL(cfg, keydbURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse KeyDB URL: %w", err)
-	}
-
-	host := parsedURL.Hostname()
-	port := parsedURL.Port()
-	if port == "" {
-		port = "6379"
-	}
-
-	redisOpts := &redis.Options{
-		Addr:         fmt.Sprintf("%s:%s", host, port),
-		DialTimeout:  cfg.Connection.ConnectTimeout,
-		ReadTimeout:  cfg.Connection.ReadTimeout,
-		WriteTimeout: cfg.Connection.SendTimeout,
-		PoolSize:     cfg.Keepalive.PoolSize,
-		IdleTimeout:  cfg.Keepalive.MaxIdleTimeout,
-	}
-
-	if parsedURL.User != nil {
-		if password, ok := parsedURL.User.Password(); ok {
-			redisOpts.Password = password
-		}
+		return nil, err
 	}
 
-	if parsedURL.Path != "" && len(parsedURL.Path) > 1 {
-		if db, err := strconv.Atoi(parsedURL.Path[1:]); err == nil {
-			redisOpts.DB = db
-		}
+	var client redis.UniversalClient
+	switch mode {
+	case ModeSentinel:
+		client = redis.NewFailoverClient(redisOpts.Failover())
+	case ModeCluster:
+		client = redis.NewClusterClient(redisOpts.Cluster())
+	default:
+		client = redis.NewClient(redisOpts.Simple())
 	}
 
-	client := redis.NewClient(redisOpts)
-
 	r := &RedisKeyDbClient{
 		client: client,
 		logger: cache.NoopLogger{},
@@ -78,22 +93,202 @@ func NewRedisKeyDbClient(cfg *cache.KeyDBConfig, keydbURL string, opts ...Client
 		opt(r)
 	}
 
+	address := strings.Join(redisOpts.Addrs, ",")
+
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Connection.ConnectTimeout)
 	defer cancel()
 
 	if err := client.Ping(ctx).Err(); err != nil {
 		_ = client.Close()
-		return nil, fmt.Errorf("failed to connect to KeyDB at %s: %w", redisOpts.Addr, err)
+		return nil, fmt.Errorf("failed to connect to KeyDB at %s: %w", address, err)
 	}
 
 	r.logger.Info("Connected to KeyDB",
-		"address", redisOpts.Addr,
+		"address", address,
+		"mode", mode,
+		"tls", redisOpts.TLSConfig != nil,
 		"connect_timeout", cfg.Connection.ConnectTimeout,
 		"pool_size", cfg.Keepalive.PoolSize)
 
 	return r, nil
 }
 
+// parseKeyDBURL resolves the deployment mode and client options from the URL and config
+func parseKeyDBURL(cfg *cache.KeyDBConfig, keydbURL string) (Mode, *redis.UniversalOptions, error) {
+	// net/url rejects comma-separated host lists, so parse the URL with the hosts
+	// stripped and split them separately
+	hosts, strippedURL := splitURLHosts(keydbURL)
+	parsedURL, err := url.Parse(strippedURL)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse KeyDB URL: %w", err)
+	}
+
+	redisOpts := &redis.UniversalOptions{
+		DialTimeout:    cfg.Connection.ConnectTimeout,
+		ReadTimeout:    cfg.Connection.ReadTimeout,
+		WriteTimeout:   cfg.Connection.SendTimeout,
+		PoolSize:       cfg.Keepalive.PoolSize,
+		IdleTimeout:    cfg.Keepalive.MaxIdleTimeout,
+		RouteByLatency: cfg.Cluster.RouteByLatency,
+		RouteRandomly:  cfg.Cluster.RouteRandomly,
+	}
+
+	if parsedURL.User != nil {
+		// A non-empty username selects a Redis 6 ACL user instead of the default user
+		redisOpts.Username = parsedURL.User.Username()
+		if password, ok := parsedURL.User.Password(); ok {
+			redisOpts.Password = password
+		}
+	}
+
+	scheme := parsedURL.Scheme
+	useTLS := cfg.Connection.TLS.Enabled
+	if strings.HasPrefix(scheme, SchemeTLS) {
+		useTLS = true
+		scheme = "redis" + strings.TrimPrefix(scheme, SchemeTLS)
+	}
+	if useTLS {
+		tlsConfig, err := newTLSConfig(cfg.Connection.TLS)
+		if err != nil {
+			return "", nil, err
+		}
+		redisOpts.TLSConfig = tlsConfig
+	}
+
+	mode := ModeStandalone
+	switch scheme {
+	case SchemeSentinel:
+		mode = ModeSentinel
+	case SchemeCluster:
+		mode = ModeCluster
+	default:
+		if cfg.Sentinel.MasterName != "" {
+			mode = ModeSentinel
+		} else if len(cfg.Cluster.Addrs) > 0 {
+			mode = ModeCluster
+		}
+	}
+
+	pathSegments := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
+	if len(pathSegments) == 1 && pathSegments[0] == "" {
+		pathSegments = nil
+	}
+
+	switch mode {
+	case ModeSentinel:
+		redisOpts.Addrs = splitHosts(hosts, defaultSentinelPort)
+		if len(redisOpts.Addrs) == 0 {
+			redisOpts.Addrs = cfg.Sentinel.Addrs
+		}
+		redisOpts.MasterName = cfg.Sentinel.MasterName
+		if len(pathSegments) > 0 {
+			redisOpts.MasterName = pathSegments[0]
+			pathSegments = pathSegments[1:]
+		}
+		if redisOpts.MasterName == "" {
+			return "", nil, fmt.Errorf("sentinel master name is required")
+		}
+		redisOpts.SentinelPassword = cfg.Sentinel.Password
+	case ModeCluster:
+		redisOpts.Addrs = splitHosts(hosts, defaultPort)
+		if len(redisOpts.Addrs) == 0 {
+			redisOpts.Addrs = cfg.Cluster.Addrs
+		}
+	default:
+		redisOpts.Addrs = splitHosts(hosts, defaultPort)
+		if len(redisOpts.Addrs) > 1 {
+			return "", nil, fmt.Errorf("multiple KeyDB hosts require the %s:// or %s:// scheme", SchemeSentinel, SchemeCluster)
+		}
+		if len(redisOpts.Addrs) == 0 {
+			// Preserve the historical behaviour of dialing the default port on localhost
+			redisOpts.Addrs = []string{":" + defaultPort}
+		}
+	}
+
+	if len(redisOpts.Addrs) == 0 {
+		return "", nil, fmt.Errorf("no KeyDB address configured for %s mode", mode)
+	}
+
+	if len(pathSegments) > 0 {
+		if db, err := strconv.Atoi(pathSegments[0]); err == nil {
+			redisOpts.DB = db
+		}
+	}
+	if mode == ModeCluster && redisOpts.DB != 0 {
+		return "", nil, fmt.Errorf("redis cluster does not support database %d", redisOpts.DB)
+	}
+
+	return mode, redisOpts, nil
+}
+
+// newTLSConfig builds the client TLS configuration from the connection settings
+func newTLSConfig(cfg cache.TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec // explicitly opted into via config
+	}
+
+	if cfg.CAFile != "" {
+		caPEM, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read KeyDB CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in KeyDB CA file %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load KeyDB client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// splitURLHosts separates the host list from a URL, returning the hosts and the URL without them
+func splitURLHosts(rawURL string) (string, string) {
+	schemeEnd := strings.Index(rawURL, "://")
+	if schemeEnd < 0 {
+		return "", rawURL
+	}
+
+	authorityStart := schemeEnd + len("://")
+	authorityEnd := len(rawURL)
+	if i := strings.IndexAny(rawURL[authorityStart:], "/?#"); i >= 0 {
+		authorityEnd = authorityStart + i
+	}
+
+	hostStart := authorityStart
+	if i := strings.LastIndex(rawURL[authorityStart:authorityEnd], "@"); i >= 0 {
+		hostStart = authorityStart + i + 1
+	}
+
+	return rawURL[hostStart:authorityEnd], rawURL[:hostStart] + rawURL[authorityEnd:]
+}
+
+// splitHosts splits a comma-separated host list, adding defaultPort where missing
+func splitHosts(hosts, defaultPort string) []string {
+	var addrs []string
+	for _, host := range strings.Split(hosts, ",") {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			host = net.JoinHostPort(strings.Trim(host, "[]"), defaultPort)
+		}
+		addrs = append(addrs, host)
+	}
+	return addrs
+}
+
 func (r *RedisKeyDbClient) Get(ctx context.Context, key string) *redis.StringCmd {
 	return r.client.Get(ctx, key)
 }
@@ -106,6 +301,74 @@ func (r *RedisKeyDbClient) Del(ctx context.Context, keys ...string) *redis.IntCm
 	return r.client.Del(ctx, keys...)
 }
 
+// MGet fetches several keys in one round trip. Redis Cluster rejects MGET across hash
+// slots, so in cluster mode the keys are fetched with a pipeline of GETs instead, which
+// go-redis splits into one round trip per node.
+func (r *RedisKeyDbClient) MGet(ctx context.Context, keys ...string) *redis.SliceCmd {
+	if _, ok := r.client.(*redis.ClusterClient); !ok {
+		return r.client.MGet(ctx, keys...)
+	}
+
+	pipe := r.client.Pipeline()
+	cmds := make([]*redis.StringCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.Get(ctx, key)
+	}
+	// Exec reports the first failed command, which may just be a missing key
+	_, _ = pipe.Exec(ctx)
+
+	vals := make([]interface{}, len(keys))
+	for i, cmd := range cmds {
+		val, err := cmd.Result()
+		switch {
+		case err == nil:
+			vals[i] = val
+		case !errors.Is(err, redis.Nil):
+			return redis.NewSliceResult(nil, err)
+		}
+	}
+	return redis.NewSliceResult(vals, nil)
+}
+
+func (r *RedisKeyDbClient) Pipeline() redis.Pipeliner {
+	return r.client.Pipeline()
+}
+
+func (r *RedisKeyDbClient) SMembers(ctx context.Context, key string) *redis.StringSliceCmd {
+	return r.client.SMembers(ctx, key)
+}
+
+// Scan iterates keys matching a pattern. SCAN cursors are per node, so in cluster mode
+// every master is scanned to completion in a single call and the returned cursor is 0.
+func (r *RedisKeyDbClient) Scan(ctx context.Context, cursor uint64, match string, count int64) *redis.ScanCmd {
+	cluster, ok := r.client.(*redis.ClusterClient)
+	if !ok {
+		return r.client.Scan(ctx, cursor, match, count)
+	}
+
+	var mu sync.Mutex
+	var keys []string
+	err := cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+		iter := node.Scan(ctx, 0, match, count).Iterator()
+		for iter.Next(ctx) {
+			mu.Lock()
+			keys = append(keys, iter.Val())
+			mu.Unlock()
+		}
+		return iter.Err()
+	})
+
+	return redis.NewScanCmdResult(keys, 0, err)
+}
+
+func (r *RedisKeyDbClient) Publish(ctx context.Context, channel string, message interface{}) *redis.IntCmd {
+	return r.client.Publish(ctx, channel, message)
+}
+
+func (r *RedisKeyDbClient) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	return r.client.Subscribe(ctx, channels...)
+}
+
 func (r *RedisKeyDbClient) Ping(ctx context.Context) *redis.StatusCmd {
 	return r.client.Ping(ctx)
 }
@@ -1,17 +1,22 @@
 package l2
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/go-redis/redis/v8"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/cache/codec"
 	"github.com/status-im/proxy-common/cache/mock"
+	"github.com/status-im/proxy-common/clock"
 	"github.com/status-im/proxy-common/models"
 )
 
@@ -414,3 +419,460 @@ func TestKeyDBCache_Close_Error(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, expectedErr, err)
 }
+
+func TestKeyDBCacheV2_Get_Miss(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockKeyDbClient(ctrl)
+	c := NewKeyDBCacheV2(&cache.KeyDBConfig{}, mockClient)
+
+	mockClient.EXPECT().Get(gomock.Any(), "test-key").Return(redis.NewStringResult("", redis.Nil))
+
+	result, err := c.Get(context.Background(), "test-key")
+
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	assert.Nil(t, result)
+}
+
+func TestKeyDBCacheV2_Get_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockKeyDbClient(ctrl)
+	c := NewKeyDBCacheV2(&cache.KeyDBConfig{}, mockClient)
+
+	connErr := errors.New("connection refused")
+	mockClient.EXPECT().Get(gomock.Any(), "test-key").Return(redis.NewStringResult("", connErr))
+
+	result, err := c.Get(context.Background(), "test-key")
+
+	assert.ErrorIs(t, err, connErr)
+	assert.NotErrorIs(t, err, cache.ErrCacheMiss)
+	assert.Nil(t, result)
+}
+
+func TestKeyDBCacheV2_Get_PropagatesDeadline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockKeyDbClient(ctrl)
+	cfg := &cache.KeyDBConfig{Connection: cache.ConnectionConfig{ReadTimeout: time.Hour}}
+	c := NewKeyDBCacheV2(cfg, mockClient)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	callerDeadline, _ := ctx.Deadline()
+
+	mockClient.EXPECT().Get(gomock.Any(), "test-key").DoAndReturn(func(ctx context.Context, key string) *redis.StringCmd {
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.Equal(t, callerDeadline, deadline)
+		return redis.NewStringResult("", redis.Nil)
+	})
+
+	_, err := c.Get(ctx, "test-key")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+}
+
+func TestKeyDBCacheV2_Set_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockKeyDbClient(ctrl)
+	c := NewKeyDBCacheV2(&cache.KeyDBConfig{}, mockClient)
+
+	setErr := errors.New("write timeout")
+	mockClient.EXPECT().Set(gomock.Any(), "test-key", gomock.Any(), 90*time.Second).Return(redis.NewStatusResult("", setErr))
+
+	err := c.Set(context.Background(), "test-key", []byte("test-data"), models.TTL{Fresh: 60 * time.Second, Stale: 30 * time.Second})
+
+	assert.ErrorIs(t, err, setErr)
+}
+
+func TestKeyDBCacheV2_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockKeyDbClient(ctrl)
+	c := NewKeyDBCacheV2(&cache.KeyDBConfig{}, mockClient)
+
+	mockClient.EXPECT().Del(gomock.Any(), "test-key").Return(redis.NewIntResult(1, nil))
+
+	assert.NoError(t, c.Delete(context.Background(), "test-key"))
+}
+
+func TestKeyDBCache_Set_UsesCodec(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testTTL := models.TTL{Fresh: 60 * time.Second, Stale: 30 * time.Second}
+
+	t.Run("json by default", func(t *testing.T) {
+		mockClient := mock.NewMockKeyDbClient(ctrl)
+		c := NewKeyDBCache(&cache.KeyDBConfig{}, mockClient)
+
+		mockClient.EXPECT().Set(gomock.Any(), "test-key", gomock.Any(), 90*time.Second).
+			DoAndReturn(func(_ context.Context, _ string, value interface{}, _ time.Duration) *redis.StatusCmd {
+				// The shape read by releases predating the binary codec
+				var entry struct {
+					Data      []byte `json:"data"`
+					ExpiresAt int64  `json:"expires_at"`
+				}
+				assert.NoError(t, json.Unmarshal(value.([]byte), &entry))
+				assert.Equal(t, []byte("test-data"), entry.Data)
+				return redis.NewStatusResult("OK", nil)
+			})
+
+		c.Set("test-key", []byte("test-data"), testTTL)
+	})
+
+	t.Run("binary when configured", func(t *testing.T) {
+		mockClient := mock.NewMockKeyDbClient(ctrl)
+		c := NewKeyDBCache(&cache.KeyDBConfig{Format: cache.EntryFormatBinary}, mockClient)
+
+		mockClient.EXPECT().Set(gomock.Any(), "test-key", gomock.Any(), 90*time.Second).
+			DoAndReturn(func(_ context.Context, _ string, value interface{}, _ time.Duration) *redis.StatusCmd {
+				data := value.([]byte)
+				assert.Equal(t, codec.Magic, data[0])
+				assert.Equal(t, []byte("test-data"), data[codec.HeaderSize:])
+				return redis.NewStatusResult("OK", nil)
+			})
+
+		c.Set("test-key", []byte("test-data"), testTTL)
+	})
+
+	t.Run("json when compression is invalid", func(t *testing.T) {
+		mockClient := mock.NewMockKeyDbClient(ctrl)
+		cfg := &cache.KeyDBConfig{Format: cache.EntryFormatBinary}
+		cfg.Compression.Algorithm = "lz4"
+		c := NewKeyDBCache(cfg, mockClient)
+
+		mockClient.EXPECT().Set(gomock.Any(), "test-key", gomock.Any(), 90*time.Second).
+			DoAndReturn(func(_ context.Context, _ string, value interface{}, _ time.Duration) *redis.StatusCmd {
+				assert.Equal(t, byte('{'), value.([]byte)[0])
+				return redis.NewStatusResult("OK", nil)
+			})
+
+		c.Set("test-key", []byte("test-data"), testTTL)
+	})
+}
+
+func TestKeyDBCache_Get_BinaryEntry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockKeyDbClient(ctrl)
+	c := NewKeyDBCache(&cache.KeyDBConfig{}, mockClient)
+
+	now := time.Now().Unix()
+	encoded, _ := codec.Binary{}.Encode(&models.CacheEntry{
+		Data:      []byte("test-data"),
+		CreatedAt: now,
+		StaleAt:   now + 100,
+		ExpiresAt: now + 200,
+	})
+	mockClient.EXPECT().Get(gomock.Any(), "test-key").Return(redis.NewStringResult(string(encoded), nil))
+
+	result, found := c.Get("test-key")
+
+	assert.True(t, found)
+	assert.Equal(t, []byte("test-data"), result.Data)
+}
+
+// newMiniredisCache creates a KeyDBCache backed by an in-process Redis server
+func newMiniredisCache(t *testing.T, opts ...Option) (*KeyDBCache, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client, err := NewRedisKeyDbClient(&cache.KeyDBConfig{}, "redis://"+server.Addr())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return NewKeyDBCache(&cache.KeyDBConfig{}, client, opts...).(*KeyDBCache), server
+}
+
+func TestKeyDBCache_InvalidateTag(t *testing.T) {
+	c, server := newMiniredisCache(t)
+
+	c.SetWithTags("block:100:a", []byte("a"), models.TTL{Fresh: time.Minute}, "block:100", "chain:1")
+	c.SetWithTags("block:100:b", []byte("b"), models.TTL{Fresh: time.Hour}, "block:100")
+	c.SetWithTags("block:101:a", []byte("c"), models.TTL{Fresh: time.Minute}, "block:101")
+
+	// The tag set lives as long as its longest-lived member
+	assert.Equal(t, time.Hour, server.TTL(tagKeyPrefix+"block:100"))
+	c.SetWithTags("block:100:c", []byte("c"), models.TTL{Fresh: time.Second}, "block:100")
+	assert.Equal(t, time.Hour, server.TTL(tagKeyPrefix+"block:100"))
+
+	removed := c.InvalidateTag("block:100")
+
+	assert.ElementsMatch(t, []string{"block:100:a", "block:100:b", "block:100:c"}, removed)
+	assert.False(t, server.Exists("block:100:a"))
+	assert.False(t, server.Exists("block:100:b"))
+	assert.False(t, server.Exists(tagKeyPrefix+"block:100"))
+	assert.True(t, server.Exists("block:101:a"))
+	assert.Empty(t, c.InvalidateTag("block:100"))
+}
+
+func TestKeyDBCache_InvalidatePrefix(t *testing.T) {
+	c, server := newMiniredisCache(t)
+
+	ttl := models.TTL{Fresh: time.Minute}
+	c.Set("chain:1:block:100", []byte("a"), ttl)
+	c.Set("chain:1:block:101", []byte("b"), ttl)
+	c.Set("chain:10:block:100", []byte("c"), ttl)
+	c.Set("chain:1*", []byte("d"), ttl)
+
+	removed := c.InvalidatePrefix("chain:1:")
+
+	assert.ElementsMatch(t, []string{"chain:1:block:100", "chain:1:block:101"}, removed)
+	assert.True(t, server.Exists("chain:10:block:100"))
+
+	// Glob characters in the prefix are matched literally
+	assert.Equal(t, []string{"chain:1*"}, c.InvalidatePrefix("chain:1*"))
+	assert.True(t, server.Exists("chain:10:block:100"))
+}
+
+func TestKeyDBCache_InvalidatePrefix_SinglePipeline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = redisClient.Close() })
+
+	keys := []string{"chain:1:block:100", "chain:1:block:101", "chain:1:block:102"}
+	for _, key := range keys {
+		require.NoError(t, server.Set(key, "value"))
+	}
+
+	mockClient := mock.NewMockExtendedKeyDbClient(ctrl)
+	c := NewKeyDBCache(&cache.KeyDBConfig{}, mockClient).(*KeyDBCache)
+
+	mockClient.EXPECT().Scan(gomock.Any(), uint64(0), "chain:1:*", int64(scanCount)).
+		Return(redis.NewScanCmdResult(keys, 0, nil))
+	// Every DEL goes through one pipeline rather than a round trip per key
+	mockClient.EXPECT().Pipeline().Return(redisClient.Pipeline())
+
+	removed := c.InvalidatePrefix("chain:1:")
+
+	assert.ElementsMatch(t, keys, removed)
+	for _, key := range keys {
+		assert.False(t, server.Exists(key))
+	}
+}
+
+func TestKeyDBCache_InvalidateTag_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockExtendedKeyDbClient(ctrl)
+	c := NewKeyDBCache(&cache.KeyDBConfig{}, mockClient).(*KeyDBCache)
+
+	mockClient.EXPECT().SMembers(gomock.Any(), tagKeyPrefix+"tag").
+		Return(redis.NewStringSliceResult(nil, errors.New("connection refused")))
+
+	assert.Empty(t, c.InvalidateTag("tag"))
+}
+
+func TestKeyDBCache_GetMany_SetMany(t *testing.T) {
+	c, server := newMiniredisCache(t)
+
+	c.SetMany([]cache.BatchItem{
+		{Key: "a", Val: []byte("1"), TTL: models.TTL{Fresh: time.Minute}},
+		{Key: "b", Val: []byte("2"), TTL: models.TTL{Fresh: time.Minute, Stale: time.Minute}},
+	})
+	assert.Equal(t, 2*time.Minute, server.TTL("b"))
+
+	require.NoError(t, server.Set("corrupted", "garbage"))
+
+	entries := c.GetMany([]string{"a", "b", "missing", "corrupted"})
+
+	assert.Len(t, entries, 2)
+	assert.Equal(t, []byte("1"), entries["a"].Data)
+	assert.Equal(t, []byte("2"), entries["b"].Data)
+	assert.False(t, server.Exists("corrupted"))
+}
+
+func TestKeyDBCache_WithMaxEntrySize(t *testing.T) {
+	c, server := newMiniredisCache(t)
+	view := c.WithMaxEntrySize(200)
+	ttl := models.TTL{Fresh: time.Minute}
+
+	view.Set("small", []byte("1"), ttl)
+	assert.True(t, server.Exists("small"))
+
+	// The raw value fits, but the encoded entry does not
+	view.Set("large", make([]byte, 48), ttl)
+	view.(cache.BatchCache).SetMany([]cache.BatchItem{{Key: "large", Val: make([]byte, 48), TTL: ttl}})
+	assert.False(t, server.Exists("large"))
+
+	// The cache itself has no limit
+	c.Set("large", make([]byte, 48), ttl)
+	assert.True(t, server.Exists("large"))
+}
+
+func TestKeyDBCache_GetMany_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockExtendedKeyDbClient(ctrl)
+	c := NewKeyDBCache(&cache.KeyDBConfig{}, mockClient).(*KeyDBCache)
+
+	mockClient.EXPECT().MGet(gomock.Any(), "a", "b").
+		Return(redis.NewSliceResult(nil, errors.New("connection refused")))
+
+	assert.Empty(t, c.GetMany([]string{"a", "b"}))
+}
+
+func TestKeyDBCache_PlainClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockKeyDbClient(ctrl)
+	c := NewKeyDBCache(&cache.KeyDBConfig{}, mockClient).(*KeyDBCache)
+	ttl := models.TTL{Fresh: time.Minute}
+
+	t.Run("batches fall back to one request per key", func(t *testing.T) {
+		mockClient.EXPECT().Set(gomock.Any(), "a", gomock.Any(), time.Minute).Return(redis.NewStatusResult("OK", nil))
+		mockClient.EXPECT().Set(gomock.Any(), "b", gomock.Any(), time.Minute).Return(redis.NewStatusResult("OK", nil))
+		c.SetMany([]cache.BatchItem{{Key: "a", Val: []byte("1"), TTL: ttl}, {Key: "b", Val: []byte("2"), TTL: ttl}})
+
+		data, err := codec.JSON{}.Encode(models.NewCacheEntry([]byte("1"), ttl, time.Now()))
+		require.NoError(t, err)
+		mockClient.EXPECT().Get(gomock.Any(), "a").Return(redis.NewStringResult(string(data), nil))
+		mockClient.EXPECT().Get(gomock.Any(), "b").Return(redis.NewStringResult("", redis.Nil))
+
+		entries := c.GetMany([]string{"a", "b"})
+		assert.Len(t, entries, 1)
+		assert.Equal(t, []byte("1"), entries["a"].Data)
+	})
+
+	t.Run("tags are not recorded", func(t *testing.T) {
+		mockClient.EXPECT().Set(gomock.Any(), "a", gomock.Any(), time.Minute).Return(redis.NewStatusResult("OK", nil))
+		c.SetWithTags("a", []byte("1"), ttl, "tag")
+
+		_, err := c.invalidateTag(context.Background(), "tag")
+		assert.ErrorIs(t, err, errors.ErrUnsupported)
+		_, err = c.invalidatePrefix(context.Background(), "a")
+		assert.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+}
+
+func TestKeyDBCache_Set_EnforcesTTLLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ttlMetrics := mock.NewMockTTLMetricsRecorder(ctrl)
+	ttlMetrics.EXPECT().RecordCacheTTLAdjusted("l2", cache.TTLAdjustmentDefault).Times(2)
+	ttlMetrics.EXPECT().RecordCacheTTLAdjusted("l2", cache.TTLAdjustmentClamped).Times(1)
+
+	// The TTL adjustments reach a recorder implementing the optional interface
+	metrics := struct {
+		*mock.MockMetricsRecorder
+		*mock.MockTTLMetricsRecorder
+	}{mock.NewMockMetricsRecorder(ctrl), ttlMetrics}
+	c, server := newMiniredisCache(t, WithMetrics(metrics))
+
+	// A zero TTL would otherwise create a key that never expires
+	c.Set("zero-ttl", []byte("a"), models.TTL{})
+	assert.Equal(t, time.Hour, server.TTL("zero-ttl"))
+
+	c.Set("long-ttl", []byte("b"), models.TTL{Fresh: 20 * time.Hour, Stale: 10 * time.Hour})
+	assert.Equal(t, 24*time.Hour, server.TTL("long-ttl"))
+	entry, found := c.Get("long-ttl")
+	require.True(t, found)
+	assert.Equal(t, (24 * time.Hour).Milliseconds(), entry.ExpiresAtMs-entry.CreatedAtMs)
+	assert.Equal(t, (20 * time.Hour).Milliseconds(), entry.StaleAtMs-entry.CreatedAtMs)
+
+	c.Set("in-range", []byte("c"), models.TTL{Fresh: time.Minute})
+	assert.Equal(t, time.Minute, server.TTL("in-range"))
+
+	c.SetMany([]cache.BatchItem{{Key: "batch-zero-ttl", Val: []byte("d")}})
+	assert.Equal(t, time.Hour, server.TTL("batch-zero-ttl"))
+}
+
+func TestKeyDBCache_Set_TTLScale(t *testing.T) {
+	server := miniredis.RunT(t)
+	client, err := NewRedisKeyDbClient(&cache.KeyDBConfig{}, "redis://"+server.Addr())
+	require.NoError(t, err)
+	defer client.Close()
+
+	c := NewKeyDBCache(&cache.KeyDBConfig{Cache: cache.CacheSettings{TTLScale: 2}}, client)
+	c.Set("test-key", []byte("a"), models.TTL{Fresh: time.Minute, Stale: time.Minute})
+
+	assert.Equal(t, 4*time.Minute, server.TTL("test-key"))
+}
+
+func TestKeyDBCache_Set_TTLJitter(t *testing.T) {
+	server := miniredis.RunT(t)
+	client, err := NewRedisKeyDbClient(&cache.KeyDBConfig{}, "redis://"+server.Addr())
+	require.NoError(t, err)
+	defer client.Close()
+
+	mockClock := clock.NewMock(time.Unix(1700000000, 0))
+	jitter := cache.TTLJitterConfig{Percent: 20, Deterministic: true}
+	c := NewKeyDBCache(&cache.KeyDBConfig{Cache: cache.CacheSettings{TTLJitter: jitter}}, client, WithClock(mockClock))
+
+	ttl := models.TTL{Fresh: 10 * time.Minute, Stale: 10 * time.Minute}
+	c.Set("key-a", []byte("a"), ttl)
+	c.(*KeyDBCache).SetMany([]cache.BatchItem{{Key: "key-b", Val: []byte("b"), TTL: ttl}})
+
+	for _, key := range []string{"key-a", "key-b"} {
+		want := jitter.Apply(key, ttl)
+		assert.Equal(t, (want.Fresh + want.Stale).Truncate(time.Millisecond), server.TTL(key))
+
+		entry, found := c.Get(key)
+		require.True(t, found)
+		assert.Equal(t, mockClock.Now().Add(want.Fresh).UnixMilli(), entry.StaleAtMs)
+		assert.Equal(t, want.Stale.Milliseconds(), entry.ExpiresAtMs-entry.StaleAtMs)
+	}
+	assert.NotEqual(t, server.TTL("key-a"), server.TTL("key-b"))
+}
+
+func TestKeyDBCache_Get_ExpiredByClock(t *testing.T) {
+	server := miniredis.RunT(t)
+	client, err := NewRedisKeyDbClient(&cache.KeyDBConfig{}, "redis://"+server.Addr())
+	require.NoError(t, err)
+	defer client.Close()
+
+	mockClock := clock.NewMock(time.Unix(1700000000, 0))
+	c := NewKeyDBCache(&cache.KeyDBConfig{}, client, WithClock(mockClock))
+	c.Set("test-key", []byte("a"), models.TTL{Fresh: 500 * time.Millisecond, Stale: 500 * time.Millisecond})
+
+	mockClock.Advance(999 * time.Millisecond)
+	_, found := c.Get("test-key")
+	assert.True(t, found)
+
+	// The entry is expired by its own timestamps even while Redis still holds the key
+	mockClock.Advance(2 * time.Millisecond)
+	_, found = c.Get("test-key")
+	assert.False(t, found)
+	assert.False(t, server.Exists("test-key"))
+}
+
+func TestKeyDBCache_SetNegative(t *testing.T) {
+	c, server := newMiniredisCache(t)
+
+	errPayload := []byte(`{"code":-32000,"message":"unknown transaction"}`)
+	c.SetNegative("tx-key", errPayload, models.TTL{Fresh: 5 * time.Second})
+	assert.Equal(t, 5*time.Second, server.TTL("tx-key"))
+
+	_, found := c.Get("tx-key")
+	assert.False(t, found, "Get must report negative entries as misses")
+	assert.Empty(t, c.GetMany([]string{"tx-key"}))
+
+	entry, found := c.GetEntry("tx-key")
+	require.True(t, found)
+	assert.True(t, entry.IsNegative())
+	assert.Equal(t, errPayload, entry.Error)
+	assert.Contains(t, c.GetManyEntries([]string{"tx-key"}), "tx-key")
+
+	require.NoError(t, c.V2().SetNegative(context.Background(), "empty-key", nil, models.TTL{Fresh: time.Second}))
+	_, err := c.V2().Get(context.Background(), "empty-key")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	entry, err = c.V2().GetEntry(context.Background(), "empty-key")
+	require.NoError(t, err)
+	assert.True(t, entry.IsNegative())
+	assert.Nil(t, entry.Error)
+}
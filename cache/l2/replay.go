@@ -0,0 +1,168 @@
+package l2
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// maxMissedDeletes bounds the deletes recorded while the circuit breaker is open. Later
+// deletes are dropped and logged when the breaker recovers, so their entries may be
+// served until they expire.
+const maxMissedDeletes = 100_000
+
+// deleteSet holds keys, tags and prefixes to remove from KeyDB
+type deleteSet struct {
+	keys     map[string]struct{}
+	tags     map[string]struct{}
+	prefixes map[string]struct{}
+}
+
+func newDeleteSet() deleteSet {
+	return deleteSet{
+		keys:     make(map[string]struct{}),
+		tags:     make(map[string]struct{}),
+		prefixes: make(map[string]struct{}),
+	}
+}
+
+func (s deleteSet) len() int {
+	return len(s.keys) + len(s.tags) + len(s.prefixes)
+}
+
+// missedDeletes records the deletes, negative writes and invalidations rejected while the
+// circuit breaker is open, so they can be replayed before it closes and KeyDB cannot serve
+// entries removed while it was unreachable. A nil missedDeletes records nothing.
+type missedDeletes struct {
+	mu      sync.Mutex
+	pending deleteSet
+	dropped int
+}
+
+func newMissedDeletes() *missedDeletes {
+	return &missedDeletes{pending: newDeleteSet()}
+}
+
+// addKeys records keys whose delete was rejected
+func (m *missedDeletes) addKeys(keys ...string) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		m.insert(m.pending.keys, key)
+	}
+}
+
+// addTag records a tag whose invalidation was rejected
+func (m *missedDeletes) addTag(tag string) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.insert(m.pending.tags, tag)
+}
+
+// addPrefix records a prefix whose invalidation was rejected
+func (m *missedDeletes) addPrefix(prefix string) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.insert(m.pending.prefixes, prefix)
+}
+
+// insert adds value to one of the pending sets unless the limit is reached; callers hold mu
+func (m *missedDeletes) insert(set map[string]struct{}, value string) {
+	if _, ok := set[value]; ok {
+		return
+	}
+	if m.pending.len() >= maxMissedDeletes {
+		m.dropped++
+		return
+	}
+	set[value] = struct{}{}
+}
+
+// take returns the recorded deletes and the number dropped, and starts recording anew
+func (m *missedDeletes) take() (deleteSet, int) {
+	if m == nil {
+		return newDeleteSet(), 0
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, dropped := m.pending, m.dropped
+	m.pending, m.dropped = newDeleteSet(), 0
+	return s, dropped
+}
+
+// restore records the deletes of a replay that failed, so the next one retries them
+func (m *missedDeletes) restore(s deleteSet) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key := range s.keys {
+		m.insert(m.pending.keys, key)
+	}
+	for tag := range s.tags {
+		m.insert(m.pending.tags, tag)
+	}
+	for prefix := range s.prefixes {
+		m.insert(m.pending.prefixes, prefix)
+	}
+}
+
+// replayDeletes sends the deletes rejected while the circuit breaker was open. The breaker
+// calls it while half-open, when deletes pass, once a probe succeeds, and closes only if
+// it returns nil.
+func (kc *KeyDBCache) replayDeletes(ctx context.Context) error {
+	missed, dropped := kc.missed.take()
+	if dropped > 0 {
+		kc.logger.Error("Dropped L2 deletes while the circuit breaker was open, their entries are served until they expire",
+			"dropped", dropped)
+	}
+	if missed.len() == 0 {
+		return nil
+	}
+
+	if err := kc.replay(ctx, missed); err != nil {
+		kc.logger.Warn("Failed to replay L2 deletes", "error", err)
+		kc.missed.restore(missed)
+		return err
+	}
+
+	kc.logger.Info("Replayed L2 deletes rejected by the circuit breaker",
+		"keys", len(missed.keys), "tags", len(missed.tags), "prefixes", len(missed.prefixes))
+	return nil
+}
+
+// replay removes every key, tag and prefix in s, sending keys in batches of scanCount
+func (kc *KeyDBCache) replay(ctx context.Context, s deleteSet) error {
+	for prefix := range s.prefixes {
+		if _, err := kc.invalidatePrefix(ctx, prefix); err != nil {
+			return err
+		}
+	}
+	for tag := range s.tags {
+		if _, err := kc.invalidateTag(ctx, tag); err != nil {
+			return err
+		}
+	}
+	for keys := range slices.Chunk(slices.Collect(maps.Keys(s.keys)), scanCount) {
+		if _, err := kc.sendDeletes(ctx, keys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
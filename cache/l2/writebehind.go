@@ -0,0 +1,242 @@
+package l2
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/clock"
+)
+
+// pendingWrite is an encoded entry waiting in the write-behind queue, with the tag sets
+// its key is added to
+type pendingWrite struct {
+	key      string
+	data     []byte
+	ttl      time.Duration
+	tags     []string
+	negative bool
+}
+
+// writeBehind queues L2 writes and sends them in pipelined batches from a background
+// goroutine. A nil writeBehind has nothing queued.
+type writeBehind struct {
+	cfg     cache.WriteBehindConfig
+	write   func(ctx context.Context, batch []pendingWrite) error
+	timeout time.Duration
+	logger  cache.Logger
+	metrics cache.WriteBehindMetricsRecorder
+
+	mu       sync.Mutex
+	queue    []pendingWrite
+	inflight []pendingWrite // batch being written, if any
+	written  chan struct{}  // closed once inflight is written
+	closed   bool
+
+	wake chan struct{}
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newWriteBehind starts a writer that sends batches with write, bounding each by timeout
+func newWriteBehind(cfg cache.WriteBehindConfig, write func(ctx context.Context, batch []pendingWrite) error,
+	timeout time.Duration, c clock.Clock, logger cache.Logger, metrics cache.MetricsRecorder) *writeBehind {
+	w := &writeBehind{
+		cfg:     cfg,
+		write:   write,
+		timeout: timeout,
+		logger:  logger,
+		metrics: cache.OptionalMetrics[cache.WriteBehindMetricsRecorder](metrics),
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+
+	ticker := c.NewTicker(cfg.FlushInterval)
+	w.wg.Add(1)
+	go w.run(ticker)
+
+	return w
+}
+
+// enqueue queues a write, dropping the oldest queued write or pw itself, depending on
+// the overflow policy, when the queue is full
+func (w *writeBehind) enqueue(pw pendingWrite) {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		w.metrics.RecordWriteDropped("l2", "closed")
+		return
+	}
+
+	dropped := false
+	if len(w.queue) >= w.cfg.QueueSize {
+		dropped = true
+		if w.cfg.Overflow == cache.OverflowDropNewest {
+			w.mu.Unlock()
+			w.metrics.RecordWriteDropped("l2", "queue_full")
+			return
+		}
+		w.queue[0] = pendingWrite{}
+		w.queue = w.queue[1:]
+	}
+	w.queue = append(w.queue, pw)
+	depth := len(w.queue)
+	w.mu.Unlock()
+
+	if dropped {
+		w.metrics.RecordWriteDropped("l2", "queue_full")
+	}
+	w.metrics.UpdateWriteQueueDepth("l2", depth)
+
+	if depth >= w.cfg.BatchSize {
+		select {
+		case w.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// discard removes the queued writes that match and waits, bounded by ctx, until an
+// in-flight batch holding a matching write is written, so neither can overwrite a
+// later delete
+func (w *writeBehind) discard(ctx context.Context, match func(pw pendingWrite) bool) error {
+	if w == nil {
+		return nil
+	}
+
+	w.mu.Lock()
+	kept := w.queue[:0]
+	for _, pw := range w.queue {
+		if !match(pw) {
+			kept = append(kept, pw)
+		}
+	}
+	clear(w.queue[len(kept):])
+	w.queue = kept
+	depth := len(w.queue)
+
+	var written chan struct{}
+	if slices.ContainsFunc(w.inflight, match) {
+		written = w.written
+	}
+	w.mu.Unlock()
+
+	w.metrics.UpdateWriteQueueDepth("l2", depth)
+
+	if written == nil {
+		return nil
+	}
+	select {
+	case <-written:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// depth returns the number of queued writes
+func (w *writeBehind) depth() int {
+	if w == nil {
+		return 0
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.queue)
+}
+
+// close stops accepting writes and returns once the queued ones were sent
+func (w *writeBehind) close() {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	close(w.done)
+	w.wg.Wait()
+}
+
+// run flushes the queue whenever a batch is full or the ticker fires, and once more on close
+func (w *writeBehind) run(ticker clock.Ticker) {
+	defer w.wg.Done()
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.wake:
+		case <-ticker.C():
+		case <-w.done:
+			w.flush()
+			return
+		}
+		w.flush()
+	}
+}
+
+// flush sends queued writes in batches of at most BatchSize until the queue is empty
+func (w *writeBehind) flush() {
+	for {
+		batch := w.next()
+		if len(batch) == 0 {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+		err := w.write(ctx, batch)
+		cancel()
+		w.finish()
+		if err == nil {
+			continue
+		}
+
+		reason := "error"
+		if errors.Is(err, ErrCircuitOpen) {
+			reason = "circuit_open"
+		} else {
+			w.logger.Warn("Failed to flush L2 write-behind batch", "writes", len(batch), "error", err)
+		}
+		for range batch {
+			w.metrics.RecordWriteDropped("l2", reason)
+		}
+	}
+}
+
+// next takes up to BatchSize writes off the queue
+func (w *writeBehind) next() []pendingWrite {
+	w.mu.Lock()
+	n := min(len(w.queue), w.cfg.BatchSize)
+	batch := make([]pendingWrite, n)
+	copy(batch, w.queue)
+	w.queue = w.queue[n:]
+	depth := len(w.queue)
+	if n > 0 {
+		w.inflight = batch
+		w.written = make(chan struct{})
+	}
+	w.mu.Unlock()
+
+	if n > 0 {
+		w.metrics.UpdateWriteQueueDepth("l2", depth)
+	}
+	return batch
+}
+
+// finish marks the batch taken by next as written, releasing the deletes waiting for it
+func (w *writeBehind) finish() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	close(w.written)
+	w.inflight = nil
+	w.written = nil
+}
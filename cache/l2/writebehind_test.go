@@ -0,0 +1,252 @@
+package l2
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/clock"
+	"github.com/status-im/proxy-common/models"
+)
+
+// writeRecorder records write-behind drops and queue depth
+type writeRecorder struct {
+	cache.NoopMetrics
+	mu      sync.Mutex
+	dropped map[string]int
+	depth   int
+}
+
+func (r *writeRecorder) UpdateWriteQueueDepth(level string, depth int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.depth = depth
+}
+
+func (r *writeRecorder) RecordWriteDropped(level, reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.dropped == nil {
+		r.dropped = make(map[string]int)
+	}
+	r.dropped[reason]++
+}
+
+func (r *writeRecorder) snapshot() (map[string]int, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	dropped := make(map[string]int, len(r.dropped))
+	for reason, n := range r.dropped {
+		dropped[reason] = n
+	}
+	return dropped, r.depth
+}
+
+// newWriteBehindCache creates a KeyDBCache with write-behind backed by miniredis. The
+// mock clock never ticks unless advanced, so only full batches and Close flush.
+func newWriteBehindCache(t *testing.T, wb cache.WriteBehindConfig, opts ...Option) (*KeyDBCache, *miniredis.Miniredis, *clock.Mock) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client, err := NewRedisKeyDbClient(&cache.KeyDBConfig{}, "redis://"+server.Addr())
+	require.NoError(t, err)
+
+	wb.Enabled = true
+	clk := clock.NewMock(time.Now())
+	opts = append([]Option{WithClock(clk)}, opts...)
+	c := NewKeyDBCache(&cache.KeyDBConfig{WriteBehind: wb}, client, opts...).(*KeyDBCache)
+	return c, server, clk
+}
+
+func TestKeyDBCache_WriteBehind_FlushOnClose(t *testing.T) {
+	recorder := &writeRecorder{}
+	c, server, _ := newWriteBehindCache(t, cache.WriteBehindConfig{}, WithMetrics(recorder))
+
+	ttl := models.TTL{Fresh: time.Minute}
+	c.Set("a", []byte("1"), ttl)
+	c.SetNegative("b", []byte("not found"), ttl)
+	c.SetMany([]cache.BatchItem{{Key: "c", Val: []byte("3"), TTL: ttl}})
+
+	assert.False(t, server.Exists("a"), "write should be queued")
+	_, depth := recorder.snapshot()
+	assert.Equal(t, 3, depth)
+
+	require.NoError(t, c.Close())
+
+	for _, key := range []string{"a", "b", "c"} {
+		assert.True(t, server.Exists(key), key)
+	}
+	_, depth = recorder.snapshot()
+	assert.Equal(t, 0, depth)
+
+	// Writes after Close are dropped
+	c.Set("d", []byte("4"), ttl)
+	dropped, _ := recorder.snapshot()
+	assert.Equal(t, 1, dropped["closed"])
+}
+
+func TestKeyDBCache_WriteBehind_FlushesFullBatch(t *testing.T) {
+	c, server, _ := newWriteBehindCache(t, cache.WriteBehindConfig{BatchSize: 2})
+	defer c.Close()
+
+	c.Set("a", []byte("1"), models.TTL{Fresh: time.Minute})
+	c.Set("b", []byte("2"), models.TTL{Fresh: time.Minute})
+
+	assert.Eventually(t, func() bool {
+		return server.Exists("a") && server.Exists("b")
+	}, time.Second, 5*time.Millisecond)
+
+	entry, found := c.Get("a")
+	require.True(t, found)
+	assert.Equal(t, []byte("1"), entry.Data)
+}
+
+func TestKeyDBCache_WriteBehind_SetWithTags(t *testing.T) {
+	c, server, _ := newWriteBehindCache(t, cache.WriteBehindConfig{})
+
+	c.SetWithTags("a", []byte("1"), models.TTL{Fresh: time.Minute}, "block:100")
+	assert.False(t, server.Exists("a"), "write should be queued")
+	assert.False(t, server.Exists(tagKeyPrefix+"block:100"), "tag update should be queued")
+
+	require.NoError(t, c.Close())
+
+	assert.True(t, server.Exists("a"))
+	members, err := server.Members(tagKeyPrefix + "block:100")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, members)
+	assert.Equal(t, time.Minute, server.TTL(tagKeyPrefix+"block:100"))
+}
+
+func TestKeyDBCache_WriteBehind_FlushInterval(t *testing.T) {
+	c, server, clk := newWriteBehindCache(t, cache.WriteBehindConfig{FlushInterval: time.Second})
+	defer c.Close()
+
+	c.Set("a", []byte("1"), models.TTL{Fresh: time.Minute})
+	assert.False(t, server.Exists("a"))
+
+	clk.Advance(time.Second)
+	assert.Eventually(t, func() bool { return server.Exists("a") }, time.Second, 5*time.Millisecond)
+}
+
+func TestKeyDBCache_WriteBehind_Overflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		overflow cache.OverflowPolicy
+		kept     []string
+		lost     string
+	}{
+		{name: "drop oldest", overflow: cache.OverflowDropOldest, kept: []string{"b", "c"}, lost: "a"},
+		{name: "drop newest", overflow: cache.OverflowDropNewest, kept: []string{"a", "b"}, lost: "c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &writeRecorder{}
+			c, server, _ := newWriteBehindCache(t, cache.WriteBehindConfig{QueueSize: 2, Overflow: tt.overflow},
+				WithMetrics(recorder))
+
+			for _, key := range []string{"a", "b", "c"} {
+				c.Set(key, []byte(key), models.TTL{Fresh: time.Minute})
+			}
+			require.NoError(t, c.Close())
+
+			for _, key := range tt.kept {
+				assert.True(t, server.Exists(key), key)
+			}
+			assert.False(t, server.Exists(tt.lost))
+
+			dropped, _ := recorder.snapshot()
+			assert.Equal(t, 1, dropped["queue_full"])
+		})
+	}
+}
+
+func TestKeyDBCache_WriteBehind_DeleteDiscardsQueuedWrite(t *testing.T) {
+	c, server, _ := newWriteBehindCache(t, cache.WriteBehindConfig{})
+
+	ttl := models.TTL{Fresh: time.Minute}
+	c.Set("a", []byte("1"), ttl)
+	c.Set("user:1", []byte("2"), ttl)
+	c.Set("user:2", []byte("3"), ttl)
+	c.Set("b", []byte("4"), ttl)
+	c.SetWithTags("c", []byte("5"), ttl, "block:100")
+
+	c.Delete("a")
+	c.InvalidatePrefix("user:")
+	c.InvalidateTag("block:100")
+	require.NoError(t, c.Close())
+
+	assert.False(t, server.Exists("a"))
+	assert.False(t, server.Exists("user:1"))
+	assert.False(t, server.Exists("user:2"))
+	assert.False(t, server.Exists("c"))
+	assert.True(t, server.Exists("b"))
+}
+
+func TestWriteBehind_DiscardWaitsForInflightBatch(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	write := func(ctx context.Context, batch []pendingWrite) error {
+		close(started)
+		<-release
+		return nil
+	}
+	cfg := cache.WriteBehindConfig{Enabled: true, QueueSize: 10, BatchSize: 1, FlushInterval: time.Minute}
+	w := newWriteBehind(cfg, write, time.Second, clock.NewMock(time.Now()), cache.NoopLogger{}, cache.NoopMetrics{})
+	defer w.close()
+
+	w.enqueue(pendingWrite{key: "a"})
+	<-started
+
+	byKey := func(key string) func(pendingWrite) bool {
+		return func(pw pendingWrite) bool { return pw.key == key }
+	}
+
+	// Deletes of other keys do not wait, and a cancelled delete gives up
+	require.NoError(t, w.discard(context.Background(), byKey("b")))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, w.discard(ctx, byKey("a")), context.Canceled)
+
+	done := make(chan error, 1)
+	go func() { done <- w.discard(context.Background(), byKey("a")) }()
+	select {
+	case <-done:
+		t.Fatal("discard returned while the batch holding its key was in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-done)
+}
+
+func TestKeyDBCache_WriteBehind_InvalidConfig(t *testing.T) {
+	c, server, _ := newWriteBehindCache(t, cache.WriteBehindConfig{BatchSize: -1})
+
+	// The invalid config is logged and writes are sent synchronously
+	assert.Nil(t, c.writer)
+	c.Set("a", []byte("1"), models.TTL{Fresh: time.Minute})
+	assert.True(t, server.Exists("a"))
+}
+
+func TestKeyDBCache_WriteBehind_CircuitOpen(t *testing.T) {
+	recorder := &writeRecorder{}
+	c, server, _ := newWriteBehindCache(t, cache.WriteBehindConfig{}, WithMetrics(recorder))
+	c.breaker = newCircuitBreaker(newBreakerConfig().CircuitBreaker, nil, time.Second, nil, c.clock, c.logger, c.metrics)
+	c.breaker.mu.Lock()
+	c.breaker.open("test")
+	c.breaker.mu.Unlock()
+
+	c.Set("a", []byte("1"), models.TTL{Fresh: time.Minute})
+	require.NoError(t, c.Close())
+
+	assert.False(t, server.Exists("a"))
+	dropped, _ := recorder.snapshot()
+	assert.Equal(t, 1, dropped["circuit_open"])
+}
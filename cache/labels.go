@@ -0,0 +1,29 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/status-im/proxy-common/models"
+)
+
+// RequestLabels identifies the request a cache operation is made for, so metrics
+// can be broken down by cache type, chain, network and RPC method
+type RequestLabels struct {
+	CacheType models.CacheType
+	Chain     string
+	Network   string
+	Method    string
+}
+
+type requestLabelsKey struct{}
+
+// ContextWithRequestLabels returns a copy of ctx carrying labels
+func ContextWithRequestLabels(ctx context.Context, labels RequestLabels) context.Context {
+	return context.WithValue(ctx, requestLabelsKey{}, labels)
+}
+
+// RequestLabelsFromContext returns the labels stored in ctx, or zero labels if there are none
+func RequestLabelsFromContext(ctx context.Context) RequestLabels {
+	labels, _ := ctx.Value(requestLabelsKey{}).(RequestLabels)
+	return labels
+}
@@ -0,0 +1,23 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/status-im/proxy-common/models"
+)
+
+func TestRequestLabelsFromContext(t *testing.T) {
+	labels := RequestLabels{
+		CacheType: models.CacheTypePermanent,
+		Chain:     "ethereum",
+		Network:   "mainnet",
+		Method:    "eth_getBlockByNumber",
+	}
+
+	ctx := ContextWithRequestLabels(context.Background(), labels)
+	assert.Equal(t, labels, RequestLabelsFromContext(ctx))
+	assert.Equal(t, RequestLabels{}, RequestLabelsFromContext(context.Background()))
+}
@@ -0,0 +1,370 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/clock"
+	"github.com/status-im/proxy-common/models"
+)
+
+// ErrLoadAborted is returned to waiters when the load they were waiting on panicked
+var ErrLoadAborted = errors.New("cache load aborted")
+
+// defaultLoadTimeout bounds a shared load, which no longer follows the deadline of the
+// request that started it
+const defaultLoadTimeout = 10 * time.Second
+
+// LoadFunc fetches the value for a key from upstream together with the TTL it should be cached for.
+// Returning a *NegativeError caches the absence of a value instead of failing the load.
+type LoadFunc func(ctx context.Context) ([]byte, models.TTL, error)
+
+// NegativeError reports that upstream has no value for a key, or answered with a
+// deterministic error, and that this outcome may be cached for TTL. Payload is the
+// optional serialized error stored with the negative entry.
+type NegativeError struct {
+	Payload []byte
+	TTL     models.TTL
+	Err     error
+}
+
+func (e *NegativeError) Error() string {
+	if e.Err == nil {
+		return "negative cache result"
+	}
+	return "negative cache result: " + e.Err.Error()
+}
+
+func (e *NegativeError) Unwrap() error {
+	return e.Err
+}
+
+// call represents an in-flight or completed load for a single key
+type call struct {
+	done   chan struct{}
+	result *models.CacheResult
+	err    error
+}
+
+// refreshTask is a pending background refresh of a stale key
+type refreshTask struct {
+	key  string
+	load LoadFunc
+}
+
+// Loader wraps a LevelAwareCache and collapses concurrent misses for the same key
+// into a single upstream load whose result is shared with every waiter
+type Loader struct {
+	cache   cache.LevelAwareCache
+	logger  cache.Logger
+	metrics cache.MetricsRecorder
+	clock   clock.Clock
+
+	loadTimeout time.Duration
+
+	mu    sync.Mutex
+	calls map[string]*call
+
+	// stale-while-revalidate state, only set when enabled
+	revalidate   *cache.RevalidateConfig
+	refreshQueue chan refreshTask
+	refreshing   map[string]struct{}
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+// Option is a functional option for configuring Loader
+type Option func(*Loader)
+
+// WithLogger sets the logger for Loader
+func WithLogger(logger cache.Logger) Option {
+	return func(l *Loader) {
+		l.logger = logger
+	}
+}
+
+// WithMetrics sets the metrics recorder for Loader
+func WithMetrics(metrics cache.MetricsRecorder) Option {
+	return func(l *Loader) {
+		l.metrics = metrics
+	}
+}
+
+// WithClock sets the clock used to check freshness and timestamp loaded entries
+func WithClock(c clock.Clock) Option {
+	return func(l *Loader) {
+		l.clock = c
+	}
+}
+
+// WithLoadTimeout bounds each shared load, 10 seconds by default. Non-positive values
+// are ignored.
+func WithLoadTimeout(d time.Duration) Option {
+	return func(l *Loader) {
+		if d > 0 {
+			l.loadTimeout = d
+		}
+	}
+}
+
+// WithStaleWhileRevalidate enables serving stale entries immediately while
+// refreshing them in the background. It has no effect if cfg.Enabled is false. cfg is
+// copied, and an invalid cfg is logged and leaves stale-while-revalidate disabled.
+func WithStaleWhileRevalidate(cfg *cache.RevalidateConfig) Option {
+	return func(l *Loader) {
+		if cfg == nil || !cfg.Enabled {
+			return
+		}
+		revalidate := *cfg
+		revalidate.ApplyDefaults()
+		l.revalidate = &revalidate
+	}
+}
+
+// New creates a new Loader on top of the given cache
+func New(c cache.LevelAwareCache, opts ...Option) *Loader {
+	l := &Loader{
+		cache:   c,
+		logger:  cache.NoopLogger{},
+		metrics: cache.NoopMetrics{},
+		clock:   clock.Real{},
+		calls:   make(map[string]*call),
+
+		loadTimeout: defaultLoadTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	if l.revalidate != nil {
+		if err := l.revalidate.Validate(); err != nil {
+			l.logger.Error("Invalid stale-while-revalidate config, reloading stale entries synchronously", "error", err)
+			l.revalidate = nil
+		} else {
+			l.startRefreshWorkers()
+		}
+	}
+
+	return l
+}
+
+// GetOrLoad returns the cached value for key or, on a miss, calls load and caches its result.
+// Concurrent misses for the same key share a single load call. The returned result has
+// Found set to false and Level set to MISS when the value came from load. When load
+// returns a *NegativeError, or a negative entry is cached, Found is false, the result's
+// IsNegative reports true and the error is nil. Callers must not
+// modify the returned entry data since it is shared between all waiters.
+//
+// The shared load runs on a context detached from the caller that started it, keeping
+// its values but not its cancellation, and bounded by WithLoadTimeout. Every caller,
+// including the first, stops waiting when its own context is done.
+//
+// A stale entry is returned immediately and refreshed in the background when
+// stale-while-revalidate is enabled; otherwise it is reloaded synchronously and only
+// served if that load fails (stale-if-error). Background refreshes call load with their
+// own context, so load must not rely on the request context it was created under.
+func (l *Loader) GetOrLoad(ctx context.Context, key string, load LoadFunc) (*models.CacheResult, error) {
+	result := l.cache.GetWithLevel(key)
+	if cached(result) && result.Entry.IsFreshAt(l.clock.Now()) {
+		return result, nil
+	}
+
+	if cached(result) && l.revalidate != nil {
+		l.scheduleRefresh(key, load)
+		return result, nil
+	}
+
+	loaded, err := l.loadShared(ctx, key, load)
+	if err != nil && cached(result) {
+		l.logger.Warn("Serving stale cache entry after load failure", "key", key, "error", err)
+		return result, nil
+	}
+
+	return loaded, err
+}
+
+// Close stops the background refresh workers, waiting for in-progress refreshes to finish
+func (l *Loader) Close() {
+	if l.cancel == nil {
+		return
+	}
+
+	l.cancel()
+	l.wg.Wait()
+}
+
+// loadShared starts a load for key unless a load for the same key is already in
+// flight, then waits for and returns that load's result
+func (l *Loader) loadShared(ctx context.Context, key string, load LoadFunc) (*models.CacheResult, error) {
+	l.mu.Lock()
+	if c, ok := l.calls[key]; ok {
+		l.mu.Unlock()
+		cache.OptionalMetrics[cache.CoalescingMetricsRecorder](l.metrics).RecordCacheCoalesced("load")
+		return l.wait(ctx, c)
+	}
+
+	c := &call{done: make(chan struct{}), err: ErrLoadAborted}
+	l.calls[key] = c
+	l.mu.Unlock()
+
+	go l.run(context.WithoutCancel(ctx), key, load, c)
+
+	return l.wait(ctx, c)
+}
+
+// run performs the shared load for c. A load that completed between the caller's miss
+// and the registration of c has already stored its result, so the cache is checked
+// again first. A panicking load is logged and reported to waiters as ErrLoadAborted.
+func (l *Loader) run(ctx context.Context, key string, load LoadFunc, c *call) {
+	defer func() {
+		if r := recover(); r != nil {
+			l.logger.Error("Cache load panicked", "key", key, "panic", r)
+		}
+
+		l.mu.Lock()
+		delete(l.calls, key)
+		l.mu.Unlock()
+		close(c.done)
+	}()
+
+	if result := l.cache.GetWithLevel(key); cached(result) && result.Entry.IsFreshAt(l.clock.Now()) {
+		c.result, c.err = result, nil
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, l.loadTimeout)
+	defer cancel()
+
+	c.result, c.err = l.load(ctx, key, load)
+}
+
+// cached reports whether result holds a positive or negative entry read from the cache
+func cached(result *models.CacheResult) bool {
+	return result.Found || result.IsNegative()
+}
+
+// wait blocks until the in-flight call completes or the caller's context is done
+func (l *Loader) wait(ctx context.Context, c *call) (*models.CacheResult, error) {
+	select {
+	case <-c.done:
+		return c.result, c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// load calls the load function and stores a successful or negative result in the cache
+func (l *Loader) load(ctx context.Context, key string, load LoadFunc) (*models.CacheResult, error) {
+	data, ttl, err := load(ctx)
+	var negErr *NegativeError
+	if errors.As(err, &negErr) {
+		l.setNegative(key, negErr)
+		return &models.CacheResult{
+			Entry: models.NewNegativeCacheEntry(negErr.Payload, negErr.TTL, l.clock.Now()),
+			Found: false,
+			Level: models.CacheLevelMiss,
+		}, nil
+	}
+	if err != nil {
+		l.logger.Debug("Cache load failed", "key", key, "error", err)
+		return nil, err
+	}
+
+	l.cache.Set(key, data, ttl)
+
+	return &models.CacheResult{
+		Entry: models.NewCacheEntry(data, ttl, l.clock.Now()),
+		Found: false,
+		Level: models.CacheLevelMiss,
+	}, nil
+}
+
+// startRefreshWorkers starts the bounded pool of background refresh workers
+func (l *Loader) startRefreshWorkers() {
+	l.ctx, l.cancel = context.WithCancel(context.Background())
+	l.refreshQueue = make(chan refreshTask, l.revalidate.QueueSize)
+	l.refreshing = make(map[string]struct{})
+
+	for i := 0; i < l.revalidate.Workers; i++ {
+		l.wg.Add(1)
+		go func() {
+			defer l.wg.Done()
+			for {
+				select {
+				case task := <-l.refreshQueue:
+					l.refresh(task)
+				case <-l.ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+}
+
+// scheduleRefresh queues a background refresh for key unless one is already pending
+func (l *Loader) scheduleRefresh(key string, load LoadFunc) {
+	if l.ctx.Err() != nil {
+		return
+	}
+
+	l.mu.Lock()
+	if _, ok := l.refreshing[key]; ok {
+		l.mu.Unlock()
+		cache.OptionalMetrics[cache.CoalescingMetricsRecorder](l.metrics).RecordCacheCoalesced("refresh")
+		return
+	}
+	l.refreshing[key] = struct{}{}
+	l.mu.Unlock()
+
+	select {
+	case l.refreshQueue <- refreshTask{key: key, load: load}:
+	default:
+		l.finishRefresh(key)
+		l.logger.Warn("Cache refresh queue full, dropping refresh", "key", key)
+		l.metrics.RecordCacheError("multi", "refresh_queue_full")
+	}
+}
+
+// refresh reloads a stale key and writes the result to all cache levels
+func (l *Loader) refresh(task refreshTask) {
+	defer l.finishRefresh(task.key)
+
+	ctx, cancel := context.WithTimeout(l.ctx, l.revalidate.Timeout)
+	defer cancel()
+
+	data, ttl, err := task.load(ctx)
+	var negErr *NegativeError
+	if errors.As(err, &negErr) {
+		l.setNegative(task.key, negErr)
+		return
+	}
+	if err != nil {
+		l.logger.Warn("Background cache refresh failed", "key", task.key, "error", err)
+		l.metrics.RecordCacheError("multi", "refresh")
+		return
+	}
+
+	l.cache.Set(task.key, data, ttl)
+}
+
+// setNegative caches a negative result if the cache supports negative entries, and
+// otherwise drops the key so an older value is not served
+func (l *Loader) setNegative(key string, negErr *NegativeError) {
+	if nc, ok := l.cache.(cache.NegativeCache); ok {
+		nc.SetNegative(key, negErr.Payload, negErr.TTL)
+		return
+	}
+	l.cache.Delete(key)
+}
+
+// finishRefresh marks key as no longer pending refresh
+func (l *Loader) finishRefresh(key string) {
+	l.mu.Lock()
+	delete(l.refreshing, key)
+	l.mu.Unlock()
+}
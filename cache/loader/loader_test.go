@@ -0,0 +1,400 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/cache/l1"
+	"github.com/status-im/proxy-common/cache/mock"
+	"github.com/status-im/proxy-common/cache/multi"
+	"github.com/status-im/proxy-common/models"
+)
+
+// countingMetrics counts coalesced waiters
+type countingMetrics struct {
+	cache.NoopMetrics
+	coalesced atomic.Int64
+}
+
+func (m *countingMetrics) RecordCacheCoalesced(operation string) {
+	m.coalesced.Add(1)
+}
+
+func newStaleEntry() *models.CacheEntry {
+	now := time.Now().Unix()
+	return &models.CacheEntry{
+		Data:      []byte("stale"),
+		CreatedAt: now - 200,
+		StaleAt:   now - 50, // stale but not expired
+		ExpiresAt: now + 100,
+	}
+}
+
+func newTestMultiCache(t *testing.T) cache.LevelAwareCache {
+	l1Cache, err := l1.NewBigCache(&cache.BigCacheConfig{Enabled: true, Size: 10})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l1Cache.(*l1.BigCache).Close() })
+
+	return multi.NewMultiCache([]cache.Cache{l1Cache}, true)
+}
+
+func TestLoader_GetOrLoad_Hit(t *testing.T) {
+	mc := newTestMultiCache(t)
+	mc.Set("test-key", []byte("cached"), models.TTL{Fresh: time.Minute})
+
+	l := New(mc)
+
+	result, err := l.GetOrLoad(context.Background(), "test-key", func(ctx context.Context) ([]byte, models.TTL, error) {
+		t.Fatal("load should not be called on hit")
+		return nil, models.TTL{}, nil
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.Found)
+	assert.Equal(t, models.CacheLevelL1, result.Level)
+	assert.Equal(t, []byte("cached"), result.Entry.Data)
+}
+
+func TestLoader_GetOrLoad_MissStoresResult(t *testing.T) {
+	mc := newTestMultiCache(t)
+	l := New(mc)
+
+	result, err := l.GetOrLoad(context.Background(), "test-key", func(ctx context.Context) ([]byte, models.TTL, error) {
+		return []byte("loaded"), models.TTL{Fresh: time.Minute, Stale: time.Minute}, nil
+	})
+
+	require.NoError(t, err)
+	assert.False(t, result.Found)
+	assert.Equal(t, models.CacheLevelMiss, result.Level)
+	assert.Equal(t, []byte("loaded"), result.Entry.Data)
+	assert.True(t, result.Entry.IsFresh())
+
+	entry, found := mc.Get("test-key")
+	assert.True(t, found)
+	assert.Equal(t, []byte("loaded"), entry.Data)
+}
+
+func TestLoader_GetOrLoad_LoadError(t *testing.T) {
+	mc := newTestMultiCache(t)
+	l := New(mc)
+
+	loadErr := errors.New("upstream failed")
+	result, err := l.GetOrLoad(context.Background(), "test-key", func(ctx context.Context) ([]byte, models.TTL, error) {
+		return nil, models.TTL{}, loadErr
+	})
+
+	assert.ErrorIs(t, err, loadErr)
+	assert.Nil(t, result)
+
+	_, found := mc.Get("test-key")
+	assert.False(t, found)
+}
+
+func TestLoader_GetOrLoad_NegativeResult(t *testing.T) {
+	mc := newTestMultiCache(t)
+	l := New(mc)
+
+	notFound := errors.New("transaction not found")
+	loads := 0
+	load := func(ctx context.Context) ([]byte, models.TTL, error) {
+		loads++
+		return nil, models.TTL{}, &NegativeError{Payload: []byte("not found"), TTL: models.TTL{Fresh: time.Minute}, Err: notFound}
+	}
+
+	result, err := l.GetOrLoad(context.Background(), "test-key", load)
+
+	require.NoError(t, err)
+	assert.False(t, result.Found)
+	assert.True(t, result.IsNegative())
+	assert.Equal(t, []byte("not found"), result.Entry.Error)
+
+	// The negative entry is served from the cache without calling load again
+	result, err = l.GetOrLoad(context.Background(), "test-key", load)
+
+	require.NoError(t, err)
+	assert.False(t, result.Found)
+	assert.Equal(t, models.CacheLevelL1, result.Level)
+	assert.True(t, result.IsNegative())
+	assert.Equal(t, 1, loads)
+	assert.ErrorIs(t, &NegativeError{Err: notFound}, notFound)
+}
+
+func TestLoader_GetOrLoad_CoalescesConcurrentMisses(t *testing.T) {
+	mc := newTestMultiCache(t)
+	metrics := &countingMetrics{}
+	l := New(mc, WithMetrics(metrics))
+
+	const waiters = 10
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	load := func(ctx context.Context) ([]byte, models.TTL, error) {
+		calls.Add(1)
+		<-release
+		return []byte("loaded"), models.TTL{Fresh: time.Minute}, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]*models.CacheResult, waiters)
+	errs := make([]error, waiters)
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = l.GetOrLoad(context.Background(), "test-key", load)
+		}(i)
+	}
+
+	assert.Eventually(t, func() bool {
+		return metrics.coalesced.Load() == waiters-1
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for i := 0; i < waiters; i++ {
+		require.NoError(t, errs[i])
+		assert.Equal(t, []byte("loaded"), results[i].Entry.Data)
+	}
+}
+
+func TestLoader_GetOrLoad_WaiterContextCancelled(t *testing.T) {
+	mc := newTestMultiCache(t)
+	l := New(mc)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	go func() {
+		_, _ = l.GetOrLoad(context.Background(), "test-key", func(ctx context.Context) ([]byte, models.TTL, error) {
+			close(started)
+			<-release
+			return []byte("loaded"), models.TTL{Fresh: time.Minute}, nil
+		})
+	}()
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := l.GetOrLoad(ctx, "test-key", func(ctx context.Context) ([]byte, models.TTL, error) {
+		t.Fatal("load should not be called while another load is in flight")
+		return nil, models.TTL{}, nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, result)
+}
+
+func TestLoader_GetOrLoad_LeaderContextCancelled(t *testing.T) {
+	mc := newTestMultiCache(t)
+	l := New(mc)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var loadErr atomic.Value
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderDone := make(chan error, 1)
+	go func() {
+		_, err := l.GetOrLoad(leaderCtx, "test-key", func(ctx context.Context) ([]byte, models.TTL, error) {
+			close(started)
+			<-release
+			if err := ctx.Err(); err != nil {
+				loadErr.Store(err)
+			}
+			return []byte("loaded"), models.TTL{Fresh: time.Minute}, nil
+		})
+		leaderDone <- err
+	}()
+	<-started
+
+	waiterDone := make(chan *models.CacheResult, 1)
+	go func() {
+		result, err := l.GetOrLoad(context.Background(), "test-key", func(ctx context.Context) ([]byte, models.TTL, error) {
+			t.Error("load should not be called while another load is in flight")
+			return nil, models.TTL{}, nil
+		})
+		assert.NoError(t, err)
+		waiterDone <- result
+	}()
+
+	// The leader stops waiting, but the shared load carries on for the waiter
+	cancelLeader()
+	assert.ErrorIs(t, <-leaderDone, context.Canceled)
+
+	close(release)
+	result := <-waiterDone
+	require.NotNil(t, result)
+	assert.Equal(t, []byte("loaded"), result.Entry.Data)
+	assert.Nil(t, loadErr.Load())
+}
+
+func TestLoader_GetOrLoad_RechecksCacheBeforeLoading(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mock.NewMockLevelAwareCache(ctrl)
+	l := New(mockCache)
+
+	fresh := &models.CacheResult{
+		Entry: models.NewCacheEntry([]byte("cached"), models.TTL{Fresh: time.Minute}, time.Now()),
+		Found: true,
+		Level: models.CacheLevelL1,
+	}
+	gomock.InOrder(
+		mockCache.EXPECT().GetWithLevel("test-key").Return(&models.CacheResult{Level: models.CacheLevelMiss}),
+		mockCache.EXPECT().GetWithLevel("test-key").Return(fresh),
+	)
+
+	result, err := l.GetOrLoad(context.Background(), "test-key", func(ctx context.Context) ([]byte, models.TTL, error) {
+		t.Error("load should not be called when another load stored the key")
+		return nil, models.TTL{}, nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, fresh, result)
+}
+
+func TestLoader_GetOrLoad_StaleReloadedSynchronously(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mock.NewMockLevelAwareCache(ctrl)
+	l := New(mockCache)
+
+	ttl := models.TTL{Fresh: time.Minute}
+	mockCache.EXPECT().GetWithLevel("test-key").Return(&models.CacheResult{
+		Entry: newStaleEntry(),
+		Found: true,
+		Level: models.CacheLevelL2,
+	}).Times(2)
+	mockCache.EXPECT().Set("test-key", []byte("loaded"), ttl).Times(1)
+
+	result, err := l.GetOrLoad(context.Background(), "test-key", func(ctx context.Context) ([]byte, models.TTL, error) {
+		return []byte("loaded"), ttl, nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.CacheLevelMiss, result.Level)
+	assert.Equal(t, []byte("loaded"), result.Entry.Data)
+}
+
+func TestLoader_GetOrLoad_StaleIfError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mock.NewMockLevelAwareCache(ctrl)
+	l := New(mockCache)
+
+	stale := &models.CacheResult{Entry: newStaleEntry(), Found: true, Level: models.CacheLevelL2}
+	mockCache.EXPECT().GetWithLevel("test-key").Return(stale).Times(2)
+
+	result, err := l.GetOrLoad(context.Background(), "test-key", func(ctx context.Context) ([]byte, models.TTL, error) {
+		return nil, models.TTL{}, errors.New("upstream failed")
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, stale, result)
+}
+
+func TestLoader_GetOrLoad_StaleWhileRevalidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mock.NewMockLevelAwareCache(ctrl)
+	metrics := &countingMetrics{}
+	l := New(mockCache,
+		WithMetrics(metrics),
+		WithStaleWhileRevalidate(&cache.RevalidateConfig{Enabled: true, Workers: 2}),
+	)
+	defer l.Close()
+
+	stale := &models.CacheResult{Entry: newStaleEntry(), Found: true, Level: models.CacheLevelL2}
+	ttl := models.TTL{Fresh: time.Minute}
+	release := make(chan struct{})
+	refreshed := make(chan struct{})
+	var calls atomic.Int32
+
+	mockCache.EXPECT().GetWithLevel("test-key").Return(stale).Times(3)
+	mockCache.EXPECT().Set("test-key", []byte("refreshed"), ttl).Do(func(string, []byte, models.TTL) {
+		close(refreshed)
+	}).Times(1)
+
+	load := func(ctx context.Context) ([]byte, models.TTL, error) {
+		calls.Add(1)
+		<-release
+		return []byte("refreshed"), ttl, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		result, err := l.GetOrLoad(context.Background(), "test-key", load)
+		require.NoError(t, err)
+		assert.Equal(t, stale, result)
+	}
+
+	assert.Equal(t, int64(2), metrics.coalesced.Load())
+	close(release)
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("background refresh did not complete")
+	}
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestLoader_GetOrLoad_StaleWhileRevalidateQueueFull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mock.NewMockLevelAwareCache(ctrl)
+	l := New(mockCache, WithStaleWhileRevalidate(&cache.RevalidateConfig{Enabled: true, Workers: 1, QueueSize: 1}))
+
+	// Stop the workers so nothing drains the queue
+	l.cancel()
+	l.wg.Wait()
+	l.ctx = context.Background()
+
+	stale := &models.CacheResult{Entry: newStaleEntry(), Found: true, Level: models.CacheLevelL2}
+	mockCache.EXPECT().GetWithLevel(gomock.Any()).Return(stale).Times(2)
+
+	load := func(ctx context.Context) ([]byte, models.TTL, error) {
+		return []byte("refreshed"), models.TTL{Fresh: time.Minute}, nil
+	}
+
+	_, err := l.GetOrLoad(context.Background(), "key-1", load)
+	require.NoError(t, err)
+	_, err = l.GetOrLoad(context.Background(), "key-2", load)
+	require.NoError(t, err)
+
+	assert.Len(t, l.refreshQueue, 1)
+	assert.Contains(t, l.refreshing, "key-1")
+	assert.NotContains(t, l.refreshing, "key-2")
+}
+
+func TestLoader_WithStaleWhileRevalidate_Config(t *testing.T) {
+	mc := newTestMultiCache(t)
+
+	cfg := &cache.RevalidateConfig{Enabled: true}
+	l := New(mc, WithStaleWhileRevalidate(cfg))
+	defer l.Close()
+
+	assert.Equal(t, cache.RevalidateConfig{Enabled: true}, *cfg, "caller's config must not be modified")
+	require.NotNil(t, l.revalidate)
+	assert.Equal(t, 4, l.revalidate.Workers)
+
+	invalid := New(mc, WithStaleWhileRevalidate(&cache.RevalidateConfig{Enabled: true, Workers: -1}))
+	defer invalid.Close()
+	assert.Nil(t, invalid.revalidate, "invalid config must disable stale-while-revalidate")
+}
@@ -5,8 +5,20 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/status-im/proxy-common/cache"
 )
 
+// Ensure CacheMetrics implements cache.MetricsRecorder and the optional recorders
+var _ cache.MetricsRecorder = (*CacheMetrics)(nil)
+var _ cache.CoalescingMetricsRecorder = (*CacheMetrics)(nil)
+var _ cache.CompressionMetricsRecorder = (*CacheMetrics)(nil)
+var _ cache.TTLMetricsRecorder = (*CacheMetrics)(nil)
+var _ cache.L1StatsMetricsRecorder = (*CacheMetrics)(nil)
+var _ cache.NegativeCacheMetricsRecorder = (*CacheMetrics)(nil)
+var _ cache.CircuitBreakerMetricsRecorder = (*CacheMetrics)(nil)
+var _ cache.WriteBehindMetricsRecorder = (*CacheMetrics)(nil)
+
 const (
 	DefaultNamespace = "proxy"
 	DefaultSubsystem = "cache"
@@ -16,6 +28,10 @@ const (
 type Config struct {
 	Namespace string // e.g., "nft_proxy", "eth_rpc_proxy"
 	Subsystem string // default: "cache"
+
+	// Registerer the metrics are registered with, default: prometheus.DefaultRegisterer.
+	// Use a separate registry per CacheMetrics sharing a namespace and subsystem.
+	Registerer prometheus.Registerer
 }
 
 // CacheMetrics holds all cache-related Prometheus metrics
@@ -28,11 +44,22 @@ type CacheMetrics struct {
 	Requests     *prometheus.CounterVec
 	Hits         *prometheus.CounterVec
 	Misses       *prometheus.CounterVec
+	NegativeHits *prometheus.CounterVec
 	Sets         *prometheus.CounterVec
 	Evictions    *prometheus.CounterVec
 	Errors       *prometheus.CounterVec
 	BytesRead    *prometheus.CounterVec
 	BytesWritten *prometheus.CounterVec
+	Coalesced    *prometheus.CounterVec
+	Collisions   *prometheus.CounterVec
+	DeleteHits   *prometheus.CounterVec
+
+	EvictionsByReason          *prometheus.CounterVec
+	CompressionRawBytes        *prometheus.CounterVec
+	CompressionCompressedBytes *prometheus.CounterVec
+	TTLAdjusted                *prometheus.CounterVec
+	CircuitBreakerOpened       *prometheus.CounterVec
+	WriteDropped               *prometheus.CounterVec
 
 	// Histogram metrics
 	OperationDuration *prometheus.HistogramVec
@@ -42,6 +69,9 @@ type CacheMetrics struct {
 	Keys     *prometheus.GaugeVec
 	Capacity *prometheus.GaugeVec
 	Used     *prometheus.GaugeVec
+
+	CircuitBreakerState *prometheus.GaugeVec
+	WriteQueueDepth     *prometheus.GaugeVec
 }
 
 // New creates a new CacheMetrics instance with the given configuration
@@ -52,6 +82,10 @@ func New(cfg Config) *CacheMetrics {
 	if cfg.Subsystem == "" {
 		cfg.Subsystem = DefaultSubsystem
 	}
+	if cfg.Registerer == nil {
+		cfg.Registerer = prometheus.DefaultRegisterer
+	}
+	factory := promauto.With(cfg.Registerer)
 
 	m := &CacheMetrics{
 		namespace: cfg.Namespace,
@@ -59,7 +93,7 @@ func New(cfg Config) *CacheMetrics {
 	}
 
 	// Initialize counter metrics
-	m.Requests = promauto.NewCounterVec(
+	m.Requests = factory.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: cfg.Namespace,
 			Subsystem: cfg.Subsystem,
@@ -69,7 +103,7 @@ func New(cfg Config) *CacheMetrics {
 		[]string{"cache_type", "level", "network", "rpc_method"},
 	)
 
-	m.Hits = promauto.NewCounterVec(
+	m.Hits = factory.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: cfg.Namespace,
 			Subsystem: cfg.Subsystem,
@@ -79,7 +113,7 @@ func New(cfg Config) *CacheMetrics {
 		[]string{"cache_type", "level", "network", "rpc_method"},
 	)
 
-	m.Misses = promauto.NewCounterVec(
+	m.Misses = factory.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: cfg.Namespace,
 			Subsystem: cfg.Subsystem,
@@ -89,7 +123,17 @@ func New(cfg Config) *CacheMetrics {
 		[]string{"cache_type", "level", "network", "rpc_method"},
 	)
 
-	m.Sets = promauto.NewCounterVec(
+	m.NegativeHits = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: cfg.Namespace,
+			Subsystem: cfg.Subsystem,
+			Name:      "negative_hits_total",
+			Help:      "Total number of hits on negative (not found or error) entries",
+		},
+		[]string{"cache_type", "level", "network", "rpc_method"},
+	)
+
+	m.Sets = factory.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: cfg.Namespace,
 			Subsystem: cfg.Subsystem,
@@ -99,7 +143,7 @@ func New(cfg Config) *CacheMetrics {
 		[]string{"level", "cache_type", "network"},
 	)
 
-	m.Evictions = promauto.NewCounterVec(
+	m.Evictions = factory.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: cfg.Namespace,
 			Subsystem: cfg.Subsystem,
@@ -109,7 +153,37 @@ func New(cfg Config) *CacheMetrics {
 		[]string{"level", "cache_type", "network"},
 	)
 
-	m.Errors = promauto.NewCounterVec(
+	m.EvictionsByReason = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: cfg.Namespace,
+			Subsystem: cfg.Subsystem,
+			Name:      "evictions_by_reason_total",
+			Help:      "Entries that left the cache by reason",
+		},
+		[]string{"level", "reason"}, // reason: expired|no_space|deleted
+	)
+
+	m.Collisions = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: cfg.Namespace,
+			Subsystem: cfg.Subsystem,
+			Name:      "collisions_total",
+			Help:      "Total number of key hash collisions",
+		},
+		[]string{"level"}, // only "l1"
+	)
+
+	m.DeleteHits = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: cfg.Namespace,
+			Subsystem: cfg.Subsystem,
+			Name:      "delete_hits_total",
+			Help:      "Total number of deletes that removed an entry",
+		},
+		[]string{"level"}, // only "l1"
+	)
+
+	m.Errors = factory.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: cfg.Namespace,
 			Subsystem: cfg.Subsystem,
@@ -119,7 +193,7 @@ func New(cfg Config) *CacheMetrics {
 		[]string{"level", "kind"},
 	)
 
-	m.BytesRead = promauto.NewCounterVec(
+	m.BytesRead = factory.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: cfg.Namespace,
 			Subsystem: cfg.Subsystem,
@@ -129,7 +203,7 @@ func New(cfg Config) *CacheMetrics {
 		[]string{"level", "cache_type", "network"},
 	)
 
-	m.BytesWritten = promauto.NewCounterVec(
+	m.BytesWritten = factory.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: cfg.Namespace,
 			Subsystem: cfg.Subsystem,
@@ -139,8 +213,48 @@ func New(cfg Config) *CacheMetrics {
 		[]string{"level", "cache_type", "network"},
 	)
 
+	m.Coalesced = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: cfg.Namespace,
+			Subsystem: cfg.Subsystem,
+			Name:      "coalesced_total",
+			Help:      "Requests that waited on an in-flight operation for the same key",
+		},
+		[]string{"operation"}, // operation: load|refresh
+	)
+
+	m.CompressionRawBytes = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: cfg.Namespace,
+			Subsystem: cfg.Subsystem,
+			Name:      "compression_raw_bytes_total",
+			Help:      "Bytes of cache values before compression",
+		},
+		[]string{"level", "algorithm"},
+	)
+
+	m.CompressionCompressedBytes = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: cfg.Namespace,
+			Subsystem: cfg.Subsystem,
+			Name:      "compression_compressed_bytes_total",
+			Help:      "Bytes of cache values after compression",
+		},
+		[]string{"level", "algorithm"},
+	)
+
+	m.TTLAdjusted = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: cfg.Namespace,
+			Subsystem: cfg.Subsystem,
+			Name:      "ttl_adjusted_total",
+			Help:      "Writes whose TTL was defaulted or clamped by the level's TTL limits",
+		},
+		[]string{"level", "reason"}, // reason: default|clamped
+	)
+
 	// Initialize histogram metrics
-	m.OperationDuration = promauto.NewHistogramVec(
+	m.OperationDuration = factory.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: cfg.Namespace,
 			Subsystem: cfg.Subsystem,
@@ -151,7 +265,7 @@ func New(cfg Config) *CacheMetrics {
 		[]string{"operation", "level"}, // operation: get|set, level: l1|l2|multi
 	)
 
-	m.ItemAge = promauto.NewHistogramVec(
+	m.ItemAge = factory.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: cfg.Namespace,
 			Subsystem: cfg.Subsystem,
@@ -163,7 +277,7 @@ func New(cfg Config) *CacheMetrics {
 	)
 
 	// Initialize gauge metrics
-	m.Keys = promauto.NewGaugeVec(
+	m.Keys = factory.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: cfg.Namespace,
 			Subsystem: cfg.Subsystem,
@@ -173,7 +287,7 @@ func New(cfg Config) *CacheMetrics {
 		[]string{"level"},
 	)
 
-	m.Capacity = promauto.NewGaugeVec(
+	m.Capacity = factory.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: cfg.Namespace,
 			Subsystem: cfg.Subsystem,
@@ -183,7 +297,7 @@ func New(cfg Config) *CacheMetrics {
 		[]string{"level"}, // only "l1"
 	)
 
-	m.Used = promauto.NewGaugeVec(
+	m.Used = factory.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: cfg.Namespace,
 			Subsystem: cfg.Subsystem,
@@ -193,6 +307,46 @@ func New(cfg Config) *CacheMetrics {
 		[]string{"level"}, // only "l1"
 	)
 
+	m.CircuitBreakerState = factory.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: cfg.Namespace,
+			Subsystem: cfg.Subsystem,
+			Name:      "circuit_breaker_state",
+			Help:      "Circuit breaker state: 0 closed, 1 half-open, 2 open",
+		},
+		[]string{"level"},
+	)
+
+	m.CircuitBreakerOpened = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: cfg.Namespace,
+			Subsystem: cfg.Subsystem,
+			Name:      "circuit_breaker_opened_total",
+			Help:      "Number of times the circuit breaker opened",
+		},
+		[]string{"level"},
+	)
+
+	m.WriteQueueDepth = factory.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: cfg.Namespace,
+			Subsystem: cfg.Subsystem,
+			Name:      "write_queue_depth",
+			Help:      "Number of writes waiting in the write-behind queue",
+		},
+		[]string{"level"},
+	)
+
+	m.WriteDropped = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: cfg.Namespace,
+			Subsystem: cfg.Subsystem,
+			Name:      "write_dropped_total",
+			Help:      "Writes dropped by the write-behind queue by reason",
+		},
+		[]string{"level", "reason"},
+	)
+
 	return m
 }
 
@@ -208,7 +362,12 @@ func (m *CacheMetrics) InitializeAllowedMethods(methods []string) {
 
 // normalizeRPCMethod returns the method name if it's in the whitelist, otherwise "other"
 func (m *CacheMetrics) normalizeRPCMethod(method string) string {
-	if m.allowedMethods != nil && m.allowedMethods[method] {
+	return normalizeRPCMethod(m.allowedMethods, method)
+}
+
+// normalizeRPCMethod returns method if allowed contains it, otherwise "other"
+func normalizeRPCMethod(allowed map[string]bool, method string) string {
+	if allowed[method] {
 		return method
 	}
 	return "other"
@@ -248,6 +407,16 @@ func (m *CacheMetrics) RecordCacheMiss(cacheType, chain, network, rpcMethod stri
 	m.Misses.WithLabelValues(cacheType, "miss", normalizedNetwork, normalizedMethod).Inc()
 }
 
+// RecordCacheNegativeHit records a hit on a negative entry. It counts as a request but
+// not as a hit, since the caller still has no value to serve.
+func (m *CacheMetrics) RecordCacheNegativeHit(cacheType, level, chain, network, rpcMethod string) {
+	normalizedNetwork := normalizeNetwork(chain, network)
+	normalizedMethod := m.normalizeRPCMethod(rpcMethod)
+
+	m.Requests.WithLabelValues(cacheType, level, normalizedNetwork, normalizedMethod).Inc()
+	m.NegativeHits.WithLabelValues(cacheType, level, normalizedNetwork, normalizedMethod).Inc()
+}
+
 // RecordCacheSet records a cache set operation with size tracking
 func (m *CacheMetrics) RecordCacheSet(level, cacheType, chain, network string, dataSize int) {
 	normalizedNetwork := normalizeNetwork(chain, network)
@@ -264,6 +433,21 @@ func (m *CacheMetrics) RecordCacheEviction(level, cacheType, chain, network stri
 	m.Evictions.WithLabelValues(level, cacheType, normalizedNetwork).Inc()
 }
 
+// RecordCacheEvictionReason records an entry leaving the cache for the given reason
+func (m *CacheMetrics) RecordCacheEvictionReason(level, reason string) {
+	m.EvictionsByReason.WithLabelValues(level, reason).Inc()
+}
+
+// RecordL1CacheStats records key collisions and successful deletes since the previous call
+func (m *CacheMetrics) RecordL1CacheStats(collisions, deleteHits int64) {
+	if collisions > 0 {
+		m.Collisions.WithLabelValues("l1").Add(float64(collisions))
+	}
+	if deleteHits > 0 {
+		m.DeleteHits.WithLabelValues("l1").Add(float64(deleteHits))
+	}
+}
+
 // RecordCacheError records a cache error
 func (m *CacheMetrics) RecordCacheError(level, kind string) {
 	m.Errors.WithLabelValues(level, kind).Inc()
@@ -277,6 +461,22 @@ func (m *CacheMetrics) RecordCacheBytesRead(level, cacheType, chain, network str
 	}
 }
 
+// RecordCacheCoalesced records a request that was collapsed into an in-flight operation
+func (m *CacheMetrics) RecordCacheCoalesced(operation string) {
+	m.Coalesced.WithLabelValues(operation).Inc()
+}
+
+// RecordCacheCompression records the size of a value before and after compression
+func (m *CacheMetrics) RecordCacheCompression(level, algorithm string, rawBytes, compressedBytes int) {
+	m.CompressionRawBytes.WithLabelValues(level, algorithm).Add(float64(rawBytes))
+	m.CompressionCompressedBytes.WithLabelValues(level, algorithm).Add(float64(compressedBytes))
+}
+
+// RecordCacheTTLAdjusted records a write whose TTL was defaulted or clamped
+func (m *CacheMetrics) RecordCacheTTLAdjusted(level, reason string) {
+	m.TTLAdjusted.WithLabelValues(level, reason).Inc()
+}
+
 // UpdateL1CacheCapacity updates L1 cache capacity metrics
 func (m *CacheMetrics) UpdateL1CacheCapacity(capacity, used int64) {
 	m.Capacity.WithLabelValues("l1").Set(float64(capacity))
@@ -288,6 +488,25 @@ func (m *CacheMetrics) UpdateCacheKeys(level string, count int64) {
 	m.Keys.WithLabelValues(level).Set(float64(count))
 }
 
+// UpdateCircuitBreakerState records the circuit breaker state of a level, counting
+// transitions to open
+func (m *CacheMetrics) UpdateCircuitBreakerState(level string, state cache.CircuitState) {
+	m.CircuitBreakerState.WithLabelValues(level).Set(float64(state))
+	if state == cache.CircuitOpen {
+		m.CircuitBreakerOpened.WithLabelValues(level).Inc()
+	}
+}
+
+// UpdateWriteQueueDepth updates the number of queued write-behind writes of a level
+func (m *CacheMetrics) UpdateWriteQueueDepth(level string, depth int) {
+	m.WriteQueueDepth.WithLabelValues(level).Set(float64(depth))
+}
+
+// RecordWriteDropped records a write-behind write that was dropped for the given reason
+func (m *CacheMetrics) RecordWriteDropped(level, reason string) {
+	m.WriteDropped.WithLabelValues(level, reason).Inc()
+}
+
 // TimeCacheOperation returns a timer function for measuring cache operation duration
 func (m *CacheMetrics) TimeCacheOperation(operation, level string) func() {
 	timer := prometheus.NewTimer(m.OperationDuration.WithLabelValues(operation, level))
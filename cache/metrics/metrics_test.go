@@ -6,6 +6,8 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/status-im/proxy-common/cache"
 )
 
 func TestNew(t *testing.T) {
@@ -96,6 +98,9 @@ func TestNew(t *testing.T) {
 		if m.BytesWritten == nil {
 			t.Error("BytesWritten counter not initialized")
 		}
+		if m.Coalesced == nil {
+			t.Error("Coalesced counter not initialized")
+		}
 	})
 
 	t.Run("initializes all histogram metrics", func(t *testing.T) {
@@ -271,6 +276,39 @@ func TestNormalizeNetwork(t *testing.T) {
 	}
 }
 
+func TestNew_Registerer(t *testing.T) {
+	t.Run("same namespace on separate registries", func(t *testing.T) {
+		reg1 := prometheus.NewRegistry()
+		reg2 := prometheus.NewRegistry()
+
+		m1 := New(Config{Namespace: "test_registerer", Registerer: reg1})
+		m2 := New(Config{Namespace: "test_registerer", Registerer: reg2})
+
+		m1.RecordCacheError("l2", "timeout")
+		m2.RecordCacheError("l2", "timeout")
+		m2.RecordCacheError("l2", "timeout")
+
+		if n, err := testutil.GatherAndCount(reg1, "test_registerer_cache_errors_total"); err != nil || n != 1 {
+			t.Errorf("expected 1 series in first registry, got %d (%v)", n, err)
+		}
+		if val := testutil.ToFloat64(m2.Errors.WithLabelValues("l2", "timeout")); val != 2 {
+			t.Errorf("expected second registry counter to be 2, got %f", val)
+		}
+	})
+
+	t.Run("duplicate registration on one registry panics", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		New(Config{Namespace: "test_registerer_dup", Registerer: reg})
+
+		defer func() {
+			if recover() == nil {
+				t.Error("expected duplicate registration to panic")
+			}
+		}()
+		New(Config{Namespace: "test_registerer_dup", Registerer: reg})
+	})
+}
+
 func TestRecordCacheHit(t *testing.T) {
 	// Create a new registry for isolation
 	reg := prometheus.NewRegistry()
@@ -365,6 +403,28 @@ func TestRecordCacheMiss(t *testing.T) {
 	})
 }
 
+func TestRecordCacheNegativeHit(t *testing.T) {
+	m := New(Config{Namespace: "test_negative", Subsystem: "cache"})
+	m.InitializeAllowedMethods([]string{"eth_getTransactionByHash"})
+
+	m.RecordCacheNegativeHit("short", "l1", "ethereum", "mainnet", "eth_getTransactionByHash")
+
+	negativeVal := testutil.ToFloat64(m.NegativeHits.WithLabelValues("short", "l1", "ethereum:mainnet", "eth_getTransactionByHash"))
+	if negativeVal != 1.0 {
+		t.Errorf("expected NegativeHits counter to be 1.0, got %f", negativeVal)
+	}
+
+	requestsVal := testutil.ToFloat64(m.Requests.WithLabelValues("short", "l1", "ethereum:mainnet", "eth_getTransactionByHash"))
+	if requestsVal != 1.0 {
+		t.Errorf("expected Requests counter to be 1.0, got %f", requestsVal)
+	}
+
+	hitsVal := testutil.ToFloat64(m.Hits.WithLabelValues("short", "l1", "ethereum:mainnet", "eth_getTransactionByHash"))
+	if hitsVal != 0 {
+		t.Errorf("expected negative hits not to count as hits, got %f", hitsVal)
+	}
+}
+
 func TestRecordCacheSet(t *testing.T) {
 	m := New(Config{Namespace: "test_set", Subsystem: "cache"})
 
@@ -411,6 +471,37 @@ func TestRecordCacheEviction(t *testing.T) {
 	})
 }
 
+func TestRecordCacheEvictionReason(t *testing.T) {
+	m := New(Config{Namespace: "test_eviction_reason", Subsystem: "cache"})
+
+	t.Run("increments evictions by reason counter", func(t *testing.T) {
+		m.RecordCacheEvictionReason("l1", "no_space")
+
+		evictionsVal := testutil.ToFloat64(m.EvictionsByReason.WithLabelValues("l1", "no_space"))
+		if evictionsVal != 1.0 {
+			t.Errorf("expected EvictionsByReason counter to be 1.0, got %f", evictionsVal)
+		}
+	})
+}
+
+func TestRecordL1CacheStats(t *testing.T) {
+	m := New(Config{Namespace: "test_l1_stats", Subsystem: "cache"})
+
+	t.Run("adds collisions and delete hits", func(t *testing.T) {
+		m.RecordL1CacheStats(2, 3)
+		m.RecordL1CacheStats(1, 0)
+
+		collisionsVal := testutil.ToFloat64(m.Collisions.WithLabelValues("l1"))
+		if collisionsVal != 3.0 {
+			t.Errorf("expected Collisions counter to be 3.0, got %f", collisionsVal)
+		}
+		deleteHitsVal := testutil.ToFloat64(m.DeleteHits.WithLabelValues("l1"))
+		if deleteHitsVal != 3.0 {
+			t.Errorf("expected DeleteHits counter to be 3.0, got %f", deleteHitsVal)
+		}
+	})
+}
+
 func TestRecordCacheError(t *testing.T) {
 	m := New(Config{Namespace: "test_error", Subsystem: "cache"})
 
@@ -441,6 +532,50 @@ func TestRecordCacheError(t *testing.T) {
 	})
 }
 
+func TestRecordCacheCoalesced(t *testing.T) {
+	m := New(Config{Namespace: "test_coalesced", Subsystem: "cache"})
+
+	t.Run("increments coalesced counter", func(t *testing.T) {
+		m.RecordCacheCoalesced("load")
+		m.RecordCacheCoalesced("load")
+
+		coalescedVal := testutil.ToFloat64(m.Coalesced.WithLabelValues("load"))
+		if coalescedVal != 2.0 {
+			t.Errorf("expected Coalesced counter to be 2.0, got %f", coalescedVal)
+		}
+	})
+}
+
+func TestRecordCacheCompression(t *testing.T) {
+	m := New(Config{Namespace: "test_compression", Subsystem: "cache"})
+
+	t.Run("adds raw and compressed bytes", func(t *testing.T) {
+		m.RecordCacheCompression("l1", "zstd", 4096, 512)
+
+		rawVal := testutil.ToFloat64(m.CompressionRawBytes.WithLabelValues("l1", "zstd"))
+		compressedVal := testutil.ToFloat64(m.CompressionCompressedBytes.WithLabelValues("l1", "zstd"))
+		if rawVal != 4096.0 {
+			t.Errorf("expected CompressionRawBytes to be 4096.0, got %f", rawVal)
+		}
+		if compressedVal != 512.0 {
+			t.Errorf("expected CompressionCompressedBytes to be 512.0, got %f", compressedVal)
+		}
+	})
+}
+
+func TestRecordCacheTTLAdjusted(t *testing.T) {
+	m := New(Config{Namespace: "test_ttl_adjusted", Subsystem: "cache"})
+
+	t.Run("increments ttl adjusted counter", func(t *testing.T) {
+		m.RecordCacheTTLAdjusted("l2", "clamped")
+
+		clampedVal := testutil.ToFloat64(m.TTLAdjusted.WithLabelValues("l2", "clamped"))
+		if clampedVal != 1.0 {
+			t.Errorf("expected TTLAdjusted counter to be 1.0, got %f", clampedVal)
+		}
+	})
+}
+
 func TestRecordCacheBytesRead(t *testing.T) {
 	m := New(Config{Namespace: "test_bytes_read", Subsystem: "cache"})
 
@@ -523,6 +658,45 @@ func TestUpdateCacheKeys(t *testing.T) {
 	})
 }
 
+func TestUpdateCircuitBreakerState(t *testing.T) {
+	m := New(Config{Namespace: "test_circuit", Subsystem: "cache"})
+
+	m.UpdateCircuitBreakerState("l2", cache.CircuitOpen)
+	m.UpdateCircuitBreakerState("l2", cache.CircuitHalfOpen)
+	m.UpdateCircuitBreakerState("l2", cache.CircuitOpen)
+
+	if v := testutil.ToFloat64(m.CircuitBreakerState.WithLabelValues("l2")); v != 2.0 {
+		t.Errorf("expected CircuitBreakerState to be 2.0, got %f", v)
+	}
+	if v := testutil.ToFloat64(m.CircuitBreakerOpened.WithLabelValues("l2")); v != 2.0 {
+		t.Errorf("expected CircuitBreakerOpened to be 2.0, got %f", v)
+	}
+
+	m.UpdateCircuitBreakerState("l2", cache.CircuitClosed)
+	if v := testutil.ToFloat64(m.CircuitBreakerState.WithLabelValues("l2")); v != 0.0 {
+		t.Errorf("expected CircuitBreakerState to be 0.0, got %f", v)
+	}
+}
+
+func TestWriteBehindMetrics(t *testing.T) {
+	m := New(Config{Namespace: "test_write_behind", Subsystem: "cache"})
+
+	m.UpdateWriteQueueDepth("l2", 42)
+	m.RecordWriteDropped("l2", "queue_full")
+	m.RecordWriteDropped("l2", "queue_full")
+	m.RecordWriteDropped("l2", "circuit_open")
+
+	if v := testutil.ToFloat64(m.WriteQueueDepth.WithLabelValues("l2")); v != 42.0 {
+		t.Errorf("expected WriteQueueDepth to be 42.0, got %f", v)
+	}
+	if v := testutil.ToFloat64(m.WriteDropped.WithLabelValues("l2", "queue_full")); v != 2.0 {
+		t.Errorf("expected queue_full drops to be 2.0, got %f", v)
+	}
+	if v := testutil.ToFloat64(m.WriteDropped.WithLabelValues("l2", "circuit_open")); v != 1.0 {
+		t.Errorf("expected circuit_open drops to be 1.0, got %f", v)
+	}
+}
+
 func TestTimeCacheOperation(t *testing.T) {
 	m := New(Config{Namespace: "test_timer", Subsystem: "cache"})
 
@@ -0,0 +1,307 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/status-im/proxy-common/cache"
+)
+
+// Ensure OTelMetrics implements cache.MetricsRecorder and the optional recorders
+var _ cache.MetricsRecorder = (*OTelMetrics)(nil)
+var _ cache.CoalescingMetricsRecorder = (*OTelMetrics)(nil)
+var _ cache.CompressionMetricsRecorder = (*OTelMetrics)(nil)
+var _ cache.TTLMetricsRecorder = (*OTelMetrics)(nil)
+var _ cache.L1StatsMetricsRecorder = (*OTelMetrics)(nil)
+var _ cache.NegativeCacheMetricsRecorder = (*OTelMetrics)(nil)
+var _ cache.CircuitBreakerMetricsRecorder = (*OTelMetrics)(nil)
+var _ cache.WriteBehindMetricsRecorder = (*OTelMetrics)(nil)
+
+// OTelMetrics records cache metrics through an OpenTelemetry Meter. Instruments carry
+// the same names and attributes as the Prometheus metrics, separated by dots instead of
+// underscores and without the _total suffix, e.g. proxy.cache.hits.
+type OTelMetrics struct {
+	allowedMethods map[string]bool
+
+	requests     metric.Int64Counter
+	hits         metric.Int64Counter
+	misses       metric.Int64Counter
+	negativeHits metric.Int64Counter
+	sets         metric.Int64Counter
+	evictions    metric.Int64Counter
+	errors       metric.Int64Counter
+	bytesRead    metric.Int64Counter
+	bytesWritten metric.Int64Counter
+	coalesced    metric.Int64Counter
+	collisions   metric.Int64Counter
+	deleteHits   metric.Int64Counter
+
+	evictionsByReason          metric.Int64Counter
+	compressionRawBytes        metric.Int64Counter
+	compressionCompressedBytes metric.Int64Counter
+	ttlAdjusted                metric.Int64Counter
+	circuitBreakerOpened       metric.Int64Counter
+	writeDropped               metric.Int64Counter
+
+	operationDuration metric.Float64Histogram
+	itemAge           metric.Float64Histogram
+
+	keys     metric.Int64Gauge
+	capacity metric.Int64Gauge
+	used     metric.Int64Gauge
+
+	circuitBreakerState metric.Int64Gauge
+	writeQueueDepth     metric.Int64Gauge
+}
+
+// NewOTel creates the cache instruments on meter, using the namespace and subsystem from cfg
+func NewOTel(meter metric.Meter, cfg Config) (*OTelMetrics, error) {
+	if cfg.Namespace == "" {
+		cfg.Namespace = DefaultNamespace
+	}
+	if cfg.Subsystem == "" {
+		cfg.Subsystem = DefaultSubsystem
+	}
+	prefix := cfg.Namespace + "." + cfg.Subsystem + "."
+
+	var errs []error
+	counter := func(name, description string) metric.Int64Counter {
+		c, err := meter.Int64Counter(prefix+name, metric.WithDescription(description))
+		errs = append(errs, err)
+		return c
+	}
+	gauge := func(name, description, unit string) metric.Int64Gauge {
+		g, err := meter.Int64Gauge(prefix+name, metric.WithDescription(description), metric.WithUnit(unit))
+		errs = append(errs, err)
+		return g
+	}
+	histogram := func(name, description string, buckets ...float64) metric.Float64Histogram {
+		opts := []metric.Float64HistogramOption{metric.WithDescription(description), metric.WithUnit("s")}
+		if len(buckets) > 0 {
+			opts = append(opts, metric.WithExplicitBucketBoundaries(buckets...))
+		}
+		h, err := meter.Float64Histogram(prefix+name, opts...)
+		errs = append(errs, err)
+		return h
+	}
+
+	m := &OTelMetrics{
+		requests:     counter("requests", "Total number of cache requests"),
+		hits:         counter("hits", "Total number of cache hits"),
+		misses:       counter("misses", "Total number of cache misses"),
+		negativeHits: counter("negative_hits", "Total number of hits on negative (not found or error) entries"),
+		sets:         counter("sets", "Total number of cache set operations"),
+		evictions:    counter("evictions", "Total number of cache evictions"),
+		errors:       counter("errors", "Cache errors by kind"),
+		bytesRead:    counter("bytes_read", "Bytes read from cache"),
+		bytesWritten: counter("bytes_written", "Bytes written to cache"),
+		coalesced:    counter("coalesced", "Requests that waited on an in-flight operation for the same key"),
+		collisions:   counter("collisions", "Total number of key hash collisions"),
+		deleteHits:   counter("delete_hits", "Total number of deletes that removed an entry"),
+
+		evictionsByReason:          counter("evictions_by_reason", "Entries that left the cache by reason"),
+		compressionRawBytes:        counter("compression_raw_bytes", "Bytes of cache values before compression"),
+		compressionCompressedBytes: counter("compression_compressed_bytes", "Bytes of cache values after compression"),
+		ttlAdjusted:                counter("ttl_adjusted", "Writes whose TTL was defaulted or clamped by the level's TTL limits"),
+		circuitBreakerOpened:       counter("circuit_breaker_opened", "Number of times the circuit breaker opened"),
+		writeDropped:               counter("write_dropped", "Writes dropped by the write-behind queue by reason"),
+
+		operationDuration: histogram("operation_duration", "Duration of cache operations"),
+		itemAge:           histogram("item_age", "Age of item at hit time", 0.1, 0.5, 1, 2, 5, 10, 30, 60, 120, 300, 600, 1800, 3600),
+
+		keys:     gauge("keys", "Current number of keys in cache", "{key}"),
+		capacity: gauge("capacity", "L1 cache capacity in bytes", "By"),
+		used:     gauge("used", "L1 cache used space in bytes", "By"),
+
+		circuitBreakerState: gauge("circuit_breaker_state", "Circuit breaker state: 0 closed, 1 half-open, 2 open", "1"),
+		writeQueueDepth:     gauge("write_queue_depth", "Number of writes waiting in the write-behind queue", "{write}"),
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// InitializeAllowedMethods initializes the allowed methods whitelist from cache rules
+func (m *OTelMetrics) InitializeAllowedMethods(methods []string) {
+	m.allowedMethods = make(map[string]bool)
+
+	for _, method := range methods {
+		m.allowedMethods[method] = true
+	}
+}
+
+// RecordCacheHit records a cache hit with enhanced labels and age tracking
+func (m *OTelMetrics) RecordCacheHit(cacheType, level, chain, network, rpcMethod string, itemAge time.Duration) {
+	attrs := metric.WithAttributes(
+		attribute.String("cache_type", cacheType),
+		attribute.String("level", level),
+		attribute.String("network", normalizeNetwork(chain, network)),
+		attribute.String("rpc_method", normalizeRPCMethod(m.allowedMethods, rpcMethod)),
+	)
+	m.requests.Add(context.Background(), 1, attrs)
+	m.hits.Add(context.Background(), 1, attrs)
+
+	if itemAge > 0 {
+		m.itemAge.Record(context.Background(), itemAge.Seconds(), metric.WithAttributes(
+			attribute.String("level", level),
+			attribute.String("cache_type", cacheType),
+		))
+	}
+}
+
+// RecordCacheMiss records a cache miss with enhanced labels
+func (m *OTelMetrics) RecordCacheMiss(cacheType, chain, network, rpcMethod string) {
+	attrs := metric.WithAttributes(
+		attribute.String("cache_type", cacheType),
+		attribute.String("level", "miss"),
+		attribute.String("network", normalizeNetwork(chain, network)),
+		attribute.String("rpc_method", normalizeRPCMethod(m.allowedMethods, rpcMethod)),
+	)
+	m.requests.Add(context.Background(), 1, attrs)
+	m.misses.Add(context.Background(), 1, attrs)
+}
+
+// RecordCacheNegativeHit records a hit on a negative entry
+func (m *OTelMetrics) RecordCacheNegativeHit(cacheType, level, chain, network, rpcMethod string) {
+	attrs := metric.WithAttributes(
+		attribute.String("cache_type", cacheType),
+		attribute.String("level", level),
+		attribute.String("network", normalizeNetwork(chain, network)),
+		attribute.String("rpc_method", normalizeRPCMethod(m.allowedMethods, rpcMethod)),
+	)
+	m.requests.Add(context.Background(), 1, attrs)
+	m.negativeHits.Add(context.Background(), 1, attrs)
+}
+
+// RecordCacheSet records a cache set operation with size tracking
+func (m *OTelMetrics) RecordCacheSet(level, cacheType, chain, network string, dataSize int) {
+	attrs := levelTypeNetwork(level, cacheType, chain, network)
+	m.sets.Add(context.Background(), 1, attrs)
+	if dataSize > 0 {
+		m.bytesWritten.Add(context.Background(), int64(dataSize), attrs)
+	}
+}
+
+// RecordCacheBytesRead records bytes read from cache
+func (m *OTelMetrics) RecordCacheBytesRead(level, cacheType, chain, network string, bytesRead int) {
+	if bytesRead > 0 {
+		m.bytesRead.Add(context.Background(), int64(bytesRead), levelTypeNetwork(level, cacheType, chain, network))
+	}
+}
+
+// RecordCacheEviction records a cache eviction
+func (m *OTelMetrics) RecordCacheEviction(level, cacheType, chain, network string) {
+	m.evictions.Add(context.Background(), 1, levelTypeNetwork(level, cacheType, chain, network))
+}
+
+// RecordCacheEvictionReason records an entry leaving the cache for the given reason
+func (m *OTelMetrics) RecordCacheEvictionReason(level, reason string) {
+	m.evictionsByReason.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("level", level),
+		attribute.String("reason", reason),
+	))
+}
+
+// RecordL1CacheStats records key collisions and successful deletes since the previous call
+func (m *OTelMetrics) RecordL1CacheStats(collisions, deleteHits int64) {
+	attrs := metric.WithAttributes(attribute.String("level", "l1"))
+	if collisions > 0 {
+		m.collisions.Add(context.Background(), collisions, attrs)
+	}
+	if deleteHits > 0 {
+		m.deleteHits.Add(context.Background(), deleteHits, attrs)
+	}
+}
+
+// RecordCacheError records a cache error
+func (m *OTelMetrics) RecordCacheError(level, kind string) {
+	m.errors.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("level", level),
+		attribute.String("kind", kind),
+	))
+}
+
+// RecordCacheCoalesced records a request that was collapsed into an in-flight operation
+func (m *OTelMetrics) RecordCacheCoalesced(operation string) {
+	m.coalesced.Add(context.Background(), 1, metric.WithAttributes(attribute.String("operation", operation)))
+}
+
+// RecordCacheCompression records the size of a value before and after compression
+func (m *OTelMetrics) RecordCacheCompression(level, algorithm string, rawBytes, compressedBytes int) {
+	attrs := metric.WithAttributes(
+		attribute.String("level", level),
+		attribute.String("algorithm", algorithm),
+	)
+	m.compressionRawBytes.Add(context.Background(), int64(rawBytes), attrs)
+	m.compressionCompressedBytes.Add(context.Background(), int64(compressedBytes), attrs)
+}
+
+// RecordCacheTTLAdjusted records a write whose TTL was defaulted or clamped
+func (m *OTelMetrics) RecordCacheTTLAdjusted(level, reason string) {
+	m.ttlAdjusted.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("level", level),
+		attribute.String("reason", reason),
+	))
+}
+
+// UpdateL1CacheCapacity updates L1 cache capacity metrics
+func (m *OTelMetrics) UpdateL1CacheCapacity(capacity, used int64) {
+	attrs := metric.WithAttributes(attribute.String("level", "l1"))
+	m.capacity.Record(context.Background(), capacity, attrs)
+	m.used.Record(context.Background(), used, attrs)
+}
+
+// UpdateCacheKeys updates the number of keys in cache
+func (m *OTelMetrics) UpdateCacheKeys(level string, count int64) {
+	m.keys.Record(context.Background(), count, metric.WithAttributes(attribute.String("level", level)))
+}
+
+// UpdateCircuitBreakerState records the circuit breaker state of a level, counting
+// transitions to open
+func (m *OTelMetrics) UpdateCircuitBreakerState(level string, state cache.CircuitState) {
+	attrs := metric.WithAttributes(attribute.String("level", level))
+	m.circuitBreakerState.Record(context.Background(), int64(state), attrs)
+	if state == cache.CircuitOpen {
+		m.circuitBreakerOpened.Add(context.Background(), 1, attrs)
+	}
+}
+
+// UpdateWriteQueueDepth updates the number of queued write-behind writes of a level
+func (m *OTelMetrics) UpdateWriteQueueDepth(level string, depth int) {
+	m.writeQueueDepth.Record(context.Background(), int64(depth), metric.WithAttributes(attribute.String("level", level)))
+}
+
+// RecordWriteDropped records a write-behind write that was dropped for the given reason
+func (m *OTelMetrics) RecordWriteDropped(level, reason string) {
+	m.writeDropped.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("level", level),
+		attribute.String("reason", reason),
+	))
+}
+
+// TimeCacheOperation returns a timer function for measuring cache operation duration
+func (m *OTelMetrics) TimeCacheOperation(operation, level string) func() {
+	start := time.Now()
+	return func() {
+		m.operationDuration.Record(context.Background(), time.Since(start).Seconds(), metric.WithAttributes(
+			attribute.String("operation", operation),
+			attribute.String("level", level),
+		))
+	}
+}
+
+// levelTypeNetwork returns the attributes shared by the set, eviction and bytes instruments
+func levelTypeNetwork(level, cacheType, chain, network string) metric.MeasurementOption {
+	return metric.WithAttributes(
+		attribute.String("level", level),
+		attribute.String("cache_type", cacheType),
+		attribute.String("network", normalizeNetwork(chain, network)),
+	)
+}
@@ -0,0 +1,179 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/status-im/proxy-common/cache"
+)
+
+func newTestOTelMetrics(t *testing.T) (*OTelMetrics, *sdkmetric.ManualReader) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	m, err := NewOTel(provider.Meter("test"), Config{Namespace: "test_proxy"})
+	require.NoError(t, err)
+	return m, reader
+}
+
+// collect returns the metrics gathered by reader, keyed by instrument name
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	data := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			data[m.Name] = m.Data
+		}
+	}
+	return data
+}
+
+// sumValue returns the counter value for the data point whose attributes include attrs
+func sumValue(t *testing.T, agg metricdata.Aggregation, attrs ...attribute.KeyValue) int64 {
+	sum, ok := agg.(metricdata.Sum[int64])
+	require.True(t, ok, "expected an int64 sum, got %T", agg)
+
+	for _, dp := range sum.DataPoints {
+		if hasAttributes(dp.Attributes, attrs) {
+			return dp.Value
+		}
+	}
+	return 0
+}
+
+func hasAttributes(set attribute.Set, attrs []attribute.KeyValue) bool {
+	for _, want := range attrs {
+		got, ok := set.Value(want.Key)
+		if !ok || got != want.Value {
+			return false
+		}
+	}
+	return true
+}
+
+func TestOTelMetrics_HitsAndMisses(t *testing.T) {
+	m, reader := newTestOTelMetrics(t)
+	m.InitializeAllowedMethods([]string{"eth_getBalance"})
+
+	m.RecordCacheHit("short", "l1", "ethereum", "mainnet", "eth_getBalance", 2*time.Second)
+	m.RecordCacheHit("short", "l2", "ethereum", "mainnet", "eth_call", 0)
+	m.RecordCacheMiss("short", "ethereum", "mainnet", "eth_getBalance")
+	m.RecordCacheNegativeHit("short", "l2", "ethereum", "mainnet", "eth_getBalance")
+
+	data := collect(t, reader)
+
+	assert.Equal(t, int64(1), sumValue(t, data["test_proxy.cache.hits"],
+		attribute.String("level", "l1"),
+		attribute.String("network", "ethereum:mainnet"),
+		attribute.String("rpc_method", "eth_getBalance")))
+	assert.Equal(t, int64(1), sumValue(t, data["test_proxy.cache.hits"],
+		attribute.String("level", "l2"),
+		attribute.String("rpc_method", "other")))
+	assert.Equal(t, int64(1), sumValue(t, data["test_proxy.cache.misses"], attribute.String("level", "miss")))
+	assert.Equal(t, int64(1), sumValue(t, data["test_proxy.cache.negative_hits"],
+		attribute.String("level", "l2"),
+		attribute.String("rpc_method", "eth_getBalance")))
+	assert.Equal(t, int64(1), sumValue(t, data["test_proxy.cache.requests"], attribute.String("level", "l1")))
+	assert.Equal(t, int64(1), sumValue(t, data["test_proxy.cache.requests"], attribute.String("level", "miss")))
+	assert.Equal(t, int64(1), sumValue(t, data["test_proxy.cache.requests"],
+		attribute.String("level", "l2"),
+		attribute.String("rpc_method", "eth_getBalance")))
+
+	age, ok := data["test_proxy.cache.item_age"].(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, age.DataPoints, 1)
+	assert.Equal(t, uint64(1), age.DataPoints[0].Count)
+	assert.Equal(t, 2.0, age.DataPoints[0].Sum)
+}
+
+func TestOTelMetrics_SetsErrorsAndEvictions(t *testing.T) {
+	m, reader := newTestOTelMetrics(t)
+
+	m.RecordCacheSet("l2", "permanent", "", "", 100)
+	m.RecordCacheBytesRead("l2", "permanent", "", "", 40)
+	m.RecordCacheError("l2", "timeout")
+	m.RecordCacheEviction("l1", "permanent", "", "")
+	m.RecordCacheEvictionReason("l1", "no_space")
+	m.RecordL1CacheStats(3, 0)
+	m.RecordCacheTTLAdjusted("l2", "clamped")
+
+	data := collect(t, reader)
+
+	unknown := attribute.String("network", "unknown")
+	assert.Equal(t, int64(1), sumValue(t, data["test_proxy.cache.sets"], unknown))
+	assert.Equal(t, int64(100), sumValue(t, data["test_proxy.cache.bytes_written"], unknown))
+	assert.Equal(t, int64(40), sumValue(t, data["test_proxy.cache.bytes_read"], unknown))
+	assert.Equal(t, int64(1), sumValue(t, data["test_proxy.cache.errors"], attribute.String("kind", "timeout")))
+	assert.Equal(t, int64(1), sumValue(t, data["test_proxy.cache.evictions"], unknown))
+	assert.Equal(t, int64(1), sumValue(t, data["test_proxy.cache.evictions_by_reason"], attribute.String("reason", "no_space")))
+	assert.Equal(t, int64(3), sumValue(t, data["test_proxy.cache.collisions"], attribute.String("level", "l1")))
+	assert.Equal(t, int64(1), sumValue(t, data["test_proxy.cache.ttl_adjusted"], attribute.String("reason", "clamped")))
+	assert.NotContains(t, data, "test_proxy.cache.delete_hits")
+}
+
+func TestOTelMetrics_GaugesAndTimers(t *testing.T) {
+	m, reader := newTestOTelMetrics(t)
+
+	m.UpdateL1CacheCapacity(1024, 256)
+	m.UpdateCacheKeys("l1", 7)
+	m.TimeCacheOperation("get", "multi")()
+
+	data := collect(t, reader)
+
+	capacity, ok := data["test_proxy.cache.capacity"].(metricdata.Gauge[int64])
+	require.True(t, ok)
+	assert.Equal(t, int64(1024), capacity.DataPoints[0].Value)
+
+	used, ok := data["test_proxy.cache.used"].(metricdata.Gauge[int64])
+	require.True(t, ok)
+	assert.Equal(t, int64(256), used.DataPoints[0].Value)
+
+	keys, ok := data["test_proxy.cache.keys"].(metricdata.Gauge[int64])
+	require.True(t, ok)
+	assert.Equal(t, int64(7), keys.DataPoints[0].Value)
+
+	duration, ok := data["test_proxy.cache.operation_duration"].(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, duration.DataPoints, 1)
+	assert.Equal(t, uint64(1), duration.DataPoints[0].Count)
+}
+
+func TestOTelMetrics_CircuitBreakerState(t *testing.T) {
+	m, reader := newTestOTelMetrics(t)
+
+	m.UpdateCircuitBreakerState("l2", cache.CircuitOpen)
+	m.UpdateCircuitBreakerState("l2", cache.CircuitHalfOpen)
+
+	data := collect(t, reader)
+
+	state, ok := data["test_proxy.cache.circuit_breaker_state"].(metricdata.Gauge[int64])
+	require.True(t, ok)
+	assert.Equal(t, int64(cache.CircuitHalfOpen), state.DataPoints[0].Value)
+	assert.Equal(t, int64(1), sumValue(t, data["test_proxy.cache.circuit_breaker_opened"],
+		attribute.String("level", "l2")))
+}
+
+func TestOTelMetrics_WriteBehind(t *testing.T) {
+	m, reader := newTestOTelMetrics(t)
+
+	m.UpdateWriteQueueDepth("l2", 42)
+	m.RecordWriteDropped("l2", "queue_full")
+
+	data := collect(t, reader)
+
+	depth, ok := data["test_proxy.cache.write_queue_depth"].(metricdata.Gauge[int64])
+	require.True(t, ok)
+	assert.Equal(t, int64(42), depth.DataPoints[0].Value)
+	assert.Equal(t, int64(1), sumValue(t, data["test_proxy.cache.write_dropped"],
+		attribute.String("reason", "queue_full")))
+}
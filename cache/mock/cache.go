@@ -15,6 +15,7 @@ import (
 	time "time"
 
 	redis "github.com/go-redis/redis/v8"
+	cache "github.com/status-im/proxy-common/cache"
 	models "github.com/status-im/proxy-common/models"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -203,32 +204,1454 @@ func (mr *MockLevelAwareCacheMockRecorder) Set(key, val, ttl any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockLevelAwareCache)(nil).Set), key, val, ttl)
 }
 
+// MockBindableCache is a mock of BindableCache interface.
+type MockBindableCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockBindableCacheMockRecorder
+	isgomock struct{}
+}
+
+// MockBindableCacheMockRecorder is the mock recorder for MockBindableCache.
+type MockBindableCacheMockRecorder struct {
+	mock *MockBindableCache
+}
+
+// NewMockBindableCache creates a new mock instance.
+func NewMockBindableCache(ctrl *gomock.Controller) *MockBindableCache {
+	mock := &MockBindableCache{ctrl: ctrl}
+	mock.recorder = &MockBindableCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBindableCache) EXPECT() *MockBindableCacheMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockBindableCache) Delete(key string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Delete", key)
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBindableCacheMockRecorder) Delete(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBindableCache)(nil).Delete), key)
+}
+
+// ForCacheType mocks base method.
+func (m *MockBindableCache) ForCacheType(cacheType models.CacheType) cache.LevelAwareCache {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForCacheType", cacheType)
+	ret0, _ := ret[0].(cache.LevelAwareCache)
+	return ret0
+}
+
+// ForCacheType indicates an expected call of ForCacheType.
+func (mr *MockBindableCacheMockRecorder) ForCacheType(cacheType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForCacheType", reflect.TypeOf((*MockBindableCache)(nil).ForCacheType), cacheType)
+}
+
+// Get mocks base method.
+func (m *MockBindableCache) Get(key string) (*models.CacheEntry, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockBindableCacheMockRecorder) Get(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBindableCache)(nil).Get), key)
+}
+
+// GetStale mocks base method.
+func (m *MockBindableCache) GetStale(key string) (*models.CacheEntry, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStale", key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetStale indicates an expected call of GetStale.
+func (mr *MockBindableCacheMockRecorder) GetStale(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStale", reflect.TypeOf((*MockBindableCache)(nil).GetStale), key)
+}
+
+// GetStaleWithLevel mocks base method.
+func (m *MockBindableCache) GetStaleWithLevel(key string) *models.CacheResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStaleWithLevel", key)
+	ret0, _ := ret[0].(*models.CacheResult)
+	return ret0
+}
+
+// GetStaleWithLevel indicates an expected call of GetStaleWithLevel.
+func (mr *MockBindableCacheMockRecorder) GetStaleWithLevel(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStaleWithLevel", reflect.TypeOf((*MockBindableCache)(nil).GetStaleWithLevel), key)
+}
+
+// GetWithLevel mocks base method.
+func (m *MockBindableCache) GetWithLevel(key string) *models.CacheResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWithLevel", key)
+	ret0, _ := ret[0].(*models.CacheResult)
+	return ret0
+}
+
+// GetWithLevel indicates an expected call of GetWithLevel.
+func (mr *MockBindableCacheMockRecorder) GetWithLevel(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithLevel", reflect.TypeOf((*MockBindableCache)(nil).GetWithLevel), key)
+}
+
+// Set mocks base method.
+func (m *MockBindableCache) Set(key string, val []byte, ttl models.TTL) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Set", key, val, ttl)
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockBindableCacheMockRecorder) Set(key, val, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockBindableCache)(nil).Set), key, val, ttl)
+}
+
+// WithContext mocks base method.
+func (m *MockBindableCache) WithContext(ctx context.Context) cache.LevelAwareCache {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithContext", ctx)
+	ret0, _ := ret[0].(cache.LevelAwareCache)
+	return ret0
+}
+
+// WithContext indicates an expected call of WithContext.
+func (mr *MockBindableCacheMockRecorder) WithContext(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithContext", reflect.TypeOf((*MockBindableCache)(nil).WithContext), ctx)
+}
+
+// MockSizeLimitedCache is a mock of SizeLimitedCache interface.
+type MockSizeLimitedCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockSizeLimitedCacheMockRecorder
+	isgomock struct{}
+}
+
+// MockSizeLimitedCacheMockRecorder is the mock recorder for MockSizeLimitedCache.
+type MockSizeLimitedCacheMockRecorder struct {
+	mock *MockSizeLimitedCache
+}
+
+// NewMockSizeLimitedCache creates a new mock instance.
+func NewMockSizeLimitedCache(ctrl *gomock.Controller) *MockSizeLimitedCache {
+	mock := &MockSizeLimitedCache{ctrl: ctrl}
+	mock.recorder = &MockSizeLimitedCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSizeLimitedCache) EXPECT() *MockSizeLimitedCacheMockRecorder {
+	return m.recorder
+}
+
+// WithMaxEntrySize mocks base method.
+func (m *MockSizeLimitedCache) WithMaxEntrySize(size int) cache.Cache {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithMaxEntrySize", size)
+	ret0, _ := ret[0].(cache.Cache)
+	return ret0
+}
+
+// WithMaxEntrySize indicates an expected call of WithMaxEntrySize.
+func (mr *MockSizeLimitedCacheMockRecorder) WithMaxEntrySize(size any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithMaxEntrySize", reflect.TypeOf((*MockSizeLimitedCache)(nil).WithMaxEntrySize), size)
+}
+
+// MockTaggedCache is a mock of TaggedCache interface.
+type MockTaggedCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaggedCacheMockRecorder
+	isgomock struct{}
+}
+
+// MockTaggedCacheMockRecorder is the mock recorder for MockTaggedCache.
+type MockTaggedCacheMockRecorder struct {
+	mock *MockTaggedCache
+}
+
+// NewMockTaggedCache creates a new mock instance.
+func NewMockTaggedCache(ctrl *gomock.Controller) *MockTaggedCache {
+	mock := &MockTaggedCache{ctrl: ctrl}
+	mock.recorder = &MockTaggedCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaggedCache) EXPECT() *MockTaggedCacheMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockTaggedCache) Delete(key string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Delete", key)
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTaggedCacheMockRecorder) Delete(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTaggedCache)(nil).Delete), key)
+}
+
+// Get mocks base method.
+func (m *MockTaggedCache) Get(key string) (*models.CacheEntry, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTaggedCacheMockRecorder) Get(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTaggedCache)(nil).Get), key)
+}
+
+// GetStale mocks base method.
+func (m *MockTaggedCache) GetStale(key string) (*models.CacheEntry, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStale", key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetStale indicates an expected call of GetStale.
+func (mr *MockTaggedCacheMockRecorder) GetStale(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStale", reflect.TypeOf((*MockTaggedCache)(nil).GetStale), key)
+}
+
+// InvalidatePrefix mocks base method.
+func (m *MockTaggedCache) InvalidatePrefix(prefix string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidatePrefix", prefix)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// InvalidatePrefix indicates an expected call of InvalidatePrefix.
+func (mr *MockTaggedCacheMockRecorder) InvalidatePrefix(prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidatePrefix", reflect.TypeOf((*MockTaggedCache)(nil).InvalidatePrefix), prefix)
+}
+
+// InvalidateTag mocks base method.
+func (m *MockTaggedCache) InvalidateTag(tag string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateTag", tag)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// InvalidateTag indicates an expected call of InvalidateTag.
+func (mr *MockTaggedCacheMockRecorder) InvalidateTag(tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateTag", reflect.TypeOf((*MockTaggedCache)(nil).InvalidateTag), tag)
+}
+
+// Set mocks base method.
+func (m *MockTaggedCache) Set(key string, val []byte, ttl models.TTL) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Set", key, val, ttl)
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockTaggedCacheMockRecorder) Set(key, val, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockTaggedCache)(nil).Set), key, val, ttl)
+}
+
+// SetWithTags mocks base method.
+func (m *MockTaggedCache) SetWithTags(key string, val []byte, ttl models.TTL, tags ...string) {
+	m.ctrl.T.Helper()
+	varargs := []any{key, val, ttl}
+	for _, a := range tags {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "SetWithTags", varargs...)
+}
+
+// SetWithTags indicates an expected call of SetWithTags.
+func (mr *MockTaggedCacheMockRecorder) SetWithTags(key, val, ttl any, tags ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{key, val, ttl}, tags...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWithTags", reflect.TypeOf((*MockTaggedCache)(nil).SetWithTags), varargs...)
+}
+
+// MockNegativeCache is a mock of NegativeCache interface.
+type MockNegativeCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockNegativeCacheMockRecorder
+	isgomock struct{}
+}
+
+// MockNegativeCacheMockRecorder is the mock recorder for MockNegativeCache.
+type MockNegativeCacheMockRecorder struct {
+	mock *MockNegativeCache
+}
+
+// NewMockNegativeCache creates a new mock instance.
+func NewMockNegativeCache(ctrl *gomock.Controller) *MockNegativeCache {
+	mock := &MockNegativeCache{ctrl: ctrl}
+	mock.recorder = &MockNegativeCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNegativeCache) EXPECT() *MockNegativeCacheMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockNegativeCache) Delete(key string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Delete", key)
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockNegativeCacheMockRecorder) Delete(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNegativeCache)(nil).Delete), key)
+}
+
+// Get mocks base method.
+func (m *MockNegativeCache) Get(key string) (*models.CacheEntry, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockNegativeCacheMockRecorder) Get(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockNegativeCache)(nil).Get), key)
+}
+
+// GetEntry mocks base method.
+func (m *MockNegativeCache) GetEntry(key string) (*models.CacheEntry, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntry", key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetEntry indicates an expected call of GetEntry.
+func (mr *MockNegativeCacheMockRecorder) GetEntry(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockNegativeCache)(nil).GetEntry), key)
+}
+
+// GetStale mocks base method.
+func (m *MockNegativeCache) GetStale(key string) (*models.CacheEntry, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStale", key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetStale indicates an expected call of GetStale.
+func (mr *MockNegativeCacheMockRecorder) GetStale(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStale", reflect.TypeOf((*MockNegativeCache)(nil).GetStale), key)
+}
+
+// GetStaleEntry mocks base method.
+func (m *MockNegativeCache) GetStaleEntry(key string) (*models.CacheEntry, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStaleEntry", key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetStaleEntry indicates an expected call of GetStaleEntry.
+func (mr *MockNegativeCacheMockRecorder) GetStaleEntry(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStaleEntry", reflect.TypeOf((*MockNegativeCache)(nil).GetStaleEntry), key)
+}
+
+// Set mocks base method.
+func (m *MockNegativeCache) Set(key string, val []byte, ttl models.TTL) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Set", key, val, ttl)
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockNegativeCacheMockRecorder) Set(key, val, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockNegativeCache)(nil).Set), key, val, ttl)
+}
+
+// SetNegative mocks base method.
+func (m *MockNegativeCache) SetNegative(key string, errPayload []byte, ttl models.TTL) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetNegative", key, errPayload, ttl)
+}
+
+// SetNegative indicates an expected call of SetNegative.
+func (mr *MockNegativeCacheMockRecorder) SetNegative(key, errPayload, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNegative", reflect.TypeOf((*MockNegativeCache)(nil).SetNegative), key, errPayload, ttl)
+}
+
+// MockNegativeBatchCache is a mock of NegativeBatchCache interface.
+type MockNegativeBatchCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockNegativeBatchCacheMockRecorder
+	isgomock struct{}
+}
+
+// MockNegativeBatchCacheMockRecorder is the mock recorder for MockNegativeBatchCache.
+type MockNegativeBatchCacheMockRecorder struct {
+	mock *MockNegativeBatchCache
+}
+
+// NewMockNegativeBatchCache creates a new mock instance.
+func NewMockNegativeBatchCache(ctrl *gomock.Controller) *MockNegativeBatchCache {
+	mock := &MockNegativeBatchCache{ctrl: ctrl}
+	mock.recorder = &MockNegativeBatchCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNegativeBatchCache) EXPECT() *MockNegativeBatchCacheMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockNegativeBatchCache) Delete(key string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Delete", key)
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockNegativeBatchCacheMockRecorder) Delete(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNegativeBatchCache)(nil).Delete), key)
+}
+
+// Get mocks base method.
+func (m *MockNegativeBatchCache) Get(key string) (*models.CacheEntry, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockNegativeBatchCacheMockRecorder) Get(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockNegativeBatchCache)(nil).Get), key)
+}
+
+// GetMany mocks base method.
+func (m *MockNegativeBatchCache) GetMany(keys []string) map[string]*models.CacheEntry {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMany", keys)
+	ret0, _ := ret[0].(map[string]*models.CacheEntry)
+	return ret0
+}
+
+// GetMany indicates an expected call of GetMany.
+func (mr *MockNegativeBatchCacheMockRecorder) GetMany(keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockNegativeBatchCache)(nil).GetMany), keys)
+}
+
+// GetManyEntries mocks base method.
+func (m *MockNegativeBatchCache) GetManyEntries(keys []string) map[string]*models.CacheEntry {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetManyEntries", keys)
+	ret0, _ := ret[0].(map[string]*models.CacheEntry)
+	return ret0
+}
+
+// GetManyEntries indicates an expected call of GetManyEntries.
+func (mr *MockNegativeBatchCacheMockRecorder) GetManyEntries(keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetManyEntries", reflect.TypeOf((*MockNegativeBatchCache)(nil).GetManyEntries), keys)
+}
+
+// GetStale mocks base method.
+func (m *MockNegativeBatchCache) GetStale(key string) (*models.CacheEntry, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStale", key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetStale indicates an expected call of GetStale.
+func (mr *MockNegativeBatchCacheMockRecorder) GetStale(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStale", reflect.TypeOf((*MockNegativeBatchCache)(nil).GetStale), key)
+}
+
+// Set mocks base method.
+func (m *MockNegativeBatchCache) Set(key string, val []byte, ttl models.TTL) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Set", key, val, ttl)
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockNegativeBatchCacheMockRecorder) Set(key, val, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockNegativeBatchCache)(nil).Set), key, val, ttl)
+}
+
+// SetMany mocks base method.
+func (m *MockNegativeBatchCache) SetMany(items []cache.BatchItem) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetMany", items)
+}
+
+// SetMany indicates an expected call of SetMany.
+func (mr *MockNegativeBatchCacheMockRecorder) SetMany(items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMany", reflect.TypeOf((*MockNegativeBatchCache)(nil).SetMany), items)
+}
+
+// MockHealthChecker is a mock of HealthChecker interface.
+type MockHealthChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthCheckerMockRecorder
+	isgomock struct{}
+}
+
+// MockHealthCheckerMockRecorder is the mock recorder for MockHealthChecker.
+type MockHealthCheckerMockRecorder struct {
+	mock *MockHealthChecker
+}
+
+// NewMockHealthChecker creates a new mock instance.
+func NewMockHealthChecker(ctrl *gomock.Controller) *MockHealthChecker {
+	mock := &MockHealthChecker{ctrl: ctrl}
+	mock.recorder = &MockHealthCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthChecker) EXPECT() *MockHealthCheckerMockRecorder {
+	return m.recorder
+}
+
+// Available mocks base method.
+func (m *MockHealthChecker) Available() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Available")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Available indicates an expected call of Available.
+func (mr *MockHealthCheckerMockRecorder) Available() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Available", reflect.TypeOf((*MockHealthChecker)(nil).Available))
+}
+
+// HealthCheck mocks base method.
+func (m *MockHealthChecker) HealthCheck(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HealthCheck", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HealthCheck indicates an expected call of HealthCheck.
+func (mr *MockHealthCheckerMockRecorder) HealthCheck(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthCheck", reflect.TypeOf((*MockHealthChecker)(nil).HealthCheck), ctx)
+}
+
+// MockStatsReporter is a mock of StatsReporter interface.
+type MockStatsReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsReporterMockRecorder
+	isgomock struct{}
+}
+
+// MockStatsReporterMockRecorder is the mock recorder for MockStatsReporter.
+type MockStatsReporterMockRecorder struct {
+	mock *MockStatsReporter
+}
+
+// NewMockStatsReporter creates a new mock instance.
+func NewMockStatsReporter(ctrl *gomock.Controller) *MockStatsReporter {
+	mock := &MockStatsReporter{ctrl: ctrl}
+	mock.recorder = &MockStatsReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatsReporter) EXPECT() *MockStatsReporterMockRecorder {
+	return m.recorder
+}
+
+// Stats mocks base method.
+func (m *MockStatsReporter) Stats() cache.LevelStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats")
+	ret0, _ := ret[0].(cache.LevelStats)
+	return ret0
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockStatsReporterMockRecorder) Stats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockStatsReporter)(nil).Stats))
+}
+
+// MockBatchCache is a mock of BatchCache interface.
+type MockBatchCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchCacheMockRecorder
+	isgomock struct{}
+}
+
+// MockBatchCacheMockRecorder is the mock recorder for MockBatchCache.
+type MockBatchCacheMockRecorder struct {
+	mock *MockBatchCache
+}
+
+// NewMockBatchCache creates a new mock instance.
+func NewMockBatchCache(ctrl *gomock.Controller) *MockBatchCache {
+	mock := &MockBatchCache{ctrl: ctrl}
+	mock.recorder = &MockBatchCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatchCache) EXPECT() *MockBatchCacheMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockBatchCache) Delete(key string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Delete", key)
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBatchCacheMockRecorder) Delete(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBatchCache)(nil).Delete), key)
+}
+
+// Get mocks base method.
+func (m *MockBatchCache) Get(key string) (*models.CacheEntry, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockBatchCacheMockRecorder) Get(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBatchCache)(nil).Get), key)
+}
+
+// GetMany mocks base method.
+func (m *MockBatchCache) GetMany(keys []string) map[string]*models.CacheEntry {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMany", keys)
+	ret0, _ := ret[0].(map[string]*models.CacheEntry)
+	return ret0
+}
+
+// GetMany indicates an expected call of GetMany.
+func (mr *MockBatchCacheMockRecorder) GetMany(keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockBatchCache)(nil).GetMany), keys)
+}
+
+// GetStale mocks base method.
+func (m *MockBatchCache) GetStale(key string) (*models.CacheEntry, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStale", key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetStale indicates an expected call of GetStale.
+func (mr *MockBatchCacheMockRecorder) GetStale(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStale", reflect.TypeOf((*MockBatchCache)(nil).GetStale), key)
+}
+
+// Set mocks base method.
+func (m *MockBatchCache) Set(key string, val []byte, ttl models.TTL) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Set", key, val, ttl)
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockBatchCacheMockRecorder) Set(key, val, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockBatchCache)(nil).Set), key, val, ttl)
+}
+
+// SetMany mocks base method.
+func (m *MockBatchCache) SetMany(items []cache.BatchItem) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetMany", items)
+}
+
+// SetMany indicates an expected call of SetMany.
+func (mr *MockBatchCacheMockRecorder) SetMany(items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMany", reflect.TypeOf((*MockBatchCache)(nil).SetMany), items)
+}
+
+// MockLevelAwareBatchCache is a mock of LevelAwareBatchCache interface.
+type MockLevelAwareBatchCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockLevelAwareBatchCacheMockRecorder
+	isgomock struct{}
+}
+
+// MockLevelAwareBatchCacheMockRecorder is the mock recorder for MockLevelAwareBatchCache.
+type MockLevelAwareBatchCacheMockRecorder struct {
+	mock *MockLevelAwareBatchCache
+}
+
+// NewMockLevelAwareBatchCache creates a new mock instance.
+func NewMockLevelAwareBatchCache(ctrl *gomock.Controller) *MockLevelAwareBatchCache {
+	mock := &MockLevelAwareBatchCache{ctrl: ctrl}
+	mock.recorder = &MockLevelAwareBatchCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLevelAwareBatchCache) EXPECT() *MockLevelAwareBatchCacheMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockLevelAwareBatchCache) Delete(key string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Delete", key)
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockLevelAwareBatchCacheMockRecorder) Delete(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockLevelAwareBatchCache)(nil).Delete), key)
+}
+
+// Get mocks base method.
+func (m *MockLevelAwareBatchCache) Get(key string) (*models.CacheEntry, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockLevelAwareBatchCacheMockRecorder) Get(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockLevelAwareBatchCache)(nil).Get), key)
+}
+
+// GetMany mocks base method.
+func (m *MockLevelAwareBatchCache) GetMany(keys []string) map[string]*models.CacheEntry {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMany", keys)
+	ret0, _ := ret[0].(map[string]*models.CacheEntry)
+	return ret0
+}
+
+// GetMany indicates an expected call of GetMany.
+func (mr *MockLevelAwareBatchCacheMockRecorder) GetMany(keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockLevelAwareBatchCache)(nil).GetMany), keys)
+}
+
+// GetManyWithLevel mocks base method.
+func (m *MockLevelAwareBatchCache) GetManyWithLevel(keys []string) map[string]*models.CacheResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetManyWithLevel", keys)
+	ret0, _ := ret[0].(map[string]*models.CacheResult)
+	return ret0
+}
+
+// GetManyWithLevel indicates an expected call of GetManyWithLevel.
+func (mr *MockLevelAwareBatchCacheMockRecorder) GetManyWithLevel(keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetManyWithLevel", reflect.TypeOf((*MockLevelAwareBatchCache)(nil).GetManyWithLevel), keys)
+}
+
+// GetStale mocks base method.
+func (m *MockLevelAwareBatchCache) GetStale(key string) (*models.CacheEntry, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStale", key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetStale indicates an expected call of GetStale.
+func (mr *MockLevelAwareBatchCacheMockRecorder) GetStale(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStale", reflect.TypeOf((*MockLevelAwareBatchCache)(nil).GetStale), key)
+}
+
+// Set mocks base method.
+func (m *MockLevelAwareBatchCache) Set(key string, val []byte, ttl models.TTL) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Set", key, val, ttl)
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockLevelAwareBatchCacheMockRecorder) Set(key, val, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockLevelAwareBatchCache)(nil).Set), key, val, ttl)
+}
+
+// SetMany mocks base method.
+func (m *MockLevelAwareBatchCache) SetMany(items []cache.BatchItem) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetMany", items)
+}
+
+// SetMany indicates an expected call of SetMany.
+func (mr *MockLevelAwareBatchCacheMockRecorder) SetMany(items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMany", reflect.TypeOf((*MockLevelAwareBatchCache)(nil).SetMany), items)
+}
+
+// MockInvalidationBus is a mock of InvalidationBus interface.
+type MockInvalidationBus struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvalidationBusMockRecorder
+	isgomock struct{}
+}
+
+// MockInvalidationBusMockRecorder is the mock recorder for MockInvalidationBus.
+type MockInvalidationBusMockRecorder struct {
+	mock *MockInvalidationBus
+}
+
+// NewMockInvalidationBus creates a new mock instance.
+func NewMockInvalidationBus(ctrl *gomock.Controller) *MockInvalidationBus {
+	mock := &MockInvalidationBus{ctrl: ctrl}
+	mock.recorder = &MockInvalidationBusMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInvalidationBus) EXPECT() *MockInvalidationBusMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockInvalidationBus) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockInvalidationBusMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockInvalidationBus)(nil).Close))
+}
+
+// Publish mocks base method.
+func (m *MockInvalidationBus) Publish(ctx context.Context, msg cache.InvalidationMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockInvalidationBusMockRecorder) Publish(ctx, msg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockInvalidationBus)(nil).Publish), ctx, msg)
+}
+
+// Subscribe mocks base method.
+func (m *MockInvalidationBus) Subscribe(handler cache.InvalidationHandler) func() {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", handler)
+	ret0, _ := ret[0].(func())
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockInvalidationBusMockRecorder) Subscribe(handler any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockInvalidationBus)(nil).Subscribe), handler)
+}
+
+// MockCacheV2 is a mock of CacheV2 interface.
+type MockCacheV2 struct {
+	ctrl     *gomock.Controller
+	recorder *MockCacheV2MockRecorder
+	isgomock struct{}
+}
+
+// MockCacheV2MockRecorder is the mock recorder for MockCacheV2.
+type MockCacheV2MockRecorder struct {
+	mock *MockCacheV2
+}
+
+// NewMockCacheV2 creates a new mock instance.
+func NewMockCacheV2(ctrl *gomock.Controller) *MockCacheV2 {
+	mock := &MockCacheV2{ctrl: ctrl}
+	mock.recorder = &MockCacheV2MockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCacheV2) EXPECT() *MockCacheV2MockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockCacheV2) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCacheV2MockRecorder) Delete(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCacheV2)(nil).Delete), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockCacheV2) Get(ctx context.Context, key string) (*models.CacheEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCacheV2MockRecorder) Get(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCacheV2)(nil).Get), ctx, key)
+}
+
+// GetStale mocks base method.
+func (m *MockCacheV2) GetStale(ctx context.Context, key string) (*models.CacheEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStale", ctx, key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStale indicates an expected call of GetStale.
+func (mr *MockCacheV2MockRecorder) GetStale(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStale", reflect.TypeOf((*MockCacheV2)(nil).GetStale), ctx, key)
+}
+
+// Set mocks base method.
+func (m *MockCacheV2) Set(ctx context.Context, key string, val []byte, ttl models.TTL) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, key, val, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockCacheV2MockRecorder) Set(ctx, key, val, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockCacheV2)(nil).Set), ctx, key, val, ttl)
+}
+
+// MockLevelAwareCacheV2 is a mock of LevelAwareCacheV2 interface.
+type MockLevelAwareCacheV2 struct {
+	ctrl     *gomock.Controller
+	recorder *MockLevelAwareCacheV2MockRecorder
+	isgomock struct{}
+}
+
+// MockLevelAwareCacheV2MockRecorder is the mock recorder for MockLevelAwareCacheV2.
+type MockLevelAwareCacheV2MockRecorder struct {
+	mock *MockLevelAwareCacheV2
+}
+
+// NewMockLevelAwareCacheV2 creates a new mock instance.
+func NewMockLevelAwareCacheV2(ctrl *gomock.Controller) *MockLevelAwareCacheV2 {
+	mock := &MockLevelAwareCacheV2{ctrl: ctrl}
+	mock.recorder = &MockLevelAwareCacheV2MockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLevelAwareCacheV2) EXPECT() *MockLevelAwareCacheV2MockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockLevelAwareCacheV2) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockLevelAwareCacheV2MockRecorder) Delete(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockLevelAwareCacheV2)(nil).Delete), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockLevelAwareCacheV2) Get(ctx context.Context, key string) (*models.CacheEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockLevelAwareCacheV2MockRecorder) Get(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockLevelAwareCacheV2)(nil).Get), ctx, key)
+}
+
+// GetStale mocks base method.
+func (m *MockLevelAwareCacheV2) GetStale(ctx context.Context, key string) (*models.CacheEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStale", ctx, key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStale indicates an expected call of GetStale.
+func (mr *MockLevelAwareCacheV2MockRecorder) GetStale(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStale", reflect.TypeOf((*MockLevelAwareCacheV2)(nil).GetStale), ctx, key)
+}
+
+// GetStaleWithLevel mocks base method.
+func (m *MockLevelAwareCacheV2) GetStaleWithLevel(ctx context.Context, key string) (*models.CacheResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStaleWithLevel", ctx, key)
+	ret0, _ := ret[0].(*models.CacheResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStaleWithLevel indicates an expected call of GetStaleWithLevel.
+func (mr *MockLevelAwareCacheV2MockRecorder) GetStaleWithLevel(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStaleWithLevel", reflect.TypeOf((*MockLevelAwareCacheV2)(nil).GetStaleWithLevel), ctx, key)
+}
+
+// GetWithLevel mocks base method.
+func (m *MockLevelAwareCacheV2) GetWithLevel(ctx context.Context, key string) (*models.CacheResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWithLevel", ctx, key)
+	ret0, _ := ret[0].(*models.CacheResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWithLevel indicates an expected call of GetWithLevel.
+func (mr *MockLevelAwareCacheV2MockRecorder) GetWithLevel(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithLevel", reflect.TypeOf((*MockLevelAwareCacheV2)(nil).GetWithLevel), ctx, key)
+}
+
+// Set mocks base method.
+func (m *MockLevelAwareCacheV2) Set(ctx context.Context, key string, val []byte, ttl models.TTL) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, key, val, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockLevelAwareCacheV2MockRecorder) Set(ctx, key, val, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockLevelAwareCacheV2)(nil).Set), ctx, key, val, ttl)
+}
+
+// MockNegativeCacheV2 is a mock of NegativeCacheV2 interface.
+type MockNegativeCacheV2 struct {
+	ctrl     *gomock.Controller
+	recorder *MockNegativeCacheV2MockRecorder
+	isgomock struct{}
+}
+
+// MockNegativeCacheV2MockRecorder is the mock recorder for MockNegativeCacheV2.
+type MockNegativeCacheV2MockRecorder struct {
+	mock *MockNegativeCacheV2
+}
+
+// NewMockNegativeCacheV2 creates a new mock instance.
+func NewMockNegativeCacheV2(ctrl *gomock.Controller) *MockNegativeCacheV2 {
+	mock := &MockNegativeCacheV2{ctrl: ctrl}
+	mock.recorder = &MockNegativeCacheV2MockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNegativeCacheV2) EXPECT() *MockNegativeCacheV2MockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockNegativeCacheV2) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockNegativeCacheV2MockRecorder) Delete(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNegativeCacheV2)(nil).Delete), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockNegativeCacheV2) Get(ctx context.Context, key string) (*models.CacheEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockNegativeCacheV2MockRecorder) Get(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockNegativeCacheV2)(nil).Get), ctx, key)
+}
+
+// GetEntry mocks base method.
+func (m *MockNegativeCacheV2) GetEntry(ctx context.Context, key string) (*models.CacheEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntry", ctx, key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntry indicates an expected call of GetEntry.
+func (mr *MockNegativeCacheV2MockRecorder) GetEntry(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockNegativeCacheV2)(nil).GetEntry), ctx, key)
+}
+
+// GetStale mocks base method.
+func (m *MockNegativeCacheV2) GetStale(ctx context.Context, key string) (*models.CacheEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStale", ctx, key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStale indicates an expected call of GetStale.
+func (mr *MockNegativeCacheV2MockRecorder) GetStale(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStale", reflect.TypeOf((*MockNegativeCacheV2)(nil).GetStale), ctx, key)
+}
+
+// GetStaleEntry mocks base method.
+func (m *MockNegativeCacheV2) GetStaleEntry(ctx context.Context, key string) (*models.CacheEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStaleEntry", ctx, key)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStaleEntry indicates an expected call of GetStaleEntry.
+func (mr *MockNegativeCacheV2MockRecorder) GetStaleEntry(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStaleEntry", reflect.TypeOf((*MockNegativeCacheV2)(nil).GetStaleEntry), ctx, key)
+}
+
+// Set mocks base method.
+func (m *MockNegativeCacheV2) Set(ctx context.Context, key string, val []byte, ttl models.TTL) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, key, val, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockNegativeCacheV2MockRecorder) Set(ctx, key, val, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockNegativeCacheV2)(nil).Set), ctx, key, val, ttl)
+}
+
+// SetNegative mocks base method.
+func (m *MockNegativeCacheV2) SetNegative(ctx context.Context, key string, errPayload []byte, ttl models.TTL) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNegative", ctx, key, errPayload, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNegative indicates an expected call of SetNegative.
+func (mr *MockNegativeCacheV2MockRecorder) SetNegative(ctx, key, errPayload, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNegative", reflect.TypeOf((*MockNegativeCacheV2)(nil).SetNegative), ctx, key, errPayload, ttl)
+}
+
+// MockSizeLimitedCacheV2 is a mock of SizeLimitedCacheV2 interface.
+type MockSizeLimitedCacheV2 struct {
+	ctrl     *gomock.Controller
+	recorder *MockSizeLimitedCacheV2MockRecorder
+	isgomock struct{}
+}
+
+// MockSizeLimitedCacheV2MockRecorder is the mock recorder for MockSizeLimitedCacheV2.
+type MockSizeLimitedCacheV2MockRecorder struct {
+	mock *MockSizeLimitedCacheV2
+}
+
+// NewMockSizeLimitedCacheV2 creates a new mock instance.
+func NewMockSizeLimitedCacheV2(ctrl *gomock.Controller) *MockSizeLimitedCacheV2 {
+	mock := &MockSizeLimitedCacheV2{ctrl: ctrl}
+	mock.recorder = &MockSizeLimitedCacheV2MockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSizeLimitedCacheV2) EXPECT() *MockSizeLimitedCacheV2MockRecorder {
+	return m.recorder
+}
+
+// WithMaxEntrySize mocks base method.
+func (m *MockSizeLimitedCacheV2) WithMaxEntrySize(size int) cache.CacheV2 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithMaxEntrySize", size)
+	ret0, _ := ret[0].(cache.CacheV2)
+	return ret0
+}
+
+// WithMaxEntrySize indicates an expected call of WithMaxEntrySize.
+func (mr *MockSizeLimitedCacheV2MockRecorder) WithMaxEntrySize(size any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithMaxEntrySize", reflect.TypeOf((*MockSizeLimitedCacheV2)(nil).WithMaxEntrySize), size)
+}
+
+// MockCodec is a mock of Codec interface.
+type MockCodec struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodecMockRecorder
+	isgomock struct{}
+}
+
+// MockCodecMockRecorder is the mock recorder for MockCodec.
+type MockCodecMockRecorder struct {
+	mock *MockCodec
+}
+
+// NewMockCodec creates a new mock instance.
+func NewMockCodec(ctrl *gomock.Controller) *MockCodec {
+	mock := &MockCodec{ctrl: ctrl}
+	mock.recorder = &MockCodecMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodec) EXPECT() *MockCodecMockRecorder {
+	return m.recorder
+}
+
+// Decode mocks base method.
+func (m *MockCodec) Decode(data []byte) (*models.CacheEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decode", data)
+	ret0, _ := ret[0].(*models.CacheEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decode indicates an expected call of Decode.
+func (mr *MockCodecMockRecorder) Decode(data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decode", reflect.TypeOf((*MockCodec)(nil).Decode), data)
+}
+
+// Encode mocks base method.
+func (m *MockCodec) Encode(entry *models.CacheEntry) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Encode", entry)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Encode indicates an expected call of Encode.
+func (mr *MockCodecMockRecorder) Encode(entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encode", reflect.TypeOf((*MockCodec)(nil).Encode), entry)
+}
+
 // MockKeyDbClient is a mock of KeyDbClient interface.
 type MockKeyDbClient struct {
 	ctrl     *gomock.Controller
-	recorder *MockKeyDbClientMockRecorder
+	recorder *MockKeyDbClientMockRecorder
+	isgomock struct{}
+}
+
+// MockKeyDbClientMockRecorder is the mock recorder for MockKeyDbClient.
+type MockKeyDbClientMockRecorder struct {
+	mock *MockKeyDbClient
+}
+
+// NewMockKeyDbClient creates a new mock instance.
+func NewMockKeyDbClient(ctrl *gomock.Controller) *MockKeyDbClient {
+	mock := &MockKeyDbClient{ctrl: ctrl}
+	mock.recorder = &MockKeyDbClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyDbClient) EXPECT() *MockKeyDbClientMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockKeyDbClient) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockKeyDbClientMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockKeyDbClient)(nil).Close))
+}
+
+// Del mocks base method.
+func (m *MockKeyDbClient) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Del", varargs...)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// Del indicates an expected call of Del.
+func (mr *MockKeyDbClientMockRecorder) Del(ctx any, keys ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockKeyDbClient)(nil).Del), varargs...)
+}
+
+// Get mocks base method.
+func (m *MockKeyDbClient) Get(ctx context.Context, key string) *redis.StringCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(*redis.StringCmd)
+	return ret0
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockKeyDbClientMockRecorder) Get(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockKeyDbClient)(nil).Get), ctx, key)
+}
+
+// Ping mocks base method.
+func (m *MockKeyDbClient) Ping(ctx context.Context) *redis.StatusCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(*redis.StatusCmd)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockKeyDbClientMockRecorder) Ping(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockKeyDbClient)(nil).Ping), ctx)
+}
+
+// Set mocks base method.
+func (m *MockKeyDbClient) Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, key, value, expiration)
+	ret0, _ := ret[0].(*redis.StatusCmd)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockKeyDbClientMockRecorder) Set(ctx, key, value, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockKeyDbClient)(nil).Set), ctx, key, value, expiration)
+}
+
+// MockExtendedKeyDbClient is a mock of ExtendedKeyDbClient interface.
+type MockExtendedKeyDbClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockExtendedKeyDbClientMockRecorder
 	isgomock struct{}
 }
 
-// MockKeyDbClientMockRecorder is the mock recorder for MockKeyDbClient.
-type MockKeyDbClientMockRecorder struct {
-	mock *MockKeyDbClient
+// MockExtendedKeyDbClientMockRecorder is the mock recorder for MockExtendedKeyDbClient.
+type MockExtendedKeyDbClientMockRecorder struct {
+	mock *MockExtendedKeyDbClient
 }
 
-// NewMockKeyDbClient creates a new mock instance.
-func NewMockKeyDbClient(ctrl *gomock.Controller) *MockKeyDbClient {
-	mock := &MockKeyDbClient{ctrl: ctrl}
-	mock.recorder = &MockKeyDbClientMockRecorder{mock}
+// NewMockExtendedKeyDbClient creates a new mock instance.
+func NewMockExtendedKeyDbClient(ctrl *gomock.Controller) *MockExtendedKeyDbClient {
+	mock := &MockExtendedKeyDbClient{ctrl: ctrl}
+	mock.recorder = &MockExtendedKeyDbClientMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockKeyDbClient) EXPECT() *MockKeyDbClientMockRecorder {
+func (m *MockExtendedKeyDbClient) EXPECT() *MockExtendedKeyDbClientMockRecorder {
 	return m.recorder
 }
 
 // Close mocks base method.
-func (m *MockKeyDbClient) Close() error {
+func (m *MockExtendedKeyDbClient) Close() error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Close")
 	ret0, _ := ret[0].(error)
@@ -236,13 +1659,13 @@ func (m *MockKeyDbClient) Close() error {
 }
 
 // Close indicates an expected call of Close.
-func (mr *MockKeyDbClientMockRecorder) Close() *gomock.Call {
+func (mr *MockExtendedKeyDbClientMockRecorder) Close() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockKeyDbClient)(nil).Close))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockExtendedKeyDbClient)(nil).Close))
 }
 
 // Del mocks base method.
-func (m *MockKeyDbClient) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+func (m *MockExtendedKeyDbClient) Del(ctx context.Context, keys ...string) *redis.IntCmd {
 	m.ctrl.T.Helper()
 	varargs := []any{ctx}
 	for _, a := range keys {
@@ -254,14 +1677,14 @@ func (m *MockKeyDbClient) Del(ctx context.Context, keys ...string) *redis.IntCmd
 }
 
 // Del indicates an expected call of Del.
-func (mr *MockKeyDbClientMockRecorder) Del(ctx any, keys ...any) *gomock.Call {
+func (mr *MockExtendedKeyDbClientMockRecorder) Del(ctx any, keys ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]any{ctx}, keys...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockKeyDbClient)(nil).Del), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockExtendedKeyDbClient)(nil).Del), varargs...)
 }
 
 // Get mocks base method.
-func (m *MockKeyDbClient) Get(ctx context.Context, key string) *redis.StringCmd {
+func (m *MockExtendedKeyDbClient) Get(ctx context.Context, key string) *redis.StringCmd {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Get", ctx, key)
 	ret0, _ := ret[0].(*redis.StringCmd)
@@ -269,13 +1692,32 @@ func (m *MockKeyDbClient) Get(ctx context.Context, key string) *redis.StringCmd
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockKeyDbClientMockRecorder) Get(ctx, key any) *gomock.Call {
+func (mr *MockExtendedKeyDbClientMockRecorder) Get(ctx, key any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockKeyDbClient)(nil).Get), ctx, key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockExtendedKeyDbClient)(nil).Get), ctx, key)
+}
+
+// MGet mocks base method.
+func (m *MockExtendedKeyDbClient) MGet(ctx context.Context, keys ...string) *redis.SliceCmd {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MGet", varargs...)
+	ret0, _ := ret[0].(*redis.SliceCmd)
+	return ret0
+}
+
+// MGet indicates an expected call of MGet.
+func (mr *MockExtendedKeyDbClientMockRecorder) MGet(ctx any, keys ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MGet", reflect.TypeOf((*MockExtendedKeyDbClient)(nil).MGet), varargs...)
 }
 
 // Ping mocks base method.
-func (m *MockKeyDbClient) Ping(ctx context.Context) *redis.StatusCmd {
+func (m *MockExtendedKeyDbClient) Ping(ctx context.Context) *redis.StatusCmd {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Ping", ctx)
 	ret0, _ := ret[0].(*redis.StatusCmd)
@@ -283,13 +1725,69 @@ func (m *MockKeyDbClient) Ping(ctx context.Context) *redis.StatusCmd {
 }
 
 // Ping indicates an expected call of Ping.
-func (mr *MockKeyDbClientMockRecorder) Ping(ctx any) *gomock.Call {
+func (mr *MockExtendedKeyDbClientMockRecorder) Ping(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockKeyDbClient)(nil).Ping), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockExtendedKeyDbClient)(nil).Ping), ctx)
+}
+
+// Pipeline mocks base method.
+func (m *MockExtendedKeyDbClient) Pipeline() redis.Pipeliner {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pipeline")
+	ret0, _ := ret[0].(redis.Pipeliner)
+	return ret0
+}
+
+// Pipeline indicates an expected call of Pipeline.
+func (mr *MockExtendedKeyDbClientMockRecorder) Pipeline() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pipeline", reflect.TypeOf((*MockExtendedKeyDbClient)(nil).Pipeline))
+}
+
+// Publish mocks base method.
+func (m *MockExtendedKeyDbClient) Publish(ctx context.Context, channel string, message any) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, channel, message)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockExtendedKeyDbClientMockRecorder) Publish(ctx, channel, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockExtendedKeyDbClient)(nil).Publish), ctx, channel, message)
+}
+
+// SMembers mocks base method.
+func (m *MockExtendedKeyDbClient) SMembers(ctx context.Context, key string) *redis.StringSliceCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SMembers", ctx, key)
+	ret0, _ := ret[0].(*redis.StringSliceCmd)
+	return ret0
+}
+
+// SMembers indicates an expected call of SMembers.
+func (mr *MockExtendedKeyDbClientMockRecorder) SMembers(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SMembers", reflect.TypeOf((*MockExtendedKeyDbClient)(nil).SMembers), ctx, key)
+}
+
+// Scan mocks base method.
+func (m *MockExtendedKeyDbClient) Scan(ctx context.Context, cursor uint64, match string, count int64) *redis.ScanCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scan", ctx, cursor, match, count)
+	ret0, _ := ret[0].(*redis.ScanCmd)
+	return ret0
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockExtendedKeyDbClientMockRecorder) Scan(ctx, cursor, match, count any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockExtendedKeyDbClient)(nil).Scan), ctx, cursor, match, count)
 }
 
 // Set mocks base method.
-func (m *MockKeyDbClient) Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd {
+func (m *MockExtendedKeyDbClient) Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Set", ctx, key, value, expiration)
 	ret0, _ := ret[0].(*redis.StatusCmd)
@@ -297,9 +1795,28 @@ func (m *MockKeyDbClient) Set(ctx context.Context, key string, value any, expira
 }
 
 // Set indicates an expected call of Set.
-func (mr *MockKeyDbClientMockRecorder) Set(ctx, key, value, expiration any) *gomock.Call {
+func (mr *MockExtendedKeyDbClientMockRecorder) Set(ctx, key, value, expiration any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockKeyDbClient)(nil).Set), ctx, key, value, expiration)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockExtendedKeyDbClient)(nil).Set), ctx, key, value, expiration)
+}
+
+// Subscribe mocks base method.
+func (m *MockExtendedKeyDbClient) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range channels {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Subscribe", varargs...)
+	ret0, _ := ret[0].(*redis.PubSub)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockExtendedKeyDbClientMockRecorder) Subscribe(ctx any, channels ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, channels...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockExtendedKeyDbClient)(nil).Subscribe), varargs...)
 }
 
 // MockLogger is a mock of Logger interface.
@@ -515,3 +2032,291 @@ func (mr *MockMetricsRecorderMockRecorder) UpdateL1CacheCapacity(capacity, used
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateL1CacheCapacity", reflect.TypeOf((*MockMetricsRecorder)(nil).UpdateL1CacheCapacity), capacity, used)
 }
+
+// MockCoalescingMetricsRecorder is a mock of CoalescingMetricsRecorder interface.
+type MockCoalescingMetricsRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockCoalescingMetricsRecorderMockRecorder
+	isgomock struct{}
+}
+
+// MockCoalescingMetricsRecorderMockRecorder is the mock recorder for MockCoalescingMetricsRecorder.
+type MockCoalescingMetricsRecorderMockRecorder struct {
+	mock *MockCoalescingMetricsRecorder
+}
+
+// NewMockCoalescingMetricsRecorder creates a new mock instance.
+func NewMockCoalescingMetricsRecorder(ctrl *gomock.Controller) *MockCoalescingMetricsRecorder {
+	mock := &MockCoalescingMetricsRecorder{ctrl: ctrl}
+	mock.recorder = &MockCoalescingMetricsRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCoalescingMetricsRecorder) EXPECT() *MockCoalescingMetricsRecorderMockRecorder {
+	return m.recorder
+}
+
+// RecordCacheCoalesced mocks base method.
+func (m *MockCoalescingMetricsRecorder) RecordCacheCoalesced(operation string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordCacheCoalesced", operation)
+}
+
+// RecordCacheCoalesced indicates an expected call of RecordCacheCoalesced.
+func (mr *MockCoalescingMetricsRecorderMockRecorder) RecordCacheCoalesced(operation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordCacheCoalesced", reflect.TypeOf((*MockCoalescingMetricsRecorder)(nil).RecordCacheCoalesced), operation)
+}
+
+// MockCompressionMetricsRecorder is a mock of CompressionMetricsRecorder interface.
+type MockCompressionMetricsRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockCompressionMetricsRecorderMockRecorder
+	isgomock struct{}
+}
+
+// MockCompressionMetricsRecorderMockRecorder is the mock recorder for MockCompressionMetricsRecorder.
+type MockCompressionMetricsRecorderMockRecorder struct {
+	mock *MockCompressionMetricsRecorder
+}
+
+// NewMockCompressionMetricsRecorder creates a new mock instance.
+func NewMockCompressionMetricsRecorder(ctrl *gomock.Controller) *MockCompressionMetricsRecorder {
+	mock := &MockCompressionMetricsRecorder{ctrl: ctrl}
+	mock.recorder = &MockCompressionMetricsRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCompressionMetricsRecorder) EXPECT() *MockCompressionMetricsRecorderMockRecorder {
+	return m.recorder
+}
+
+// RecordCacheCompression mocks base method.
+func (m *MockCompressionMetricsRecorder) RecordCacheCompression(level, algorithm string, rawBytes, compressedBytes int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordCacheCompression", level, algorithm, rawBytes, compressedBytes)
+}
+
+// RecordCacheCompression indicates an expected call of RecordCacheCompression.
+func (mr *MockCompressionMetricsRecorderMockRecorder) RecordCacheCompression(level, algorithm, rawBytes, compressedBytes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordCacheCompression", reflect.TypeOf((*MockCompressionMetricsRecorder)(nil).RecordCacheCompression), level, algorithm, rawBytes, compressedBytes)
+}
+
+// MockTTLMetricsRecorder is a mock of TTLMetricsRecorder interface.
+type MockTTLMetricsRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockTTLMetricsRecorderMockRecorder
+	isgomock struct{}
+}
+
+// MockTTLMetricsRecorderMockRecorder is the mock recorder for MockTTLMetricsRecorder.
+type MockTTLMetricsRecorderMockRecorder struct {
+	mock *MockTTLMetricsRecorder
+}
+
+// NewMockTTLMetricsRecorder creates a new mock instance.
+func NewMockTTLMetricsRecorder(ctrl *gomock.Controller) *MockTTLMetricsRecorder {
+	mock := &MockTTLMetricsRecorder{ctrl: ctrl}
+	mock.recorder = &MockTTLMetricsRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTTLMetricsRecorder) EXPECT() *MockTTLMetricsRecorderMockRecorder {
+	return m.recorder
+}
+
+// RecordCacheTTLAdjusted mocks base method.
+func (m *MockTTLMetricsRecorder) RecordCacheTTLAdjusted(level, reason string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordCacheTTLAdjusted", level, reason)
+}
+
+// RecordCacheTTLAdjusted indicates an expected call of RecordCacheTTLAdjusted.
+func (mr *MockTTLMetricsRecorderMockRecorder) RecordCacheTTLAdjusted(level, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordCacheTTLAdjusted", reflect.TypeOf((*MockTTLMetricsRecorder)(nil).RecordCacheTTLAdjusted), level, reason)
+}
+
+// MockL1StatsMetricsRecorder is a mock of L1StatsMetricsRecorder interface.
+type MockL1StatsMetricsRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockL1StatsMetricsRecorderMockRecorder
+	isgomock struct{}
+}
+
+// MockL1StatsMetricsRecorderMockRecorder is the mock recorder for MockL1StatsMetricsRecorder.
+type MockL1StatsMetricsRecorderMockRecorder struct {
+	mock *MockL1StatsMetricsRecorder
+}
+
+// NewMockL1StatsMetricsRecorder creates a new mock instance.
+func NewMockL1StatsMetricsRecorder(ctrl *gomock.Controller) *MockL1StatsMetricsRecorder {
+	mock := &MockL1StatsMetricsRecorder{ctrl: ctrl}
+	mock.recorder = &MockL1StatsMetricsRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockL1StatsMetricsRecorder) EXPECT() *MockL1StatsMetricsRecorderMockRecorder {
+	return m.recorder
+}
+
+// RecordCacheEviction mocks base method.
+func (m *MockL1StatsMetricsRecorder) RecordCacheEviction(level, cacheType, chain, network string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordCacheEviction", level, cacheType, chain, network)
+}
+
+// RecordCacheEviction indicates an expected call of RecordCacheEviction.
+func (mr *MockL1StatsMetricsRecorderMockRecorder) RecordCacheEviction(level, cacheType, chain, network any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordCacheEviction", reflect.TypeOf((*MockL1StatsMetricsRecorder)(nil).RecordCacheEviction), level, cacheType, chain, network)
+}
+
+// RecordCacheEvictionReason mocks base method.
+func (m *MockL1StatsMetricsRecorder) RecordCacheEvictionReason(level, reason string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordCacheEvictionReason", level, reason)
+}
+
+// RecordCacheEvictionReason indicates an expected call of RecordCacheEvictionReason.
+func (mr *MockL1StatsMetricsRecorderMockRecorder) RecordCacheEvictionReason(level, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordCacheEvictionReason", reflect.TypeOf((*MockL1StatsMetricsRecorder)(nil).RecordCacheEvictionReason), level, reason)
+}
+
+// RecordL1CacheStats mocks base method.
+func (m *MockL1StatsMetricsRecorder) RecordL1CacheStats(collisions, deleteHits int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordL1CacheStats", collisions, deleteHits)
+}
+
+// RecordL1CacheStats indicates an expected call of RecordL1CacheStats.
+func (mr *MockL1StatsMetricsRecorderMockRecorder) RecordL1CacheStats(collisions, deleteHits any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordL1CacheStats", reflect.TypeOf((*MockL1StatsMetricsRecorder)(nil).RecordL1CacheStats), collisions, deleteHits)
+}
+
+// MockNegativeCacheMetricsRecorder is a mock of NegativeCacheMetricsRecorder interface.
+type MockNegativeCacheMetricsRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockNegativeCacheMetricsRecorderMockRecorder
+	isgomock struct{}
+}
+
+// MockNegativeCacheMetricsRecorderMockRecorder is the mock recorder for MockNegativeCacheMetricsRecorder.
+type MockNegativeCacheMetricsRecorderMockRecorder struct {
+	mock *MockNegativeCacheMetricsRecorder
+}
+
+// NewMockNegativeCacheMetricsRecorder creates a new mock instance.
+func NewMockNegativeCacheMetricsRecorder(ctrl *gomock.Controller) *MockNegativeCacheMetricsRecorder {
+	mock := &MockNegativeCacheMetricsRecorder{ctrl: ctrl}
+	mock.recorder = &MockNegativeCacheMetricsRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNegativeCacheMetricsRecorder) EXPECT() *MockNegativeCacheMetricsRecorderMockRecorder {
+	return m.recorder
+}
+
+// RecordCacheNegativeHit mocks base method.
+func (m *MockNegativeCacheMetricsRecorder) RecordCacheNegativeHit(cacheType, level, chain, network, rpcMethod string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordCacheNegativeHit", cacheType, level, chain, network, rpcMethod)
+}
+
+// RecordCacheNegativeHit indicates an expected call of RecordCacheNegativeHit.
+func (mr *MockNegativeCacheMetricsRecorderMockRecorder) RecordCacheNegativeHit(cacheType, level, chain, network, rpcMethod any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordCacheNegativeHit", reflect.TypeOf((*MockNegativeCacheMetricsRecorder)(nil).RecordCacheNegativeHit), cacheType, level, chain, network, rpcMethod)
+}
+
+// MockCircuitBreakerMetricsRecorder is a mock of CircuitBreakerMetricsRecorder interface.
+type MockCircuitBreakerMetricsRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockCircuitBreakerMetricsRecorderMockRecorder
+	isgomock struct{}
+}
+
+// MockCircuitBreakerMetricsRecorderMockRecorder is the mock recorder for MockCircuitBreakerMetricsRecorder.
+type MockCircuitBreakerMetricsRecorderMockRecorder struct {
+	mock *MockCircuitBreakerMetricsRecorder
+}
+
+// NewMockCircuitBreakerMetricsRecorder creates a new mock instance.
+func NewMockCircuitBreakerMetricsRecorder(ctrl *gomock.Controller) *MockCircuitBreakerMetricsRecorder {
+	mock := &MockCircuitBreakerMetricsRecorder{ctrl: ctrl}
+	mock.recorder = &MockCircuitBreakerMetricsRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCircuitBreakerMetricsRecorder) EXPECT() *MockCircuitBreakerMetricsRecorderMockRecorder {
+	return m.recorder
+}
+
+// UpdateCircuitBreakerState mocks base method.
+func (m *MockCircuitBreakerMetricsRecorder) UpdateCircuitBreakerState(level string, state cache.CircuitState) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateCircuitBreakerState", level, state)
+}
+
+// UpdateCircuitBreakerState indicates an expected call of UpdateCircuitBreakerState.
+func (mr *MockCircuitBreakerMetricsRecorderMockRecorder) UpdateCircuitBreakerState(level, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCircuitBreakerState", reflect.TypeOf((*MockCircuitBreakerMetricsRecorder)(nil).UpdateCircuitBreakerState), level, state)
+}
+
+// MockWriteBehindMetricsRecorder is a mock of WriteBehindMetricsRecorder interface.
+type MockWriteBehindMetricsRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockWriteBehindMetricsRecorderMockRecorder
+	isgomock struct{}
+}
+
+// MockWriteBehindMetricsRecorderMockRecorder is the mock recorder for MockWriteBehindMetricsRecorder.
+type MockWriteBehindMetricsRecorderMockRecorder struct {
+	mock *MockWriteBehindMetricsRecorder
+}
+
+// NewMockWriteBehindMetricsRecorder creates a new mock instance.
+func NewMockWriteBehindMetricsRecorder(ctrl *gomock.Controller) *MockWriteBehindMetricsRecorder {
+	mock := &MockWriteBehindMetricsRecorder{ctrl: ctrl}
+	mock.recorder = &MockWriteBehindMetricsRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWriteBehindMetricsRecorder) EXPECT() *MockWriteBehindMetricsRecorderMockRecorder {
+	return m.recorder
+}
+
+// RecordWriteDropped mocks base method.
+func (m *MockWriteBehindMetricsRecorder) RecordWriteDropped(level, reason string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordWriteDropped", level, reason)
+}
+
+// RecordWriteDropped indicates an expected call of RecordWriteDropped.
+func (mr *MockWriteBehindMetricsRecorderMockRecorder) RecordWriteDropped(level, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWriteDropped", reflect.TypeOf((*MockWriteBehindMetricsRecorder)(nil).RecordWriteDropped), level, reason)
+}
+
+// UpdateWriteQueueDepth mocks base method.
+func (m *MockWriteBehindMetricsRecorder) UpdateWriteQueueDepth(level string, depth int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateWriteQueueDepth", level, depth)
+}
+
+// UpdateWriteQueueDepth indicates an expected call of UpdateWriteQueueDepth.
+func (mr *MockWriteBehindMetricsRecorderMockRecorder) UpdateWriteQueueDepth(level, depth any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWriteQueueDepth", reflect.TypeOf((*MockWriteBehindMetricsRecorder)(nil).UpdateWriteQueueDepth), level, depth)
+}
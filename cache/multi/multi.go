@@ -1,22 +1,52 @@
 package multi
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"slices"
+	"sync"
+	"time"
+
 	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/clock"
 	"github.com/status-im/proxy-common/models"
 )
 
-// Ensure MultiCache implements cache.Cache and cache.LevelAwareCache
+// Ensure MultiCache implements cache.Cache, cache.LevelAwareCache, cache.TaggedCache
+// and cache.LevelAwareBatchCache
 var _ cache.Cache = (*MultiCache)(nil)
 var _ cache.LevelAwareCache = (*MultiCache)(nil)
+var _ cache.BindableCache = (*MultiCache)(nil)
+var _ cache.TaggedCache = (*MultiCache)(nil)
+var _ cache.LevelAwareBatchCache = (*MultiCache)(nil)
+var _ cache.NegativeCache = (*MultiCache)(nil)
+var _ cache.NegativeBatchCache = (*MultiCache)(nil)
 
 // MultiCache implements a composite cache that tries multiple cache implementations
-// It attempts to get/set values through an array of cache interfaces in order
+// It attempts to get/set values through an array of cache interfaces in order.
+// Levels implementing cache.HealthChecker are skipped while they are not Available,
+// except by deletes, which they record and replay once they recover. A negative write
+// deletes the key from such a level instead.
+// Writes, including propagation, skip levels whose cache.LevelPolicy does not accept
+// the entry, and reads skip levels whose policy does not read the cache type. Levels
+// implementing cache.SizeLimitedCache check the policy's MaxEntrySize against the
+// encoded entry themselves.
 type MultiCache struct {
 	caches            []cache.Cache
 	logger            cache.Logger
 	enablePropagation bool
+	policies          []cache.LevelPolicy
+	bus               cache.InvalidationBus
+	publishTimeout    time.Duration
+	unsubscribe       func()
+	instanceID        string
+	clock             clock.Clock
 }
 
+// defaultPublishTimeout bounds invalidation publishes, matching the default KeyDB send timeout
+const defaultPublishTimeout = time.Second
+
 // Option is a functional option for configuring MultiCache
 type Option func(*MultiCache)
 
@@ -27,46 +57,256 @@ func WithLogger(logger cache.Logger) Option {
 	}
 }
 
+// WithInvalidationBus broadcasts deletes and invalidations over bus and applies those
+// published by other instances to the first cache level. Later levels are assumed to
+// be shared between instances, so they are not touched by remote messages.
+func WithInvalidationBus(bus cache.InvalidationBus) Option {
+	return func(mc *MultiCache) {
+		mc.bus = bus
+	}
+}
+
+// WithPublishTimeout bounds each publish on the invalidation bus, e.g. by the L2 send
+// timeout when the bus runs on the same KeyDB. It defaults to one second.
+func WithPublishTimeout(timeout time.Duration) Option {
+	return func(mc *MultiCache) {
+		mc.publishTimeout = timeout
+	}
+}
+
+// WithClock sets the clock used to compute the remaining TTL of propagated entries
+func WithClock(c clock.Clock) Option {
+	return func(mc *MultiCache) {
+		mc.clock = c
+	}
+}
+
+// WithLevelPolicies sets the read and write policy of each level, in level order. Levels
+// without a policy serve every read and accept every entry. Cache types come from the
+// view returned by WithContext or ForCacheType; calls made on MultiCache itself carry no
+// cache type, so only the policies' type-independent settings apply to them.
+// NewMultiCacheFromConfig sets the policies from cache.MultiCacheConfig.Levels.
+func WithLevelPolicies(policies []cache.LevelPolicy) Option {
+	return func(mc *MultiCache) {
+		mc.policies = policies
+	}
+}
+
 // NewMultiCache creates a new MultiCache instance with provided cache implementations
-func NewMultiCache(caches []cache.Cache, enablePropagation bool, opts ...Option) cache.LevelAwareCache {
+func NewMultiCache(caches []cache.Cache, enablePropagation bool, opts ...Option) cache.BindableCache {
 	mc := &MultiCache{
 		caches:            caches,
 		logger:            cache.NoopLogger{},
 		enablePropagation: enablePropagation,
+		publishTimeout:    defaultPublishTimeout,
+		clock:             clock.Real{},
 	}
 
 	for _, opt := range opts {
 		opt(mc)
 	}
 
+	checkPolicies(mc.logger, mc.policies, len(caches))
+	mc.caches, mc.policies = limitEntrySizes(caches, mc.policies, func(c cache.Cache, size int) (cache.Cache, bool) {
+		if sl, ok := c.(cache.SizeLimitedCache); ok {
+			return sl.WithMaxEntrySize(size), true
+		}
+		return c, false
+	})
+
+	if mc.bus != nil {
+		mc.instanceID = newInstanceID()
+		mc.unsubscribe = sync.OnceFunc(mc.bus.Subscribe(mc.handleInvalidation))
+	}
+
 	return mc
 }
 
-// Get retrieves value from the first available cache that has the key
+// NewMultiCacheFromConfig creates a new MultiCache with the propagation setting and level
+// policies of cfg. Options are applied after cfg, so WithLevelPolicies overrides its Levels.
+func NewMultiCacheFromConfig(cfg *cache.MultiCacheConfig, caches []cache.Cache, opts ...Option) cache.BindableCache {
+	return NewMultiCache(caches, cfg.EnablePropagation, append([]Option{WithLevelPolicies(cfg.Levels)}, opts...)...)
+}
+
+// checkPolicies logs policies that cannot apply as configured
+func checkPolicies(logger cache.Logger, policies []cache.LevelPolicy, levels int) {
+	if len(policies) > levels {
+		logger.Warn("More level policies than cache levels, ignoring the extra policies",
+			"policies", len(policies), "levels", levels)
+	}
+	for i, p := range policies {
+		if len(p.SkipCacheTypes) > 0 || len(p.SkipReadCacheTypes) > 0 {
+			logger.Info("Level policy skips cache types, which only applies to calls carrying a cache type",
+				"level", models.CacheLevelFromIndex(i))
+		}
+	}
+}
+
+// limitEntrySizes replaces each level whose policy sets a MaxEntrySize with the view
+// returned by limit, if it has one, so the limit is checked against the encoded entry.
+// The limit is then cleared from the returned policies, which are a copy.
+func limitEntrySizes[C any](caches []C, policies []cache.LevelPolicy, limit func(C, int) (C, bool)) ([]C, []cache.LevelPolicy) {
+	caches = slices.Clone(caches)
+	policies = slices.Clone(policies)
+	for i := range min(len(caches), len(policies)) {
+		if policies[i].MaxEntrySize <= 0 {
+			continue
+		}
+		if limited, ok := limit(caches[i], policies[i].MaxEntrySize); ok {
+			caches[i] = limited
+			policies[i].MaxEntrySize = 0
+		}
+	}
+	return caches, policies
+}
+
+// Close unsubscribes from the invalidation bus, if one is configured. The bus and the
+// cache levels are left open for their owners to close.
+func (mc *MultiCache) Close() error {
+	if mc.unsubscribe != nil {
+		mc.unsubscribe()
+	}
+	return nil
+}
+
+// WithContext returns a view of the cache whose reads and writes apply the level
+// policies for the cache type in the RequestLabels stored in ctx
+func (mc *MultiCache) WithContext(ctx context.Context) cache.LevelAwareCache {
+	return mc.ForCacheType(cache.RequestLabelsFromContext(ctx).CacheType)
+}
+
+// ForCacheType returns a view of the cache whose reads and writes apply the level
+// policies for cacheType
+func (mc *MultiCache) ForCacheType(cacheType models.CacheType) cache.LevelAwareCache {
+	return &boundMultiCache{MultiCache: mc, cacheType: cacheType}
+}
+
+// reads reports whether the policy of level i lets it serve reads for cacheType
+func (mc *MultiCache) reads(i int, cacheType models.CacheType) bool {
+	return i >= len(mc.policies) || mc.policies[i].Reads(cacheType)
+}
+
+// accepts reports whether the policy of level i accepts an entry of size bytes for cacheType
+func (mc *MultiCache) accepts(i int, cacheType models.CacheType, size int) bool {
+	return i >= len(mc.policies) || mc.policies[i].Accepts(cacheType, size)
+}
+
+// acceptedItems returns the items the policy of level i accepts for cacheType
+func (mc *MultiCache) acceptedItems(i int, cacheType models.CacheType, items []cache.BatchItem) []cache.BatchItem {
+	if i >= len(mc.policies) {
+		return items
+	}
+
+	accepted := make([]cache.BatchItem, 0, len(items))
+	for _, item := range items {
+		if mc.policies[i].Accepts(cacheType, len(item.Val)) {
+			accepted = append(accepted, item)
+		}
+	}
+	return accepted
+}
+
+// Get retrieves value from the first available cache that has the key. A negative
+// entry is reported as a miss.
 func (mc *MultiCache) Get(key string) (*models.CacheEntry, bool) {
-	result := mc.GetWithLevel(key)
-	return result.Entry, result.Found
+	return positive(mc.GetWithLevel(key))
 }
 
-// GetStale retrieves stale value from the first available cache that has the key
+// GetStale retrieves stale value from the first available cache that has the key. A
+// negative entry is reported as a miss.
 func (mc *MultiCache) GetStale(key string) (*models.CacheEntry, bool) {
-	result := mc.GetStaleWithLevel(key)
-	return result.Entry, result.Found
+	return positive(mc.GetStaleWithLevel(key))
+}
+
+// GetEntry retrieves a positive or negative entry from the first available cache that has the key
+func (mc *MultiCache) GetEntry(key string) (*models.CacheEntry, bool) {
+	return anyEntry(mc.GetWithLevel(key))
+}
+
+// GetStaleEntry retrieves a stale positive or negative entry from the first available
+// cache that has the key
+func (mc *MultiCache) GetStaleEntry(key string) (*models.CacheEntry, bool) {
+	return anyEntry(mc.GetStaleWithLevel(key))
+}
+
+// positive returns the entry of a result holding a positive entry
+func positive(result *models.CacheResult) (*models.CacheEntry, bool) {
+	if !result.Found {
+		return nil, false
+	}
+	return result.Entry, true
+}
+
+// anyEntry returns the entry of a result holding a positive or negative entry
+func anyEntry(result *models.CacheResult) (*models.CacheEntry, bool) {
+	if !result.Found && !result.IsNegative() {
+		return nil, false
+	}
+	return result.Entry, true
 }
 
 // Set stores value in all available caches
 func (mc *MultiCache) Set(key string, val []byte, ttl models.TTL) {
+	mc.set(key, val, ttl, "")
+}
+
+func (mc *MultiCache) set(key string, val []byte, ttl models.TTL, cacheType models.CacheType) {
 	if len(mc.caches) == 0 {
 		mc.logger.Warn("No caches available for set operation", "key", key)
 		return
 	}
 
-	for _, c := range mc.caches {
+	for i, c := range mc.caches {
+		if !available(c) || !mc.accepts(i, cacheType, len(val)) {
+			continue
+		}
 		c.Set(key, val, ttl)
 	}
 }
 
-// Delete removes entry from all available caches
+// SetNegative stores a negative entry in all available caches. Levels that do not
+// implement cache.NegativeCache drop the key instead, so they cannot serve an older value.
+func (mc *MultiCache) SetNegative(key string, errPayload []byte, ttl models.TTL) {
+	mc.setNegative(key, errPayload, ttl, "")
+}
+
+func (mc *MultiCache) setNegative(key string, errPayload []byte, ttl models.TTL, cacheType models.CacheType) {
+	if len(mc.caches) == 0 {
+		mc.logger.Warn("No caches available for set operation", "key", key)
+		return
+	}
+
+	for i, c := range mc.caches {
+		if !mc.accepts(i, cacheType, len(errPayload)) {
+			continue
+		}
+		if !available(c) {
+			// The level cannot take the entry, but must not keep the one it replaces
+			c.Delete(key)
+			continue
+		}
+		setNegative(c, key, errPayload, ttl)
+	}
+}
+
+// available reports whether c accepts requests; levels that do not implement
+// cache.HealthChecker are always available
+func available(c any) bool {
+	hc, ok := c.(cache.HealthChecker)
+	return !ok || hc.Available()
+}
+
+// setNegative uses the level's negative write if it has one, falling back to Delete
+func setNegative(c cache.Cache, key string, errPayload []byte, ttl models.TTL) {
+	if nc, ok := c.(cache.NegativeCache); ok {
+		nc.SetNegative(key, errPayload, ttl)
+		return
+	}
+	c.Delete(key)
+}
+
+// Delete removes entry from all caches, including unavailable ones, which replay the
+// delete once they recover
 func (mc *MultiCache) Delete(key string) {
 	if len(mc.caches) == 0 {
 		mc.logger.Warn("No caches available for delete operation", "key", key)
@@ -76,6 +316,315 @@ func (mc *MultiCache) Delete(key string) {
 	for _, c := range mc.caches {
 		c.Delete(key)
 	}
+
+	mc.publish(cache.InvalidationMessage{Keys: []string{key}})
+}
+
+// GetMany retrieves several values, returning only the keys found in some level with a
+// positive entry
+func (mc *MultiCache) GetMany(keys []string) map[string]*models.CacheEntry {
+	return mc.getMany(keys, "", positive)
+}
+
+// GetManyEntries retrieves several positive or negative entries, returning only the
+// keys found in some level
+func (mc *MultiCache) GetManyEntries(keys []string) map[string]*models.CacheEntry {
+	return mc.getMany(keys, "", anyEntry)
+}
+
+// getMany returns the entries of the results that pick accepts
+func (mc *MultiCache) getMany(
+	keys []string,
+	cacheType models.CacheType,
+	pick func(*models.CacheResult) (*models.CacheEntry, bool),
+) map[string]*models.CacheEntry {
+	results := mc.getManyWithLevel(keys, cacheType)
+
+	entries := make(map[string]*models.CacheEntry, len(results))
+	for key, result := range results {
+		if entry, ok := pick(result); ok {
+			entries[key] = entry
+		}
+	}
+	return entries
+}
+
+// SetMany stores several values in all available caches
+func (mc *MultiCache) SetMany(items []cache.BatchItem) {
+	mc.setMany(items, "")
+}
+
+func (mc *MultiCache) setMany(items []cache.BatchItem, cacheType models.CacheType) {
+	if len(mc.caches) == 0 {
+		mc.logger.Warn("No caches available for set operation", "keys", len(items))
+		return
+	}
+
+	for i, c := range mc.caches {
+		if !available(c) {
+			continue
+		}
+		if accepted := mc.acceptedItems(i, cacheType, items); len(accepted) > 0 {
+			setMany(c, accepted)
+		}
+	}
+}
+
+// GetManyWithLevel retrieves several values with per-key level information. Each level
+// is queried once for the keys still missing, and hits from later levels are
+// propagated to earlier levels in a single batch per level.
+func (mc *MultiCache) GetManyWithLevel(keys []string) map[string]*models.CacheResult {
+	return mc.getManyWithLevel(keys, "")
+}
+
+func (mc *MultiCache) getManyWithLevel(keys []string, cacheType models.CacheType) map[string]*models.CacheResult {
+	results := make(map[string]*models.CacheResult, len(keys))
+	if len(mc.caches) == 0 {
+		mc.logger.Warn("No caches available for get operation", "keys", len(keys))
+	}
+
+	remaining := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if _, ok := results[key]; !ok {
+			results[key] = &models.CacheResult{Level: models.CacheLevelMiss}
+			remaining = append(remaining, key)
+		}
+	}
+
+	now := mc.clock.Now()
+	for i, c := range mc.caches {
+		if len(remaining) == 0 {
+			break
+		}
+		if !available(c) || !mc.reads(i, cacheType) {
+			continue
+		}
+
+		entries := getManyEntries(c, remaining)
+		level := models.CacheLevelFromIndex(i)
+
+		var missing []string
+		var propagate []cache.BatchItem
+		for _, key := range remaining {
+			entry, found := entries[key]
+			if !found {
+				missing = append(missing, key)
+				continue
+			}
+
+			results[key] = &models.CacheResult{
+				Entry: entry,
+				Found: !entry.IsNegative(),
+				Level: level,
+			}
+
+			if i > 0 && mc.enablePropagation && !entry.IsExpiredAt(now) {
+				remainingTTL := entry.RemainingTTLAt(now)
+				if remainingTTL.Fresh <= 0 && remainingTTL.Stale <= 0 {
+					continue
+				}
+				if entry.IsNegative() {
+					for j := 0; j < i; j++ {
+						if mc.accepts(j, cacheType, entrySize(entry)) {
+							setNegative(mc.caches[j], key, entry.Error, remainingTTL)
+						}
+					}
+					continue
+				}
+				propagate = append(propagate, cache.BatchItem{Key: key, Val: entry.Data, TTL: remainingTTL})
+			}
+		}
+
+		for j := 0; j < i && len(propagate) > 0; j++ {
+			if accepted := mc.acceptedItems(j, cacheType, propagate); len(accepted) > 0 {
+				setMany(mc.caches[j], accepted)
+			}
+		}
+
+		remaining = missing
+	}
+
+	return results
+}
+
+// getManyEntries uses the level's batch read if it has one, falling back to per-key
+// reads. Negative entries are included if the level can return them.
+func getManyEntries(c cache.Cache, keys []string) map[string]*models.CacheEntry {
+	if nbc, ok := c.(cache.NegativeBatchCache); ok {
+		return nbc.GetManyEntries(keys)
+	}
+	if bc, ok := c.(cache.BatchCache); ok {
+		return bc.GetMany(keys)
+	}
+
+	entries := make(map[string]*models.CacheEntry, len(keys))
+	for _, key := range keys {
+		if entry, found := getEntry(c, key); found {
+			entries[key] = entry
+		}
+	}
+	return entries
+}
+
+// getEntry uses the level's GetEntry if it has one, so negative entries are found,
+// falling back to Get
+func getEntry(c cache.Cache, key string) (*models.CacheEntry, bool) {
+	if nc, ok := c.(cache.NegativeCache); ok {
+		return nc.GetEntry(key)
+	}
+	return c.Get(key)
+}
+
+// getStaleEntry is the stale-if-error counterpart of getEntry
+func getStaleEntry(c cache.Cache, key string) (*models.CacheEntry, bool) {
+	if nc, ok := c.(cache.NegativeCache); ok {
+		return nc.GetStaleEntry(key)
+	}
+	return c.GetStale(key)
+}
+
+// setMany uses the level's batch write if it has one, falling back to per-key Set
+func setMany(c cache.Cache, items []cache.BatchItem) {
+	if bc, ok := c.(cache.BatchCache); ok {
+		bc.SetMany(items)
+		return
+	}
+
+	for _, item := range items {
+		c.Set(item.Key, item.Val, item.TTL)
+	}
+}
+
+// SetWithTags stores value with tags in all available caches. Levels that do not
+// implement cache.TaggedCache store it untagged.
+func (mc *MultiCache) SetWithTags(key string, val []byte, ttl models.TTL, tags ...string) {
+	mc.setWithTags(key, val, ttl, "", tags...)
+}
+
+func (mc *MultiCache) setWithTags(key string, val []byte, ttl models.TTL, cacheType models.CacheType, tags ...string) {
+	if len(mc.caches) == 0 {
+		mc.logger.Warn("No caches available for set operation", "key", key)
+		return
+	}
+
+	for i, c := range mc.caches {
+		if !available(c) || !mc.accepts(i, cacheType, len(val)) {
+			continue
+		}
+		if tc, ok := c.(cache.TaggedCache); ok {
+			tc.SetWithTags(key, val, ttl, tags...)
+		} else {
+			c.Set(key, val, ttl)
+		}
+	}
+}
+
+// InvalidateTag removes every entry stored with tag from all caches, returning the removed keys.
+// Keys found in any level are deleted from every level, since entries propagated to
+// earlier levels are stored without their tags.
+func (mc *MultiCache) InvalidateTag(tag string) []string {
+	removed := mc.invalidate(func(tc cache.TaggedCache) []string {
+		return tc.InvalidateTag(tag)
+	})
+
+	// Other instances may hold removed keys in L1 without the tag, so send them along
+	mc.publish(cache.InvalidationMessage{Tag: tag, Keys: removed})
+
+	return removed
+}
+
+// InvalidatePrefix removes every entry whose key starts with prefix from all caches,
+// returning the removed keys
+func (mc *MultiCache) InvalidatePrefix(prefix string) []string {
+	removed := mc.invalidate(func(tc cache.TaggedCache) []string {
+		return tc.InvalidatePrefix(prefix)
+	})
+
+	mc.publish(cache.InvalidationMessage{Prefix: prefix})
+
+	return removed
+}
+
+// invalidate runs fn against every tagged level, then deletes the union of the removed
+// keys from the levels that did not report them. Levels are processed from last to
+// first so a concurrent read cannot repopulate an earlier level from a later one.
+func (mc *MultiCache) invalidate(fn func(tc cache.TaggedCache) []string) []string {
+	levelKeys := make([]map[string]struct{}, len(mc.caches))
+	seen := make(map[string]struct{})
+	var removed []string
+
+	for i := len(mc.caches) - 1; i >= 0; i-- {
+		tc, ok := mc.caches[i].(cache.TaggedCache)
+		if !ok {
+			mc.logger.Warn("Cache level does not support invalidation", "level", models.CacheLevelFromIndex(i))
+			continue
+		}
+
+		keys := fn(tc)
+		levelKeys[i] = make(map[string]struct{}, len(keys))
+		for _, key := range keys {
+			levelKeys[i][key] = struct{}{}
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				removed = append(removed, key)
+			}
+		}
+	}
+
+	for i := len(mc.caches) - 1; i >= 0; i-- {
+		for _, key := range removed {
+			if _, ok := levelKeys[i][key]; !ok {
+				mc.caches[i].Delete(key)
+			}
+		}
+	}
+
+	return removed
+}
+
+// publish broadcasts msg on the invalidation bus, if one is configured
+func (mc *MultiCache) publish(msg cache.InvalidationMessage) {
+	if mc.bus == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mc.publishTimeout)
+	defer cancel()
+
+	msg.Source = mc.instanceID
+	if err := mc.bus.Publish(ctx, msg); err != nil {
+		mc.logger.Warn("Failed to publish cache invalidation", "keys", msg.Keys, "tag", msg.Tag, "prefix", msg.Prefix, "error", err)
+	}
+}
+
+// handleInvalidation applies an invalidation published by another instance to the first level
+func (mc *MultiCache) handleInvalidation(msg cache.InvalidationMessage) {
+	if msg.Source == mc.instanceID || len(mc.caches) == 0 {
+		return
+	}
+
+	local := mc.caches[0]
+	if tc, ok := local.(cache.TaggedCache); ok {
+		if msg.Tag != "" {
+			tc.InvalidateTag(msg.Tag)
+		}
+		if msg.Prefix != "" {
+			tc.InvalidatePrefix(msg.Prefix)
+		}
+	} else if msg.Tag != "" || msg.Prefix != "" {
+		mc.logger.Warn("Cache level does not support invalidation", "level", models.CacheLevelFromIndex(0))
+	}
+
+	for _, key := range msg.Keys {
+		local.Delete(key)
+	}
+}
+
+// newInstanceID returns a random identifier distinguishing this instance on the bus
+func newInstanceID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
 }
 
 // GetCacheCount returns the number of caches in the multi-cache
@@ -83,8 +632,13 @@ func (mc *MultiCache) GetCacheCount() int {
 	return len(mc.caches)
 }
 
-// GetWithLevel retrieves value from cache with level information
+// GetWithLevel retrieves value from cache with level information. A negative entry is
+// returned with Found false; the result's IsNegative reports it.
 func (mc *MultiCache) GetWithLevel(key string) *models.CacheResult {
+	return mc.getWithLevel(key, "")
+}
+
+func (mc *MultiCache) getWithLevel(key string, cacheType models.CacheType) *models.CacheResult {
 	if len(mc.caches) == 0 {
 		mc.logger.Warn("No caches available for get operation", "key", key)
 		return &models.CacheResult{
@@ -95,17 +649,20 @@ func (mc *MultiCache) GetWithLevel(key string) *models.CacheResult {
 	}
 
 	for i, c := range mc.caches {
-		entry, found := c.Get(key)
+		if !available(c) || !mc.reads(i, cacheType) {
+			continue
+		}
+		entry, found := getEntry(c, key)
 		if found {
 			if i > 0 && mc.enablePropagation {
-				mc.propagateToEarlierCaches(key, entry, i)
+				mc.propagateToEarlierCaches(key, entry, i, cacheType)
 			}
 
 			level := models.CacheLevelFromIndex(i)
 
 			return &models.CacheResult{
 				Entry: entry,
-				Found: true,
+				Found: !entry.IsNegative(),
 				Level: level,
 			}
 		}
@@ -118,8 +675,13 @@ func (mc *MultiCache) GetWithLevel(key string) *models.CacheResult {
 	}
 }
 
-// GetStaleWithLevel retrieves stale value from cache with level information
+// GetStaleWithLevel retrieves stale value from cache with level information. A negative
+// entry is returned with Found false; the result's IsNegative reports it.
 func (mc *MultiCache) GetStaleWithLevel(key string) *models.CacheResult {
+	return mc.getStaleWithLevel(key, "")
+}
+
+func (mc *MultiCache) getStaleWithLevel(key string, cacheType models.CacheType) *models.CacheResult {
 	if len(mc.caches) == 0 {
 		return &models.CacheResult{
 			Entry: nil,
@@ -129,17 +691,20 @@ func (mc *MultiCache) GetStaleWithLevel(key string) *models.CacheResult {
 	}
 
 	for i, c := range mc.caches {
-		entry, found := c.GetStale(key)
+		if !available(c) || !mc.reads(i, cacheType) {
+			continue
+		}
+		entry, found := getStaleEntry(c, key)
 		if found {
 			if i > 0 && mc.enablePropagation {
-				mc.propagateToEarlierCaches(key, entry, i)
+				mc.propagateToEarlierCaches(key, entry, i, cacheType)
 			}
 
 			level := models.CacheLevelFromIndex(i)
 
 			return &models.CacheResult{
 				Entry: entry,
-				Found: true,
+				Found: !entry.IsNegative(),
 				Level: level,
 			}
 		}
@@ -153,11 +718,12 @@ func (mc *MultiCache) GetStaleWithLevel(key string) *models.CacheResult {
 }
 
 // propagateToEarlierCaches propagates a cache entry to earlier caches with adjusted TTL
-func (mc *MultiCache) propagateToEarlierCaches(key string, entry *models.CacheEntry, foundAtIndex int) {
-	if entry == nil || entry.IsExpired() {
+func (mc *MultiCache) propagateToEarlierCaches(key string, entry *models.CacheEntry, foundAtIndex int, cacheType models.CacheType) {
+	now := mc.clock.Now()
+	if entry == nil || entry.IsExpiredAt(now) {
 		return
 	}
-	remainingTTL := entry.RemainingTTL()
+	remainingTTL := entry.RemainingTTLAt(now)
 
 	// Only propagate if there's meaningful time left
 	if remainingTTL.Fresh <= 0 && remainingTTL.Stale <= 0 {
@@ -165,6 +731,81 @@ func (mc *MultiCache) propagateToEarlierCaches(key string, entry *models.CacheEn
 	}
 
 	for i := 0; i < foundAtIndex; i++ {
-		mc.caches[i].Set(key, entry.Data, remainingTTL)
+		if !mc.accepts(i, cacheType, entrySize(entry)) {
+			continue
+		}
+		if entry.IsNegative() {
+			setNegative(mc.caches[i], key, entry.Error, remainingTTL)
+		} else {
+			mc.caches[i].Set(key, entry.Data, remainingTTL)
+		}
 	}
 }
+
+// entrySize returns the size checked against level policies: the error payload of a
+// negative entry, or the data otherwise
+func entrySize(entry *models.CacheEntry) int {
+	if entry.IsNegative() {
+		return len(entry.Error)
+	}
+	return len(entry.Data)
+}
+
+// boundMultiCache is a view of MultiCache whose writes apply the level policies for a
+// single request's cache type
+type boundMultiCache struct {
+	*MultiCache
+	cacheType models.CacheType
+}
+
+func (b *boundMultiCache) Get(key string) (*models.CacheEntry, bool) {
+	return positive(b.getWithLevel(key, b.cacheType))
+}
+
+func (b *boundMultiCache) GetStale(key string) (*models.CacheEntry, bool) {
+	return positive(b.getStaleWithLevel(key, b.cacheType))
+}
+
+func (b *boundMultiCache) GetEntry(key string) (*models.CacheEntry, bool) {
+	return anyEntry(b.getWithLevel(key, b.cacheType))
+}
+
+func (b *boundMultiCache) GetStaleEntry(key string) (*models.CacheEntry, bool) {
+	return anyEntry(b.getStaleWithLevel(key, b.cacheType))
+}
+
+func (b *boundMultiCache) Set(key string, val []byte, ttl models.TTL) {
+	b.set(key, val, ttl, b.cacheType)
+}
+
+func (b *boundMultiCache) SetNegative(key string, errPayload []byte, ttl models.TTL) {
+	b.setNegative(key, errPayload, ttl, b.cacheType)
+}
+
+func (b *boundMultiCache) SetWithTags(key string, val []byte, ttl models.TTL, tags ...string) {
+	b.setWithTags(key, val, ttl, b.cacheType, tags...)
+}
+
+func (b *boundMultiCache) GetMany(keys []string) map[string]*models.CacheEntry {
+	return b.getMany(keys, b.cacheType, positive)
+}
+
+func (b *boundMultiCache) GetManyEntries(keys []string) map[string]*models.CacheEntry {
+	return b.getMany(keys, b.cacheType, anyEntry)
+}
+
+func (b *boundMultiCache) SetMany(items []cache.BatchItem) {
+	b.setMany(items, b.cacheType)
+}
+
+func (b *boundMultiCache) GetManyWithLevel(keys []string) map[string]*models.CacheResult {
+	return b.getManyWithLevel(keys, b.cacheType)
+}
+
+func (b *boundMultiCache) GetWithLevel(key string) *models.CacheResult {
+	return b.getWithLevel(key, b.cacheType)
+}
+
+func (b *boundMultiCache) GetStaleWithLevel(key string) *models.CacheResult {
+	return b.getStaleWithLevel(key, b.cacheType)
+}
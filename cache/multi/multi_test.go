@@ -1,14 +1,18 @@
 package multi
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/cache/invalidation"
 	"github.com/status-im/proxy-common/cache/mock"
+	"github.com/status-im/proxy-common/clock"
 	"github.com/status-im/proxy-common/models"
 )
 
@@ -82,6 +86,84 @@ func TestMultiCache_Get_SecondCacheHit(t *testing.T) {
 	assert.Equal(t, expectedEntry, entry)
 }
 
+func TestMultiCache_Get_PropagatesRemainingTTL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache1 := mock.NewMockCache(ctrl)
+	cache2 := mock.NewMockCache(ctrl)
+	mockClock := clock.NewMock(time.UnixMilli(1700000000000))
+
+	multiCache := NewMultiCache([]cache.Cache{cache1, cache2}, true, WithClock(mockClock))
+
+	entry := models.NewCacheEntry([]byte("test-value"), models.TTL{Fresh: 2 * time.Second, Stale: time.Second}, mockClock.Now())
+	mockClock.Advance(1500 * time.Millisecond)
+
+	cache1.EXPECT().Get("test-key").Return(nil, false).Times(1)
+	cache2.EXPECT().Get("test-key").Return(entry, true).Times(1)
+	cache1.EXPECT().Set("test-key", entry.Data, models.TTL{Fresh: 500 * time.Millisecond, Stale: time.Second}).Times(1)
+
+	_, found := multiCache.Get("test-key")
+	assert.True(t, found)
+
+	// Expired entries are not propagated
+	mockClock.Advance(2 * time.Second)
+	cache1.EXPECT().Get("test-key").Return(nil, false).Times(1)
+	cache2.EXPECT().Get("test-key").Return(entry, true).Times(1)
+
+	_, found = multiCache.Get("test-key")
+	assert.True(t, found)
+}
+
+func TestMultiCache_SetNegative(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	negative := mock.NewMockNegativeCache(ctrl)
+	plain := mock.NewMockCache(ctrl)
+	multiCache := NewMultiCache([]cache.Cache{negative, plain}, true).(*MultiCache)
+
+	ttl := models.TTL{Fresh: 5 * time.Second}
+	negative.EXPECT().SetNegative("test-key", []byte("not found"), ttl)
+	// Levels without negative entries drop the key so they cannot serve an older value
+	plain.EXPECT().Delete("test-key")
+
+	multiCache.SetNegative("test-key", []byte("not found"), ttl)
+}
+
+func TestMultiCache_Get_PropagatesNegativeEntry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	l1 := mock.NewMockNegativeCache(ctrl)
+	l2 := mock.NewMockNegativeCache(ctrl)
+	mockClock := clock.NewMock(time.UnixMilli(1700000000000))
+	multiCache := NewMultiCache([]cache.Cache{l1, l2}, true, WithClock(mockClock))
+
+	entry := models.NewNegativeCacheEntry([]byte("not found"), models.TTL{Fresh: 5 * time.Second}, mockClock.Now())
+	mockClock.Advance(time.Second)
+
+	l1.EXPECT().GetEntry("test-key").Return(nil, false)
+	l2.EXPECT().GetEntry("test-key").Return(entry, true)
+	l1.EXPECT().SetNegative("test-key", []byte("not found"), models.TTL{Fresh: 4 * time.Second})
+
+	result := multiCache.GetWithLevel("test-key")
+
+	// Callers unaware of negative entries see a miss
+	assert.False(t, result.Found)
+	assert.True(t, result.IsNegative())
+	assert.Equal(t, models.CacheLevelL2, result.Level)
+
+	l1.EXPECT().GetEntry("test-key").Return(entry, true)
+	_, found := multiCache.Get("test-key")
+	assert.False(t, found)
+
+	l1.EXPECT().GetEntry("test-key").Return(entry, true)
+	got, found := multiCache.(cache.NegativeCache).GetEntry("test-key")
+	assert.True(t, found)
+	assert.Equal(t, entry, got)
+}
+
 func TestMultiCache_Get_AllCachesMiss(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -227,3 +309,437 @@ func TestMultiCache_GetCacheCount(t *testing.T) {
 
 	assert.Equal(t, 2, mc.GetCacheCount())
 }
+
+func TestMultiCache_SetWithTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tagged := mock.NewMockTaggedCache(ctrl)
+	untagged := mock.NewMockCache(ctrl)
+	multiCache := NewMultiCache([]cache.Cache{tagged, untagged}, true).(*MultiCache)
+
+	ttl := models.TTL{Fresh: time.Minute}
+	tagged.EXPECT().SetWithTags("test-key", []byte("test-value"), ttl, "block:100", "chain:1")
+	untagged.EXPECT().Set("test-key", []byte("test-value"), ttl)
+
+	multiCache.SetWithTags("test-key", []byte("test-value"), ttl, "block:100", "chain:1")
+}
+
+func TestMultiCache_InvalidateTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	l1 := mock.NewMockTaggedCache(ctrl)
+	l2 := mock.NewMockTaggedCache(ctrl)
+	multiCache := NewMultiCache([]cache.Cache{l1, l2}, true).(*MultiCache)
+
+	// L2 is invalidated first; "propagated" was copied to L1 without its tags
+	gomock.InOrder(
+		l2.EXPECT().InvalidateTag("block:100").Return([]string{"propagated", "both"}),
+		l1.EXPECT().InvalidateTag("block:100").Return([]string{"both", "l1-only"}),
+		l2.EXPECT().Delete("l1-only"),
+		l1.EXPECT().Delete("propagated"),
+	)
+
+	removed := multiCache.InvalidateTag("block:100")
+
+	assert.Equal(t, []string{"propagated", "both", "l1-only"}, removed)
+}
+
+func TestMultiCache_InvalidatePrefix_UnsupportedLevel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	plain := mock.NewMockCache(ctrl)
+	tagged := mock.NewMockTaggedCache(ctrl)
+	multiCache := NewMultiCache([]cache.Cache{plain, tagged}, true).(*MultiCache)
+
+	tagged.EXPECT().InvalidatePrefix("chain:1:").Return([]string{"chain:1:a"})
+	plain.EXPECT().Delete("chain:1:a")
+
+	removed := multiCache.InvalidatePrefix("chain:1:")
+
+	assert.Equal(t, []string{"chain:1:a"}, removed)
+}
+
+func TestMultiCache_InvalidationBus_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	bus := invalidation.NewMemoryBus()
+	l2 := mock.NewMockCache(ctrl)
+
+	localL1 := mock.NewMockCache(ctrl)
+	local := NewMultiCache([]cache.Cache{localL1, l2}, true, WithInvalidationBus(bus))
+
+	remoteL1 := mock.NewMockCache(ctrl)
+	NewMultiCache([]cache.Cache{remoteL1, l2}, true, WithInvalidationBus(bus))
+
+	// The shared L2 is only cleared by the instance that deleted the key
+	localL1.EXPECT().Delete("test-key").Times(1)
+	l2.EXPECT().Delete("test-key").Times(1)
+	remoteL1.EXPECT().Delete("test-key").Times(1)
+
+	local.Delete("test-key")
+}
+
+func TestMultiCache_InvalidationBus_InvalidateTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	bus := invalidation.NewMemoryBus()
+	l2 := mock.NewMockTaggedCache(ctrl)
+
+	localL1 := mock.NewMockTaggedCache(ctrl)
+	local := NewMultiCache([]cache.Cache{localL1, l2}, true, WithInvalidationBus(bus)).(*MultiCache)
+
+	remoteL1 := mock.NewMockTaggedCache(ctrl)
+	NewMultiCache([]cache.Cache{remoteL1, l2}, true, WithInvalidationBus(bus))
+
+	l2.EXPECT().InvalidateTag("block:100").Return([]string{"a"})
+	localL1.EXPECT().InvalidateTag("block:100").Return([]string{"a"})
+
+	// The remote L1 drops its own tagged keys plus the keys removed elsewhere
+	remoteL1.EXPECT().InvalidateTag("block:100").Return(nil)
+	remoteL1.EXPECT().Delete("a")
+
+	local.InvalidateTag("block:100")
+}
+
+func TestMultiCache_InvalidationBus_InvalidatePrefix(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	bus := invalidation.NewMemoryBus()
+	l2 := mock.NewMockTaggedCache(ctrl)
+
+	localL1 := mock.NewMockTaggedCache(ctrl)
+	local := NewMultiCache([]cache.Cache{localL1, l2}, true, WithInvalidationBus(bus)).(*MultiCache)
+
+	remoteL1 := mock.NewMockTaggedCache(ctrl)
+	NewMultiCache([]cache.Cache{remoteL1, l2}, true, WithInvalidationBus(bus))
+
+	l2.EXPECT().InvalidatePrefix("chain:1:").Return([]string{"chain:1:a"})
+	localL1.EXPECT().InvalidatePrefix("chain:1:").Return(nil)
+	localL1.EXPECT().Delete("chain:1:a")
+	remoteL1.EXPECT().InvalidatePrefix("chain:1:").Return(nil)
+
+	local.InvalidatePrefix("chain:1:")
+}
+
+func TestMultiCache_Close_Unsubscribes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	bus := invalidation.NewMemoryBus()
+	l2 := mock.NewMockCache(ctrl)
+
+	localL1 := mock.NewMockCache(ctrl)
+	local := NewMultiCache([]cache.Cache{localL1, l2}, true, WithInvalidationBus(bus))
+
+	remoteL1 := mock.NewMockCache(ctrl)
+	remote := NewMultiCache([]cache.Cache{remoteL1, l2}, true, WithInvalidationBus(bus)).(*MultiCache)
+	require.NoError(t, remote.Close())
+	require.NoError(t, remote.Close())
+
+	// The closed instance no longer applies remote invalidations
+	localL1.EXPECT().Delete("test-key").Times(1)
+	l2.EXPECT().Delete("test-key").Times(1)
+
+	local.Delete("test-key")
+}
+
+func TestMultiCache_InvalidationBus_PublishTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	bus := mock.NewMockInvalidationBus(ctrl)
+	bus.EXPECT().Subscribe(gomock.Any()).Return(func() {})
+	bus.EXPECT().Publish(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ cache.InvalidationMessage) error {
+			deadline, ok := ctx.Deadline()
+			assert.True(t, ok, "publish should be bounded")
+			assert.WithinDuration(t, time.Now().Add(50*time.Millisecond), deadline, 40*time.Millisecond)
+			return nil
+		})
+
+	l1 := mock.NewMockCache(ctrl)
+	l1.EXPECT().Delete("test-key")
+	multiCache := NewMultiCache([]cache.Cache{l1}, true, WithInvalidationBus(bus), WithPublishTimeout(50*time.Millisecond))
+
+	multiCache.Delete("test-key")
+}
+
+func TestMultiCache_InvalidationBus_PublishError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	bus := invalidation.NewMemoryBus()
+	l1 := mock.NewMockCache(ctrl)
+	multiCache := NewMultiCache([]cache.Cache{l1}, true, WithInvalidationBus(bus))
+	require.NoError(t, bus.Close())
+
+	// A failed broadcast does not prevent the local delete
+	l1.EXPECT().Delete("test-key").Times(1)
+
+	multiCache.Delete("test-key")
+}
+
+func TestMultiCache_GetManyWithLevel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	l1 := mock.NewMockBatchCache(ctrl)
+	l2 := mock.NewMockBatchCache(ctrl)
+	multiCache := NewMultiCache([]cache.Cache{l1, l2}, true).(*MultiCache)
+
+	now := time.Now().Unix()
+	l1Entry := &models.CacheEntry{Data: []byte("l1"), CreatedAt: now, StaleAt: now + 60, ExpiresAt: now + 120}
+	l2Entry := &models.CacheEntry{Data: []byte("l2"), CreatedAt: now, StaleAt: now + 60, ExpiresAt: now + 120}
+
+	l1.EXPECT().GetMany([]string{"a", "b", "c"}).Return(map[string]*models.CacheEntry{"a": l1Entry})
+	l2.EXPECT().GetMany([]string{"b", "c"}).Return(map[string]*models.CacheEntry{"b": l2Entry})
+	l1.EXPECT().SetMany(gomock.Any()).Do(func(items []cache.BatchItem) {
+		require.Len(t, items, 1)
+		assert.Equal(t, "b", items[0].Key)
+		assert.Equal(t, []byte("l2"), items[0].Val)
+		assert.Greater(t, items[0].TTL.Fresh, time.Duration(0))
+	})
+
+	results := multiCache.GetManyWithLevel([]string{"a", "b", "c", "a"})
+
+	require.Len(t, results, 3)
+	assert.Equal(t, models.CacheLevelL1, results["a"].Level)
+	assert.Equal(t, l1Entry, results["a"].Entry)
+	assert.Equal(t, models.CacheLevelL2, results["b"].Level)
+	assert.Equal(t, l2Entry, results["b"].Entry)
+	assert.False(t, results["c"].Found)
+	assert.Equal(t, models.CacheLevelMiss, results["c"].Level)
+}
+
+func TestMultiCache_GetManyWithLevel_PropagatesNegativeEntries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	l1 := mock.NewMockNegativeCache(ctrl)
+	l2 := mock.NewMockNegativeBatchCache(ctrl)
+	mockClock := clock.NewMock(time.UnixMilli(1700000000000))
+	multiCache := NewMultiCache([]cache.Cache{l1, l2}, true, WithClock(mockClock)).(*MultiCache)
+
+	ttl := models.TTL{Fresh: time.Minute}
+	positive := models.NewCacheEntry([]byte("value"), ttl, mockClock.Now())
+	negative := models.NewNegativeCacheEntry(nil, ttl, mockClock.Now())
+
+	l1.EXPECT().GetEntry("a").Return(nil, false)
+	l1.EXPECT().GetEntry("b").Return(nil, false)
+	l2.EXPECT().GetManyEntries([]string{"a", "b"}).Return(map[string]*models.CacheEntry{"a": positive, "b": negative})
+	l1.EXPECT().SetNegative("b", []byte(nil), ttl)
+	l1.EXPECT().Set("a", []byte("value"), ttl)
+
+	results := multiCache.GetManyWithLevel([]string{"a", "b"})
+
+	assert.True(t, results["a"].Found)
+	assert.False(t, results["a"].IsNegative())
+	assert.False(t, results["b"].Found)
+	assert.True(t, results["b"].IsNegative())
+}
+
+func TestMultiCache_GetMany_FallsBackToGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	l1 := mock.NewMockCache(ctrl)
+	multiCache := NewMultiCache([]cache.Cache{l1}, true).(*MultiCache)
+
+	entry := &models.CacheEntry{Data: []byte("value")}
+	l1.EXPECT().Get("a").Return(entry, true)
+	l1.EXPECT().Get("b").Return(nil, false)
+
+	entries := multiCache.GetMany([]string{"a", "b"})
+
+	assert.Equal(t, map[string]*models.CacheEntry{"a": entry}, entries)
+}
+
+func TestMultiCache_SetMany(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	batch := mock.NewMockBatchCache(ctrl)
+	plain := mock.NewMockCache(ctrl)
+	multiCache := NewMultiCache([]cache.Cache{batch, plain}, true).(*MultiCache)
+
+	ttl := models.TTL{Fresh: time.Minute}
+	items := []cache.BatchItem{
+		{Key: "a", Val: []byte("1"), TTL: ttl},
+		{Key: "b", Val: []byte("2"), TTL: ttl},
+	}
+	batch.EXPECT().SetMany(items)
+	plain.EXPECT().Set("a", []byte("1"), ttl)
+	plain.EXPECT().Set("b", []byte("2"), ttl)
+
+	multiCache.SetMany(items)
+}
+
+// unhealthyCache is a mock cache level that also reports its availability
+type unhealthyCache struct {
+	*mock.MockCache
+	*mock.MockHealthChecker
+}
+
+func TestMultiCache_SkipsUnavailableLevel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache1 := mock.NewMockCache(ctrl)
+	cache2 := unhealthyCache{mock.NewMockCache(ctrl), mock.NewMockHealthChecker(ctrl)}
+	cache2.MockHealthChecker.EXPECT().Available().Return(false).AnyTimes()
+	multiCache := NewMultiCache([]cache.Cache{cache1, cache2}, true)
+
+	// Only deletes reach cache2.MockCache
+	cache1.EXPECT().Get("test-key").Return(nil, false)
+	result := multiCache.GetWithLevel("test-key")
+	assert.False(t, result.Found)
+
+	cache1.EXPECT().Set("test-key", []byte("v"), models.TTL{Fresh: time.Minute})
+	multiCache.Set("test-key", []byte("v"), models.TTL{Fresh: time.Minute})
+
+	cache1.EXPECT().Delete("test-key")
+	cache2.MockCache.EXPECT().Delete("test-key")
+	multiCache.Delete("test-key")
+
+	// A negative write the level cannot take drops the key from it instead
+	cache1.EXPECT().Delete("test-key")
+	cache2.MockCache.EXPECT().Delete("test-key")
+	multiCache.(*MultiCache).SetNegative("test-key", []byte("err"), models.TTL{Fresh: time.Minute})
+
+	cache1.EXPECT().Get("test-key").Return(nil, false)
+	results := multiCache.(*MultiCache).GetManyWithLevel([]string{"test-key"})
+	assert.False(t, results["test-key"].Found)
+}
+
+func TestMultiCache_LevelPolicies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache1 := mock.NewMockCache(ctrl)
+	cache2 := mock.NewMockCache(ctrl)
+	multiCache := NewMultiCacheFromConfig(&cache.MultiCacheConfig{
+		EnablePropagation: true,
+		Levels: []cache.LevelPolicy{
+			{MaxEntrySize: 4},
+			{SkipCacheTypes: []models.CacheType{models.CacheTypeMinimal}},
+		},
+	}, []cache.Cache{cache1, cache2})
+
+	ttl := models.TTL{Fresh: time.Minute}
+	minimal := multiCache.WithContext(cache.ContextWithRequestLabels(context.Background(),
+		cache.RequestLabels{CacheType: models.CacheTypeMinimal}))
+	permanent := multiCache.WithContext(cache.ContextWithRequestLabels(context.Background(),
+		cache.RequestLabels{CacheType: models.CacheTypePermanent}))
+
+	t.Run("skips cache types per level", func(t *testing.T) {
+		cache1.EXPECT().Set("a", []byte("1"), ttl)
+		minimal.Set("a", []byte("1"), ttl)
+
+		cache1.EXPECT().Set("b", []byte("2"), ttl)
+		cache2.EXPECT().Set("b", []byte("2"), ttl)
+		permanent.Set("b", []byte("2"), ttl)
+
+		// Writes without a cache type only apply the size limit
+		cache1.EXPECT().Set("c", []byte("3"), ttl)
+		cache2.EXPECT().Set("c", []byte("3"), ttl)
+		multiCache.Set("c", []byte("3"), ttl)
+	})
+
+	t.Run("skips entries above the size limit", func(t *testing.T) {
+		cache2.EXPECT().Set("big", []byte("large"), ttl)
+		permanent.Set("big", []byte("large"), ttl)
+
+		cache2.EXPECT().Set("big", []byte("large"), ttl)
+		cache1.EXPECT().Set("small", []byte("1"), ttl)
+		cache2.EXPECT().Set("small", []byte("1"), ttl)
+		multiCache.(cache.BatchCache).SetMany([]cache.BatchItem{
+			{Key: "big", Val: []byte("large"), TTL: ttl},
+			{Key: "small", Val: []byte("1"), TTL: ttl},
+		})
+	})
+
+	t.Run("does not propagate rejected entries", func(t *testing.T) {
+		entry := models.NewCacheEntry([]byte("large"), ttl, time.Now())
+		cache1.EXPECT().Get("big").Return(nil, false)
+		cache2.EXPECT().Get("big").Return(entry, true)
+
+		result := permanent.GetWithLevel("big")
+		assert.True(t, result.Found)
+		assert.Equal(t, models.CacheLevelL2, result.Level)
+	})
+}
+
+func TestMultiCache_ReadPolicies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache1 := mock.NewMockCache(ctrl)
+	cache2 := mock.NewMockCache(ctrl)
+	multiCache := NewMultiCache([]cache.Cache{cache1, cache2}, true, WithLevelPolicies([]cache.LevelPolicy{
+		{SkipReadCacheTypes: []models.CacheType{models.CacheTypePermanent}},
+		{WriteOnly: true},
+	}))
+
+	// Neither level serves permanent reads
+	permanent := multiCache.ForCacheType(models.CacheTypePermanent)
+	assert.Equal(t, models.CacheLevelMiss, permanent.GetWithLevel("key").Level)
+	assert.Equal(t, models.CacheLevelMiss, permanent.(cache.LevelAwareBatchCache).GetManyWithLevel([]string{"key"})["key"].Level)
+
+	// Other cache types, and calls without one, are read from L1 only
+	cache1.EXPECT().Get("key").Return(nil, false).Times(2)
+	_, found := multiCache.ForCacheType(models.CacheTypeShort).Get("key")
+	assert.False(t, found)
+	_, found = multiCache.Get("key")
+	assert.False(t, found)
+}
+
+type sizeLimitedCache struct {
+	*mock.MockCache
+	*mock.MockSizeLimitedCache
+}
+
+func TestMultiCache_SizeLimitedLevel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	level := sizeLimitedCache{mock.NewMockCache(ctrl), mock.NewMockSizeLimitedCache(ctrl)}
+	limited := mock.NewMockCache(ctrl)
+	level.MockSizeLimitedCache.EXPECT().WithMaxEntrySize(4).Return(limited)
+	multiCache := NewMultiCache([]cache.Cache{level}, false, WithLevelPolicies([]cache.LevelPolicy{{MaxEntrySize: 4}}))
+
+	// The view checks the encoded entry, so larger raw values are still handed to it
+	ttl := models.TTL{Fresh: time.Minute}
+	limited.EXPECT().Set("key", []byte("large"), ttl)
+	multiCache.Set("key", []byte("large"), ttl)
+}
+
+func TestMultiCache_ReadOnlyLevel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache1 := mock.NewMockCache(ctrl)
+	cache2 := mock.NewMockCache(ctrl)
+	multiCache := NewMultiCache([]cache.Cache{cache1, cache2}, true,
+		WithLevelPolicies([]cache.LevelPolicy{{ReadOnly: true}}))
+
+	ttl := models.TTL{Fresh: time.Minute}
+	cache2.EXPECT().Set("key", []byte("v"), ttl)
+	multiCache.Set("key", []byte("v"), ttl)
+
+	entry := models.NewCacheEntry([]byte("v"), ttl, time.Now())
+	cache1.EXPECT().Get("key").Return(nil, false)
+	cache2.EXPECT().Get("key").Return(entry, true)
+	_, found := multiCache.Get("key")
+	assert.True(t, found)
+
+	// Deletes still reach read-only levels
+	cache1.EXPECT().Delete("key")
+	cache2.EXPECT().Delete("key")
+	multiCache.Delete("key")
+}
@@ -0,0 +1,392 @@
+package multi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/clock"
+	"github.com/status-im/proxy-common/models"
+)
+
+// Ensure MultiCacheV2 implements cache.CacheV2 and cache.LevelAwareCacheV2
+var _ cache.CacheV2 = (*MultiCacheV2)(nil)
+var _ cache.LevelAwareCacheV2 = (*MultiCacheV2)(nil)
+var _ cache.NegativeCacheV2 = (*MultiCacheV2)(nil)
+
+// MultiCacheV2 is the context-aware counterpart of MultiCache.
+// A failing level is skipped on reads; if no level has the key, the read returns
+// cache.ErrCacheMiss when every level missed, or the level errors otherwise.
+// Levels implementing cache.HealthChecker are skipped while they are not Available,
+// except by deletes, which they record and replay once they recover. A negative write
+// deletes the key from such a level instead.
+// Writes, including propagation, skip levels whose cache.LevelPolicy does not accept
+// the entry for the cache type in the context's RequestLabels, and reads skip levels
+// whose policy does not read it. Levels implementing
+// cache.SizeLimitedCacheV2 check the policy's MaxEntrySize against the encoded entry
+// themselves.
+type MultiCacheV2 struct {
+	caches            []cache.CacheV2
+	logger            cache.Logger
+	enablePropagation bool
+	policies          []cache.LevelPolicy
+	clock             clock.Clock
+	tracer            trace.Tracer
+}
+
+// OptionV2 is a functional option for configuring MultiCacheV2
+type OptionV2 func(*MultiCacheV2)
+
+// WithLoggerV2 sets the logger for MultiCacheV2
+func WithLoggerV2(logger cache.Logger) OptionV2 {
+	return func(mc *MultiCacheV2) {
+		mc.logger = logger
+	}
+}
+
+// WithClockV2 sets the clock used to compute the remaining TTL of propagated entries
+func WithClockV2(c clock.Clock) OptionV2 {
+	return func(mc *MultiCacheV2) {
+		mc.clock = c
+	}
+}
+
+// WithTracerV2 sets the tracer used to create a span for every get, set and delete on each level
+func WithTracerV2(tracer trace.Tracer) OptionV2 {
+	return func(mc *MultiCacheV2) {
+		mc.tracer = tracer
+	}
+}
+
+// WithLevelPoliciesV2 sets the read and write policy of each level, in level order.
+// Levels without a policy serve every read and accept every entry. NewMultiCacheV2FromConfig sets the policies from
+// cache.MultiCacheConfig.Levels.
+func WithLevelPoliciesV2(policies []cache.LevelPolicy) OptionV2 {
+	return func(mc *MultiCacheV2) {
+		mc.policies = policies
+	}
+}
+
+// NewMultiCacheV2 creates a new MultiCacheV2 instance with provided cache implementations
+func NewMultiCacheV2(caches []cache.CacheV2, enablePropagation bool, opts ...OptionV2) cache.LevelAwareCacheV2 {
+	mc := &MultiCacheV2{
+		caches:            caches,
+		logger:            cache.NoopLogger{},
+		enablePropagation: enablePropagation,
+		clock:             clock.Real{},
+		tracer:            noop.NewTracerProvider().Tracer(""),
+	}
+
+	for _, opt := range opts {
+		opt(mc)
+	}
+
+	checkPolicies(mc.logger, mc.policies, len(caches))
+	mc.caches, mc.policies = limitEntrySizes(caches, mc.policies, func(c cache.CacheV2, size int) (cache.CacheV2, bool) {
+		if sl, ok := c.(cache.SizeLimitedCacheV2); ok {
+			return sl.WithMaxEntrySize(size), true
+		}
+		return c, false
+	})
+
+	return mc
+}
+
+// NewMultiCacheV2FromConfig creates a new MultiCacheV2 with the propagation setting and
+// level policies of cfg. Options are applied after cfg, so WithLevelPoliciesV2 overrides
+// its Levels.
+func NewMultiCacheV2FromConfig(cfg *cache.MultiCacheConfig, caches []cache.CacheV2, opts ...OptionV2) cache.LevelAwareCacheV2 {
+	return NewMultiCacheV2(caches, cfg.EnablePropagation, append([]OptionV2{WithLevelPoliciesV2(cfg.Levels)}, opts...)...)
+}
+
+// Get retrieves value from the first available cache that has the key. A negative
+// entry is reported as cache.ErrCacheMiss.
+func (mc *MultiCacheV2) Get(ctx context.Context, key string) (*models.CacheEntry, error) {
+	return positiveV2(mc.GetWithLevel(ctx, key))
+}
+
+// GetStale retrieves stale value from the first available cache that has the key. A
+// negative entry is reported as cache.ErrCacheMiss.
+func (mc *MultiCacheV2) GetStale(ctx context.Context, key string) (*models.CacheEntry, error) {
+	return positiveV2(mc.GetStaleWithLevel(ctx, key))
+}
+
+// GetEntry retrieves a positive or negative entry from the first available cache that has the key
+func (mc *MultiCacheV2) GetEntry(ctx context.Context, key string) (*models.CacheEntry, error) {
+	return anyEntryV2(mc.GetWithLevel(ctx, key))
+}
+
+// GetStaleEntry retrieves a stale positive or negative entry from the first available
+// cache that has the key
+func (mc *MultiCacheV2) GetStaleEntry(ctx context.Context, key string) (*models.CacheEntry, error) {
+	return anyEntryV2(mc.GetStaleWithLevel(ctx, key))
+}
+
+// positiveV2 returns the entry of a result holding a positive entry
+func positiveV2(result *models.CacheResult, err error) (*models.CacheEntry, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.Entry, nil
+}
+
+// anyEntryV2 returns the entry of a result holding a positive or negative entry
+func anyEntryV2(result *models.CacheResult, err error) (*models.CacheEntry, error) {
+	if result.IsNegative() {
+		return result.Entry, nil
+	}
+	return positiveV2(result, err)
+}
+
+// Set stores value in all available caches, returning the joined errors of failing levels
+func (mc *MultiCacheV2) Set(ctx context.Context, key string, val []byte, ttl models.TTL) error {
+	if len(mc.caches) == 0 {
+		mc.logger.Warn("No caches available for set operation", "key", key)
+		return nil
+	}
+
+	cacheType := cache.RequestLabelsFromContext(ctx).CacheType
+	var errs []error
+	for i, c := range mc.caches {
+		if !available(c) || !mc.accepts(i, cacheType, len(val)) {
+			continue
+		}
+
+		spanCtx, span := mc.startLevelSpan(ctx, "set", i)
+		err := c.Set(spanCtx, key, val, ttl)
+		endLevelSpan(span, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", models.CacheLevelFromIndex(i), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SetNegative stores a negative entry in all available caches, returning the joined
+// errors of failing levels. Levels that do not implement cache.NegativeCacheV2 drop the
+// key instead, so they cannot serve an older value.
+func (mc *MultiCacheV2) SetNegative(ctx context.Context, key string, errPayload []byte, ttl models.TTL) error {
+	if len(mc.caches) == 0 {
+		mc.logger.Warn("No caches available for set operation", "key", key)
+		return nil
+	}
+
+	cacheType := cache.RequestLabelsFromContext(ctx).CacheType
+	var errs []error
+	for i, c := range mc.caches {
+		if !mc.accepts(i, cacheType, len(errPayload)) {
+			continue
+		}
+
+		spanCtx, span := mc.startLevelSpan(ctx, "set_negative", i)
+		var err error
+		if available(c) {
+			err = setNegativeV2(spanCtx, c, key, errPayload, ttl)
+		} else {
+			// The level cannot take the entry, but must not keep the one it replaces
+			err = c.Delete(spanCtx, key)
+		}
+		endLevelSpan(span, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", models.CacheLevelFromIndex(i), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// accepts reports whether the policy of level i accepts an entry of size bytes for cacheType
+func (mc *MultiCacheV2) accepts(i int, cacheType models.CacheType, size int) bool {
+	return i >= len(mc.policies) || mc.policies[i].Accepts(cacheType, size)
+}
+
+// reads reports whether the policy of level i lets it serve reads for cacheType
+func (mc *MultiCacheV2) reads(i int, cacheType models.CacheType) bool {
+	return i >= len(mc.policies) || mc.policies[i].Reads(cacheType)
+}
+
+// setNegativeV2 uses the level's negative write if it has one, falling back to Delete
+func setNegativeV2(ctx context.Context, c cache.CacheV2, key string, errPayload []byte, ttl models.TTL) error {
+	if nc, ok := c.(cache.NegativeCacheV2); ok {
+		return nc.SetNegative(ctx, key, errPayload, ttl)
+	}
+	return c.Delete(ctx, key)
+}
+
+// Delete removes entry from all caches, returning the joined errors of failing levels.
+// Unavailable levels are included, so a level that records the delete for replay still
+// reports that it has not been applied yet.
+func (mc *MultiCacheV2) Delete(ctx context.Context, key string) error {
+	if len(mc.caches) == 0 {
+		mc.logger.Warn("No caches available for delete operation", "key", key)
+		return nil
+	}
+
+	var errs []error
+	for i, c := range mc.caches {
+		spanCtx, span := mc.startLevelSpan(ctx, "delete", i)
+		err := c.Delete(spanCtx, key)
+		endLevelSpan(span, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", models.CacheLevelFromIndex(i), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// GetCacheCount returns the number of caches in the multi-cache
+func (mc *MultiCacheV2) GetCacheCount() int {
+	return len(mc.caches)
+}
+
+// GetWithLevel retrieves value from cache with level information. A negative entry is
+// returned with cache.ErrCacheMiss; the result's IsNegative reports it.
+func (mc *MultiCacheV2) GetWithLevel(ctx context.Context, key string) (*models.CacheResult, error) {
+	return mc.getWithLevel(ctx, key, "get", getEntryV2)
+}
+
+// GetStaleWithLevel retrieves stale value from cache with level information. A negative
+// entry is returned with cache.ErrCacheMiss; the result's IsNegative reports it.
+func (mc *MultiCacheV2) GetStaleWithLevel(ctx context.Context, key string) (*models.CacheResult, error) {
+	return mc.getWithLevel(ctx, key, "get_stale", getStaleEntryV2)
+}
+
+// getEntryV2 uses the level's GetEntry if it has one, so negative entries are found,
+// falling back to Get
+func getEntryV2(c cache.CacheV2, ctx context.Context, key string) (*models.CacheEntry, error) {
+	if nc, ok := c.(cache.NegativeCacheV2); ok {
+		return nc.GetEntry(ctx, key)
+	}
+	return c.Get(ctx, key)
+}
+
+// getStaleEntryV2 is the stale-if-error counterpart of getEntryV2
+func getStaleEntryV2(c cache.CacheV2, ctx context.Context, key string) (*models.CacheEntry, error) {
+	if nc, ok := c.(cache.NegativeCacheV2); ok {
+		return nc.GetStaleEntry(ctx, key)
+	}
+	return c.GetStale(ctx, key)
+}
+
+// getWithLevel walks the levels in order using get, propagating a hit to earlier levels.
+// operation names the per-level spans.
+func (mc *MultiCacheV2) getWithLevel(
+	ctx context.Context,
+	key string,
+	operation string,
+	get func(cache.CacheV2, context.Context, string) (*models.CacheEntry, error),
+) (*models.CacheResult, error) {
+	miss := &models.CacheResult{
+		Entry: nil,
+		Found: false,
+		Level: models.CacheLevelMiss,
+	}
+
+	if len(mc.caches) == 0 {
+		mc.logger.Warn("No caches available for get operation", "key", key)
+		return miss, cache.ErrCacheMiss
+	}
+
+	cacheType := cache.RequestLabelsFromContext(ctx).CacheType
+	var errs []error
+	for i, c := range mc.caches {
+		if !available(c) || !mc.reads(i, cacheType) {
+			continue
+		}
+
+		level := models.CacheLevelFromIndex(i)
+
+		spanCtx, span := mc.startLevelSpan(ctx, operation, i)
+		entry, err := get(c, spanCtx, key)
+		span.SetAttributes(attribute.Bool("cache.hit", err == nil))
+		endLevelSpan(span, err)
+		if err != nil {
+			if !errors.Is(err, cache.ErrCacheMiss) {
+				mc.logger.Warn("Cache level get failed", "key", key, "level", level, "error", err)
+				errs = append(errs, fmt.Errorf("%s: %w", level, err))
+			}
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return miss, ctxErr
+			}
+			continue
+		}
+
+		if i > 0 && mc.enablePropagation {
+			mc.propagateToEarlierCaches(ctx, key, entry, i)
+		}
+
+		if entry.IsNegative() {
+			return &models.CacheResult{Entry: entry, Level: level}, cache.ErrCacheMiss
+		}
+
+		return &models.CacheResult{
+			Entry: entry,
+			Found: true,
+			Level: level,
+		}, nil
+	}
+
+	if len(errs) > 0 {
+		return miss, errors.Join(errs...)
+	}
+
+	return miss, cache.ErrCacheMiss
+}
+
+// propagateToEarlierCaches propagates a cache entry to earlier caches with adjusted TTL
+func (mc *MultiCacheV2) propagateToEarlierCaches(ctx context.Context, key string, entry *models.CacheEntry, foundAtIndex int) {
+	now := mc.clock.Now()
+	if entry == nil || entry.IsExpiredAt(now) {
+		return
+	}
+	remainingTTL := entry.RemainingTTLAt(now)
+
+	// Only propagate if there's meaningful time left
+	if remainingTTL.Fresh <= 0 && remainingTTL.Stale <= 0 {
+		return
+	}
+
+	cacheType := cache.RequestLabelsFromContext(ctx).CacheType
+	for i := 0; i < foundAtIndex; i++ {
+		if !mc.accepts(i, cacheType, entrySize(entry)) {
+			continue
+		}
+
+		spanCtx, span := mc.startLevelSpan(ctx, "propagate", i)
+		var err error
+		if entry.IsNegative() {
+			err = setNegativeV2(spanCtx, mc.caches[i], key, entry.Error, remainingTTL)
+		} else {
+			err = mc.caches[i].Set(spanCtx, key, entry.Data, remainingTTL)
+		}
+		endLevelSpan(span, err)
+		if err != nil {
+			mc.logger.Warn("Failed to propagate cache entry", "key", key, "level", models.CacheLevelFromIndex(i), "error", err)
+		}
+	}
+}
+
+// startLevelSpan starts a cache.<operation> span for the level at index i
+func (mc *MultiCacheV2) startLevelSpan(ctx context.Context, operation string, i int) (context.Context, trace.Span) {
+	return mc.tracer.Start(ctx, "cache."+operation, trace.WithAttributes(
+		attribute.String("cache.level", strings.ToLower(models.CacheLevelFromIndex(i).String())),
+	))
+}
+
+// endLevelSpan marks span as failed if err is a level error rather than a miss, then ends it
+func endLevelSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, cache.ErrCacheMiss) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,323 @@
+package multi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/mock/gomock"
+
+	"github.com/status-im/proxy-common/cache"
+	"github.com/status-im/proxy-common/cache/mock"
+	"github.com/status-im/proxy-common/models"
+)
+
+func newTestEntry() *models.CacheEntry {
+	return &models.CacheEntry{
+		Data:      []byte("test-value"),
+		CreatedAt: time.Now().Unix(),
+		StaleAt:   time.Now().Unix() + 60,
+		ExpiresAt: time.Now().Unix() + 120,
+	}
+}
+
+func TestMultiCacheV2_GetWithLevel_SecondCacheHit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache1 := mock.NewMockCacheV2(ctrl)
+	cache2 := mock.NewMockCacheV2(ctrl)
+	multiCache := NewMultiCacheV2([]cache.CacheV2{cache1, cache2}, true)
+
+	expectedEntry := newTestEntry()
+	cache1.EXPECT().Get(gomock.Any(), "test-key").Return(nil, cache.ErrCacheMiss)
+	cache2.EXPECT().Get(gomock.Any(), "test-key").Return(expectedEntry, nil)
+	// Expect propagation to cache1
+	cache1.EXPECT().Set(gomock.Any(), "test-key", expectedEntry.Data, gomock.Any()).Return(nil)
+
+	result, err := multiCache.GetWithLevel(context.Background(), "test-key")
+
+	assert.NoError(t, err)
+	assert.True(t, result.Found)
+	assert.Equal(t, models.CacheLevelL2, result.Level)
+	assert.Equal(t, expectedEntry, result.Entry)
+}
+
+func TestMultiCacheV2_Get_AllCachesMiss(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache1 := mock.NewMockCacheV2(ctrl)
+	cache2 := mock.NewMockCacheV2(ctrl)
+	multiCache := NewMultiCacheV2([]cache.CacheV2{cache1, cache2}, true)
+
+	cache1.EXPECT().GetStale(gomock.Any(), "test-key").Return(nil, cache.ErrCacheMiss)
+	cache2.EXPECT().GetStale(gomock.Any(), "test-key").Return(nil, cache.ErrCacheMiss)
+
+	entry, err := multiCache.GetStale(context.Background(), "test-key")
+
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	assert.Nil(t, entry)
+}
+
+func TestMultiCacheV2_Get_LevelErrorIsNotAMiss(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache1 := mock.NewMockCacheV2(ctrl)
+	cache2 := mock.NewMockCacheV2(ctrl)
+	multiCache := NewMultiCacheV2([]cache.CacheV2{cache1, cache2}, true)
+
+	l2Err := errors.New("connection refused")
+	cache1.EXPECT().Get(gomock.Any(), "test-key").Return(nil, cache.ErrCacheMiss)
+	cache2.EXPECT().Get(gomock.Any(), "test-key").Return(nil, l2Err)
+
+	result, err := multiCache.GetWithLevel(context.Background(), "test-key")
+
+	assert.ErrorIs(t, err, l2Err)
+	assert.NotErrorIs(t, err, cache.ErrCacheMiss)
+	assert.False(t, result.Found)
+	assert.Equal(t, models.CacheLevelMiss, result.Level)
+}
+
+func TestMultiCacheV2_Get_ContextCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache1 := mock.NewMockCacheV2(ctrl)
+	cache2 := mock.NewMockCacheV2(ctrl)
+	multiCache := NewMultiCacheV2([]cache.CacheV2{cache1, cache2}, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cache1.EXPECT().Get(gomock.Any(), "test-key").DoAndReturn(func(context.Context, string) (*models.CacheEntry, error) {
+		cancel()
+		return nil, context.Canceled
+	})
+	// cache2.Get should not be called once the context is done
+
+	_, err := multiCache.Get(ctx, "test-key")
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestMultiCacheV2_Set_JoinsErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache1 := mock.NewMockCacheV2(ctrl)
+	cache2 := mock.NewMockCacheV2(ctrl)
+	multiCache := NewMultiCacheV2([]cache.CacheV2{cache1, cache2}, true)
+
+	testVal := []byte("test-value")
+	testTTL := models.TTL{Fresh: 60 * time.Second, Stale: 30 * time.Second}
+	l2Err := errors.New("write timeout")
+
+	cache1.EXPECT().Set(gomock.Any(), "test-key", testVal, testTTL).Return(nil)
+	cache2.EXPECT().Set(gomock.Any(), "test-key", testVal, testTTL).Return(l2Err)
+
+	err := multiCache.Set(context.Background(), "test-key", testVal, testTTL)
+
+	assert.ErrorIs(t, err, l2Err)
+	assert.Contains(t, err.Error(), "L2")
+}
+
+func TestMultiCacheV2_SetNegative(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	negative := mock.NewMockNegativeCacheV2(ctrl)
+	plain := mock.NewMockCacheV2(ctrl)
+	multiCache := NewMultiCacheV2([]cache.CacheV2{negative, plain}, true).(*MultiCacheV2)
+
+	ttl := models.TTL{Fresh: 5 * time.Second}
+	negative.EXPECT().SetNegative(gomock.Any(), "test-key", []byte("not found"), ttl).Return(nil)
+	plain.EXPECT().Delete(gomock.Any(), "test-key").Return(errors.New("connection refused"))
+
+	err := multiCache.SetNegative(context.Background(), "test-key", []byte("not found"), ttl)
+
+	assert.ErrorContains(t, err, "L2: connection refused")
+
+	t.Run("propagates negative entries", func(t *testing.T) {
+		entry := models.NewNegativeCacheEntry(nil, models.TTL{Fresh: time.Minute}, time.Now())
+		negative.EXPECT().GetEntry(gomock.Any(), "test-key").Return(nil, cache.ErrCacheMiss)
+		plain.EXPECT().Get(gomock.Any(), "test-key").Return(entry, nil)
+		negative.EXPECT().SetNegative(gomock.Any(), "test-key", []byte(nil), gomock.Any()).Return(nil)
+
+		result, err := multiCache.GetWithLevel(context.Background(), "test-key")
+
+		assert.ErrorIs(t, err, cache.ErrCacheMiss)
+		assert.False(t, result.Found)
+		assert.True(t, result.IsNegative())
+
+		negative.EXPECT().GetEntry(gomock.Any(), "test-key").Return(entry, nil)
+		got, err := multiCache.GetEntry(context.Background(), "test-key")
+		require.NoError(t, err)
+		assert.Equal(t, entry, got)
+	})
+}
+
+func TestMultiCacheV2_Delete_AllCaches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache1 := mock.NewMockCacheV2(ctrl)
+	cache2 := mock.NewMockCacheV2(ctrl)
+	multiCache := NewMultiCacheV2([]cache.CacheV2{cache1, cache2}, true)
+
+	cache1.EXPECT().Delete(gomock.Any(), "test-key").Return(nil)
+	cache2.EXPECT().Delete(gomock.Any(), "test-key").Return(nil)
+
+	assert.NoError(t, multiCache.Delete(context.Background(), "test-key"))
+}
+
+func TestMultiCacheV2_NoCaches(t *testing.T) {
+	multiCache := NewMultiCacheV2([]cache.CacheV2{}, true)
+
+	_, err := multiCache.Get(context.Background(), "test-key")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	assert.NoError(t, multiCache.Set(context.Background(), "test-key", []byte("v"), models.TTL{}))
+	assert.NoError(t, multiCache.Delete(context.Background(), "test-key"))
+}
+
+func TestMultiCacheV2_Tracing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := provider.Tracer("test")
+
+	cache1 := mock.NewMockCacheV2(ctrl)
+	cache2 := mock.NewMockCacheV2(ctrl)
+	multiCache := NewMultiCacheV2([]cache.CacheV2{cache1, cache2}, false, WithTracerV2(tracer))
+
+	ctx, parent := tracer.Start(context.Background(), "request")
+
+	expectedEntry := newTestEntry()
+	cache1.EXPECT().Get(gomock.Any(), "test-key").Return(nil, cache.ErrCacheMiss)
+	cache2.EXPECT().Get(gomock.Any(), "test-key").Return(expectedEntry, nil)
+	_, err := multiCache.GetWithLevel(ctx, "test-key")
+	require.NoError(t, err)
+
+	cache1.EXPECT().Set(gomock.Any(), "test-key", []byte("v"), gomock.Any()).Return(nil)
+	cache2.EXPECT().Set(gomock.Any(), "test-key", []byte("v"), gomock.Any()).Return(errors.New("connection refused"))
+	assert.Error(t, multiCache.Set(ctx, "test-key", []byte("v"), models.TTL{Fresh: time.Minute}))
+
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 5)
+
+	tests := []struct {
+		name   string
+		level  string
+		hit    *bool
+		status codes.Code
+	}{
+		{name: "cache.get", level: "l1", hit: boolPtr(false), status: codes.Unset},
+		{name: "cache.get", level: "l2", hit: boolPtr(true), status: codes.Unset},
+		{name: "cache.set", level: "l1", status: codes.Unset},
+		{name: "cache.set", level: "l2", status: codes.Error},
+	}
+
+	for i, tt := range tests {
+		span := spans[i]
+		assert.Equal(t, tt.name, span.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Contains(t, span.Attributes(), attribute.String("cache.level", tt.level))
+		if tt.hit != nil {
+			assert.Contains(t, span.Attributes(), attribute.Bool("cache.hit", *tt.hit))
+		}
+		assert.Equal(t, tt.status, span.Status().Code)
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// unhealthyCacheV2 is a mock cache level that also reports its availability
+type unhealthyCacheV2 struct {
+	*mock.MockCacheV2
+	*mock.MockHealthChecker
+}
+
+func TestMultiCacheV2_SkipsUnavailableLevel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache1 := mock.NewMockCacheV2(ctrl)
+	cache2 := unhealthyCacheV2{mock.NewMockCacheV2(ctrl), mock.NewMockHealthChecker(ctrl)}
+	cache2.MockHealthChecker.EXPECT().Available().Return(false).AnyTimes()
+	multiCache := NewMultiCacheV2([]cache.CacheV2{cache1, cache2}, true)
+
+	// The unavailable level is neither queried nor reported as failing
+	cache1.EXPECT().Get(gomock.Any(), "test-key").Return(nil, cache.ErrCacheMiss)
+	_, err := multiCache.Get(context.Background(), "test-key")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+
+	cache1.EXPECT().Set(gomock.Any(), "test-key", []byte("v"), models.TTL{}).Return(nil)
+	assert.NoError(t, multiCache.Set(context.Background(), "test-key", []byte("v"), models.TTL{}))
+
+	// Deletes still reach it, and its failure is reported
+	errUnavailable := errors.New("unavailable")
+	cache1.EXPECT().Delete(gomock.Any(), "test-key").Return(nil)
+	cache2.MockCacheV2.EXPECT().Delete(gomock.Any(), "test-key").Return(errUnavailable)
+	assert.ErrorIs(t, multiCache.Delete(context.Background(), "test-key"), errUnavailable)
+}
+
+func TestMultiCacheV2_ReadPolicies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache1 := mock.NewMockCacheV2(ctrl)
+	cache2 := mock.NewMockCacheV2(ctrl)
+	multiCache := NewMultiCacheV2([]cache.CacheV2{cache1, cache2}, false, WithLevelPoliciesV2([]cache.LevelPolicy{
+		{SkipReadCacheTypes: []models.CacheType{models.CacheTypeMinimal}},
+	}))
+
+	minimal := cache.ContextWithRequestLabels(context.Background(), cache.RequestLabels{CacheType: models.CacheTypeMinimal})
+	cache2.EXPECT().Get(gomock.Any(), "key").Return(newTestEntry(), nil)
+	result, err := multiCache.GetWithLevel(minimal, "key")
+	require.NoError(t, err)
+	assert.Equal(t, models.CacheLevelL2, result.Level)
+}
+
+func TestMultiCacheV2_LevelPolicies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache1 := mock.NewMockCacheV2(ctrl)
+	cache2 := mock.NewMockCacheV2(ctrl)
+	multiCache := NewMultiCacheV2FromConfig(&cache.MultiCacheConfig{
+		EnablePropagation: true,
+		Levels: []cache.LevelPolicy{
+			{SkipCacheTypes: []models.CacheType{models.CacheTypePermanent}},
+			{SkipCacheTypes: []models.CacheType{models.CacheTypeMinimal}},
+		},
+	}, []cache.CacheV2{cache1, cache2})
+
+	ttl := models.TTL{Fresh: time.Minute}
+	minimal := cache.ContextWithRequestLabels(context.Background(), cache.RequestLabels{CacheType: models.CacheTypeMinimal})
+	permanent := cache.ContextWithRequestLabels(context.Background(), cache.RequestLabels{CacheType: models.CacheTypePermanent})
+
+	cache1.EXPECT().Set(gomock.Any(), "a", []byte("1"), ttl).Return(nil)
+	require.NoError(t, multiCache.Set(minimal, "a", []byte("1"), ttl))
+
+	cache2.EXPECT().Set(gomock.Any(), "b", []byte("2"), ttl).Return(nil)
+	require.NoError(t, multiCache.Set(permanent, "b", []byte("2"), ttl))
+
+	// Permanent entries found in L2 are not propagated to L1
+	cache1.EXPECT().Get(gomock.Any(), "b").Return(nil, cache.ErrCacheMiss)
+	cache2.EXPECT().Get(gomock.Any(), "b").Return(newTestEntry(), nil)
+	result, err := multiCache.GetWithLevel(permanent, "b")
+	require.NoError(t, err)
+	assert.Equal(t, models.CacheLevelL2, result.Level)
+}
@@ -1,12 +1,17 @@
 package noop
 
 import (
+	"context"
+
 	"github.com/status-im/proxy-common/cache"
 	"github.com/status-im/proxy-common/models"
 )
 
-// Ensure NoOpCache implements cache.Cache
+// Ensure NoOpCache implements cache.Cache, cache.TaggedCache, cache.BatchCache and cache.NegativeCache
 var _ cache.Cache = (*NoOpCache)(nil)
+var _ cache.TaggedCache = (*NoOpCache)(nil)
+var _ cache.BatchCache = (*NoOpCache)(nil)
+var _ cache.NegativeCache = (*NoOpCache)(nil)
 
 // NoOpCache is a no-operation cache implementation for disabled caches
 type NoOpCache struct{}
@@ -24,8 +29,77 @@ func (n *NoOpCache) GetStale(key string) (*models.CacheEntry, bool) {
 	return nil, false
 }
 
+func (n *NoOpCache) GetEntry(key string) (*models.CacheEntry, bool) {
+	return nil, false
+}
+
+func (n *NoOpCache) GetStaleEntry(key string) (*models.CacheEntry, bool) {
+	return nil, false
+}
+
 func (n *NoOpCache) Set(key string, val []byte, ttl models.TTL) {
 }
 
+func (n *NoOpCache) SetNegative(key string, errPayload []byte, ttl models.TTL) {
+}
+
 func (n *NoOpCache) Delete(key string) {
 }
+
+func (n *NoOpCache) GetMany(keys []string) map[string]*models.CacheEntry {
+	return map[string]*models.CacheEntry{}
+}
+
+func (n *NoOpCache) SetMany(items []cache.BatchItem) {
+}
+
+func (n *NoOpCache) SetWithTags(key string, val []byte, ttl models.TTL, tags ...string) {
+}
+
+func (n *NoOpCache) InvalidateTag(tag string) []string {
+	return nil
+}
+
+func (n *NoOpCache) InvalidatePrefix(prefix string) []string {
+	return nil
+}
+
+// Ensure NoOpCacheV2 implements cache.CacheV2 and cache.NegativeCacheV2
+var _ cache.CacheV2 = (*NoOpCacheV2)(nil)
+var _ cache.NegativeCacheV2 = (*NoOpCacheV2)(nil)
+
+// NoOpCacheV2 is the context-aware no-operation cache; every read is a miss
+type NoOpCacheV2 struct{}
+
+// NewNoOpCacheV2 creates a new context-aware no-operation cache instance
+func NewNoOpCacheV2() cache.CacheV2 {
+	return &NoOpCacheV2{}
+}
+
+func (n *NoOpCacheV2) Get(ctx context.Context, key string) (*models.CacheEntry, error) {
+	return nil, cache.ErrCacheMiss
+}
+
+func (n *NoOpCacheV2) GetStale(ctx context.Context, key string) (*models.CacheEntry, error) {
+	return nil, cache.ErrCacheMiss
+}
+
+func (n *NoOpCacheV2) GetEntry(ctx context.Context, key string) (*models.CacheEntry, error) {
+	return nil, cache.ErrCacheMiss
+}
+
+func (n *NoOpCacheV2) GetStaleEntry(ctx context.Context, key string) (*models.CacheEntry, error) {
+	return nil, cache.ErrCacheMiss
+}
+
+func (n *NoOpCacheV2) Set(ctx context.Context, key string, val []byte, ttl models.TTL) error {
+	return nil
+}
+
+func (n *NoOpCacheV2) SetNegative(ctx context.Context, key string, errPayload []byte, ttl models.TTL) error {
+	return nil
+}
+
+func (n *NoOpCacheV2) Delete(ctx context.Context, key string) error {
+	return nil
+}
@@ -1,9 +1,12 @@
 package noop
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/status-im/proxy-common/cache"
 	"github.com/status-im/proxy-common/models"
 )
 
@@ -185,3 +188,66 @@ func TestNoOpCache_ConcurrentAccess(t *testing.T) {
 		t.Errorf("After concurrent operations, Get() = (%v, %v), want (nil, false)", entry, found)
 	}
 }
+
+func TestNoOpCacheV2(t *testing.T) {
+	c := NewNoOpCacheV2()
+	ctx := context.Background()
+	ttl := models.TTL{Fresh: 60 * time.Second, Stale: 120 * time.Second}
+
+	if err := c.Set(ctx, "test-key", []byte("test-value"), ttl); err != nil {
+		t.Errorf("Set() error = %v, want nil", err)
+	}
+
+	entry, err := c.Get(ctx, "test-key")
+	if entry != nil || !errors.Is(err, cache.ErrCacheMiss) {
+		t.Errorf("Get() after Set() = (%v, %v), want (nil, ErrCacheMiss)", entry, err)
+	}
+
+	entry, err = c.GetStale(ctx, "test-key")
+	if entry != nil || !errors.Is(err, cache.ErrCacheMiss) {
+		t.Errorf("GetStale() after Set() = (%v, %v), want (nil, ErrCacheMiss)", entry, err)
+	}
+
+	if err := c.Delete(ctx, "test-key"); err != nil {
+		t.Errorf("Delete() error = %v, want nil", err)
+	}
+}
+
+func TestNoOpCache_Invalidation(t *testing.T) {
+	c := NewNoOpCache().(cache.TaggedCache)
+
+	c.SetWithTags("test-key", []byte("test-value"), models.TTL{Fresh: time.Minute}, "tag")
+
+	if _, found := c.Get("test-key"); found {
+		t.Errorf("Get() after SetWithTags() found = true, want false")
+	}
+	if keys := c.InvalidateTag("tag"); keys != nil {
+		t.Errorf("InvalidateTag() = %v, want nil", keys)
+	}
+	if keys := c.InvalidatePrefix("test-"); keys != nil {
+		t.Errorf("InvalidatePrefix() = %v, want nil", keys)
+	}
+}
+
+func TestNoOpCache_Batch(t *testing.T) {
+	c := NewNoOpCache().(cache.BatchCache)
+
+	c.SetMany([]cache.BatchItem{{Key: "test-key", Val: []byte("test-value"), TTL: models.TTL{Fresh: time.Minute}}})
+
+	if entries := c.GetMany([]string{"test-key"}); len(entries) != 0 {
+		t.Errorf("GetMany() = %v, want empty", entries)
+	}
+}
+
+func TestNoOpCache_Negative(t *testing.T) {
+	c := NewNoOpCache().(cache.NegativeCache)
+
+	c.SetNegative("test-key", []byte("not found"), models.TTL{Fresh: time.Second})
+
+	if _, found := c.Get("test-key"); found {
+		t.Errorf("Get() after SetNegative() found = true, want false")
+	}
+	if err := NewNoOpCacheV2().(cache.NegativeCacheV2).SetNegative(context.Background(), "test-key", nil, models.TTL{}); err != nil {
+		t.Errorf("NoOpCacheV2.SetNegative() error = %v, want nil", err)
+	}
+}
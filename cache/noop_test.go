@@ -163,6 +163,66 @@ func TestNoopMetrics(t *testing.T) {
 		metrics.RecordCacheBytesRead("l2", "json", "ethereum", "mainnet", -1)
 	})
 
+	t.Run("RecordCacheCoalesced does not panic", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("RecordCacheCoalesced panicked: %v", r)
+			}
+		}()
+		metrics.RecordCacheCoalesced("load")
+		metrics.RecordCacheCoalesced("")
+	})
+
+	t.Run("RecordCacheCompression does not panic", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("RecordCacheCompression panicked: %v", r)
+			}
+		}()
+		metrics.RecordCacheCompression("l1", "zstd", 4096, 512)
+		metrics.RecordCacheCompression("", "", 0, 0)
+	})
+
+	t.Run("RecordCacheTTLAdjusted does not panic", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("RecordCacheTTLAdjusted panicked: %v", r)
+			}
+		}()
+		metrics.RecordCacheTTLAdjusted("l2", "clamped")
+		metrics.RecordCacheTTLAdjusted("", "")
+	})
+
+	t.Run("RecordCacheEviction does not panic", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("RecordCacheEviction panicked: %v", r)
+			}
+		}()
+		metrics.RecordCacheEviction("l1", "", "", "")
+		metrics.RecordCacheEviction("", "", "", "")
+	})
+
+	t.Run("RecordCacheEvictionReason does not panic", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("RecordCacheEvictionReason panicked: %v", r)
+			}
+		}()
+		metrics.RecordCacheEvictionReason("l1", EvictionNoSpace)
+		metrics.RecordCacheEvictionReason("", "")
+	})
+
+	t.Run("RecordL1CacheStats does not panic", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("RecordL1CacheStats panicked: %v", r)
+			}
+		}()
+		metrics.RecordL1CacheStats(1, 2)
+		metrics.RecordL1CacheStats(0, 0)
+	})
+
 	t.Run("TimeCacheOperation returns callable function", func(t *testing.T) {
 		defer func() {
 			if r := recover(); r != nil {
@@ -262,3 +322,35 @@ func TestNoopImplementsInterfaces(t *testing.T) {
 		done()
 	})
 }
+
+// baseMetrics implements only MetricsRecorder
+type baseMetrics struct {
+	MetricsRecorder
+}
+
+// ttlMetrics also implements TTLMetricsRecorder
+type ttlMetrics struct {
+	baseMetrics
+	adjusted int
+}
+
+func (m *ttlMetrics) RecordCacheTTLAdjusted(level, reason string) {
+	m.adjusted++
+}
+
+func TestOptionalMetrics(t *testing.T) {
+	t.Run("returns the recorder if it implements the interface", func(t *testing.T) {
+		m := &ttlMetrics{}
+		OptionalMetrics[TTLMetricsRecorder](m).RecordCacheTTLAdjusted("l2", "clamped")
+		if m.adjusted != 1 {
+			t.Errorf("expected 1 adjustment, got %d", m.adjusted)
+		}
+	})
+
+	t.Run("falls back to NoopMetrics", func(t *testing.T) {
+		r := OptionalMetrics[WriteBehindMetricsRecorder](baseMetrics{})
+		if _, ok := r.(NoopMetrics); !ok {
+			t.Errorf("expected NoopMetrics, got %T", r)
+		}
+	})
+}
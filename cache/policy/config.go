@@ -0,0 +1,222 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/status-im/proxy-common/models"
+)
+
+// Block kinds accepted by ParamMatcher.Block besides the individual block tags
+const (
+	BlockNumber = "number" // an explicit block number, e.g. "0x10d4f"
+	BlockHash   = "hash"   // a block hash, e.g. "0xb3b2...", or an EIP-1898 {"blockHash": ...} object
+	BlockTag    = "tag"    // any of the named block tags
+)
+
+// blockTags are the named blocks defined by the Ethereum JSON-RPC spec
+var blockTags = map[string]bool{
+	"latest":    true,
+	"pending":   true,
+	"earliest":  true,
+	"safe":      true,
+	"finalized": true,
+}
+
+// Config is the YAML representation of a TTL policy
+type Config struct {
+	// Defaults holds the TTL of each cache type, overriding DefaultTTLs per type
+	Defaults map[models.CacheType]TTLConfig `yaml:"defaults" json:"defaults"`
+	// DefaultCacheType applies to requests no rule matches (default none)
+	DefaultCacheType models.CacheType `yaml:"default_cache_type" json:"default_cache_type"`
+	// NegativeTTL is how long "not found" and deterministic error responses are cached,
+	// overriding DefaultNegativeTTL
+	NegativeTTL *TTLConfig `yaml:"negative_ttl" json:"negative_ttl"`
+	Rules       []Rule     `yaml:"rules" json:"rules"`
+}
+
+// TTLConfig is the YAML representation of models.TTL
+type TTLConfig struct {
+	Fresh time.Duration `yaml:"fresh" json:"fresh"`
+	Stale time.Duration `yaml:"stale" json:"stale"`
+}
+
+// TTL converts the config to a models.TTL
+func (t TTLConfig) TTL() models.TTL {
+	return models.TTL{Fresh: t.Fresh, Stale: t.Stale}
+}
+
+// Rule maps requests to a cache type. Empty Chain, Network and Methods match anything;
+// Methods may contain path.Match globs such as "eth_get*".
+type Rule struct {
+	Name        string           `yaml:"name" json:"name"`
+	Chain       string           `yaml:"chain" json:"chain"`
+	Network     string           `yaml:"network" json:"network"`
+	Methods     []string         `yaml:"methods" json:"methods"`
+	Params      []ParamMatcher   `yaml:"params" json:"params"`
+	CacheType   models.CacheType `yaml:"cache_type" json:"cache_type"`
+	TTL         *TTLConfig       `yaml:"ttl" json:"ttl"`                   // overrides the cache type's TTL
+	NegativeTTL *TTLConfig       `yaml:"negative_ttl" json:"negative_ttl"` // overrides Config.NegativeTTL
+}
+
+// ParamMatcher matches one positional JSON-RPC parameter, or a field of it when Field
+// is set. A missing block parameter is treated as "latest", the JSON-RPC default.
+type ParamMatcher struct {
+	Index   int    `yaml:"index" json:"index"`
+	Field   string `yaml:"field" json:"field"`     // e.g. "toBlock" in an eth_getLogs filter
+	Block   string `yaml:"block" json:"block"`     // number, hash, tag or a specific tag such as latest
+	Pattern string `yaml:"pattern" json:"pattern"` // regular expression matched against the value
+}
+
+// DefaultTTLs are the TTLs used for cache types missing from Config.Defaults
+var DefaultTTLs = map[models.CacheType]models.TTL{
+	models.CacheTypePermanent: {Fresh: 24 * time.Hour},
+	models.CacheTypeShort:     {Fresh: 15 * time.Second, Stale: time.Minute},
+	models.CacheTypeMinimal:   {Fresh: 5 * time.Second, Stale: 30 * time.Second},
+	models.CacheTypeNone:      {},
+}
+
+// DefaultNegativeTTL is the TTL of negative entries when Config.NegativeTTL is not set.
+// It is kept short so a transaction or block that appears later is picked up quickly.
+var DefaultNegativeTTL = models.TTL{Fresh: 5 * time.Second}
+
+// ApplyDefaults fills in the default cache type
+func (c *Config) ApplyDefaults() {
+	if c.DefaultCacheType == "" {
+		c.DefaultCacheType = models.CacheTypeNone
+	}
+}
+
+// Validate checks the config for invalid cache types, TTLs, globs and matchers
+func (c *Config) Validate() error {
+	if err := validateCacheType(c.DefaultCacheType); err != nil {
+		return fmt.Errorf("default_cache_type: %w", err)
+	}
+
+	for cacheType, ttl := range c.Defaults {
+		if err := validateCacheType(cacheType); err != nil {
+			return fmt.Errorf("defaults: %w", err)
+		}
+		if err := validateTTL(ttl); err != nil {
+			return fmt.Errorf("defaults.%s: %w", cacheType, err)
+		}
+	}
+
+	if c.NegativeTTL != nil {
+		if err := validateTTL(*c.NegativeTTL); err != nil {
+			return fmt.Errorf("negative_ttl: %w", err)
+		}
+	}
+
+	for i, rule := range c.Rules {
+		if err := rule.validate(); err != nil {
+			name := rule.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i)
+			}
+			return fmt.Errorf("rule %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Rule) validate() error {
+	if r.CacheType == "" {
+		return fmt.Errorf("cache_type is required")
+	}
+	if err := validateCacheType(r.CacheType); err != nil {
+		return err
+	}
+
+	for _, method := range r.Methods {
+		if _, err := path.Match(method, ""); err != nil {
+			return fmt.Errorf("invalid method pattern '%s': %w", method, err)
+		}
+	}
+
+	for _, param := range r.Params {
+		if err := param.validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.TTL != nil {
+		if err := validateTTL(*r.TTL); err != nil {
+			return err
+		}
+	}
+	if r.NegativeTTL != nil {
+		if err := validateTTL(*r.NegativeTTL); err != nil {
+			return fmt.Errorf("negative_ttl: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (p *ParamMatcher) validate() error {
+	if p.Index < 0 {
+		return fmt.Errorf("param index must be non-negative, got %d", p.Index)
+	}
+	if p.Block == "" && p.Pattern == "" {
+		return fmt.Errorf("param %d: block or pattern is required", p.Index)
+	}
+
+	switch p.Block {
+	case "", BlockNumber, BlockHash, BlockTag:
+	default:
+		if !blockTags[p.Block] {
+			return fmt.Errorf("param %d: invalid block '%s': must be number, hash, tag or a block tag", p.Index, p.Block)
+		}
+	}
+
+	if p.Pattern != "" {
+		if _, err := regexp.Compile(p.Pattern); err != nil {
+			return fmt.Errorf("param %d: invalid pattern: %w", p.Index, err)
+		}
+	}
+
+	return nil
+}
+
+func validateCacheType(cacheType models.CacheType) error {
+	switch cacheType {
+	case models.CacheTypePermanent, models.CacheTypeShort, models.CacheTypeMinimal, models.CacheTypeNone:
+		return nil
+	default:
+		return fmt.Errorf("invalid cache type '%s'", cacheType)
+	}
+}
+
+func validateTTL(ttl TTLConfig) error {
+	if ttl.Fresh < 0 || ttl.Stale < 0 {
+		return fmt.Errorf("ttl must be non-negative")
+	}
+	return nil
+}
+
+// Parse decodes a YAML policy and builds an Engine from it
+func Parse(data []byte) (*Engine, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse policy: %w", err)
+	}
+
+	return New(&cfg)
+}
+
+// LoadFromFile reads a YAML policy file and builds an Engine from it
+func LoadFromFile(path string) (*Engine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	return Parse(data)
+}
@@ -0,0 +1,153 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/proxy-common/models"
+)
+
+const testPolicy = `
+defaults:
+  short:
+    fresh: 10s
+    stale: 1m
+default_cache_type: minimal
+rules:
+  - name: historical-blocks
+    methods: [eth_getBlockByNumber]
+    params:
+      - index: 0
+        block: number
+    cache_type: permanent
+  - name: polygon-logs
+    chain: polygon
+    methods: [eth_getLogs]
+    cache_type: short
+    ttl:
+      fresh: 2s
+      stale: 10s
+`
+
+func TestParse(t *testing.T) {
+	engine, err := Parse([]byte(testPolicy))
+	require.NoError(t, err)
+
+	decision := engine.Resolve(Request{Chain: "polygon", Method: "eth_getLogs"})
+	assert.Equal(t, "polygon-logs", decision.Rule)
+	assert.Equal(t, models.TTL{Fresh: 2 * time.Second, Stale: 10 * time.Second}, decision.TTL)
+
+	decision = engine.Resolve(Request{Method: "eth_chainId"})
+	assert.Equal(t, models.CacheTypeMinimal, decision.Info.CacheType)
+	assert.Equal(t, DefaultTTLs[models.CacheTypeMinimal], decision.TTL)
+}
+
+func TestLoadFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testPolicy), 0o600))
+
+	engine, err := LoadFromFile(path)
+
+	require.NoError(t, err)
+	assert.Len(t, engine.rules, 2)
+
+	_, err = LoadFromFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestParse_InvalidCacheType(t *testing.T) {
+	_, err := Parse([]byte("rules:\n  - methods: [eth_call]\n    cache_type: forever\n"))
+
+	assert.ErrorContains(t, err, "invalid cache type")
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name: "valid",
+			cfg: Config{Rules: []Rule{{
+				Methods:   []string{"eth_get*"},
+				Params:    []ParamMatcher{{Index: 0, Block: "finalized"}, {Index: 1, Pattern: "^true$"}},
+				CacheType: models.CacheTypeShort,
+			}}},
+		},
+		{
+			name:    "missing cache type",
+			cfg:     Config{Rules: []Rule{{Name: "no-type", Methods: []string{"eth_call"}}}},
+			wantErr: "rule no-type: cache_type is required",
+		},
+		{
+			name:    "invalid default cache type",
+			cfg:     Config{DefaultCacheType: "forever"},
+			wantErr: "default_cache_type",
+		},
+		{
+			name:    "negative default ttl",
+			cfg:     Config{Defaults: map[models.CacheType]TTLConfig{models.CacheTypeShort: {Fresh: -time.Second}}},
+			wantErr: "defaults.short",
+		},
+		{
+			name:    "invalid method glob",
+			cfg:     Config{Rules: []Rule{{Methods: []string{"eth_[call"}, CacheType: models.CacheTypeShort}}},
+			wantErr: "rule #0: invalid method pattern",
+		},
+		{
+			name:    "invalid block kind",
+			cfg:     Config{Rules: []Rule{{Params: []ParamMatcher{{Block: "newest"}}, CacheType: models.CacheTypeShort}}},
+			wantErr: "invalid block 'newest'",
+		},
+		{
+			name:    "invalid pattern",
+			cfg:     Config{Rules: []Rule{{Params: []ParamMatcher{{Pattern: "("}}, CacheType: models.CacheTypeShort}}},
+			wantErr: "invalid pattern",
+		},
+		{
+			name:    "empty matcher",
+			cfg:     Config{Rules: []Rule{{Params: []ParamMatcher{{Index: 1}}, CacheType: models.CacheTypeShort}}},
+			wantErr: "block or pattern is required",
+		},
+		{
+			name:    "negative param index",
+			cfg:     Config{Rules: []Rule{{Params: []ParamMatcher{{Index: -1, Block: "latest"}}, CacheType: models.CacheTypeShort}}},
+			wantErr: "non-negative",
+		},
+		{
+			name:    "negative rule ttl",
+			cfg:     Config{Rules: []Rule{{CacheType: models.CacheTypeShort, TTL: &TTLConfig{Stale: -time.Second}}}},
+			wantErr: "ttl must be non-negative",
+		},
+		{
+			name:    "negative default negative_ttl",
+			cfg:     Config{NegativeTTL: &TTLConfig{Fresh: -time.Second}},
+			wantErr: "negative_ttl: ttl must be non-negative",
+		},
+		{
+			name:    "negative rule negative_ttl",
+			cfg:     Config{Rules: []Rule{{Name: "r", CacheType: models.CacheTypeShort, NegativeTTL: &TTLConfig{Fresh: -time.Second}}}},
+			wantErr: "rule r: negative_ttl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.ApplyDefaults()
+
+			err := tt.cfg.Validate()
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
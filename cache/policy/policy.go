@@ -0,0 +1,316 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/status-im/proxy-common/internal/hexutil"
+	"github.com/status-im/proxy-common/models"
+)
+
+// Request identifies a JSON-RPC call for policy resolution
+type Request struct {
+	Chain   string
+	Network string
+	Method  string
+	Params  json.RawMessage // positional params array; other shapes match no param matchers
+}
+
+// Decision is the caching outcome for a request
+type Decision struct {
+	Info        models.CacheInfo
+	TTL         models.TTL
+	NegativeTTL models.TTL // TTL of "not found" and deterministic error responses
+	Rule        string     // name of the matching rule, empty when the default applied
+}
+
+// Cacheable reports whether the response should be cached at all
+func (d Decision) Cacheable() bool {
+	return d.Info.CacheType != models.CacheTypeNone && d.TTL.Fresh > 0
+}
+
+// NegativeCacheable reports whether a "not found" or deterministic error response
+// should be cached as a negative entry
+func (d Decision) NegativeCacheable() bool {
+	return d.Info.CacheType != models.CacheTypeNone && d.NegativeTTL.Fresh > 0
+}
+
+// Engine resolves requests to cache decisions.
+//
+// When several rules match, the most specific one wins: an exact method beats a method
+// glob, which beats no method; then more param matchers win; then rules naming both
+// chain and network beat rules naming one, which beat rules naming neither. Remaining
+// ties go to the rule listed first.
+type Engine struct {
+	rules            []compiledRule
+	ttls             map[models.CacheType]models.TTL
+	negativeTTL      models.TTL
+	defaultCacheType models.CacheType
+}
+
+type compiledRule struct {
+	Rule
+	params      []compiledParam
+	specificity [3]int
+}
+
+type compiledParam struct {
+	ParamMatcher
+	pattern *regexp.Regexp
+}
+
+// New validates cfg and builds an Engine from it
+func New(cfg *Config) (*Engine, error) {
+	cfg.ApplyDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	e := &Engine{
+		ttls:             make(map[models.CacheType]models.TTL, len(DefaultTTLs)),
+		negativeTTL:      DefaultNegativeTTL,
+		defaultCacheType: cfg.DefaultCacheType,
+	}
+	if cfg.NegativeTTL != nil {
+		e.negativeTTL = cfg.NegativeTTL.TTL()
+	}
+	for cacheType, ttl := range DefaultTTLs {
+		e.ttls[cacheType] = ttl
+	}
+	for cacheType, ttl := range cfg.Defaults {
+		e.ttls[cacheType] = ttl.TTL()
+	}
+
+	for _, rule := range cfg.Rules {
+		compiled := compiledRule{Rule: rule, specificity: specificity(rule)}
+		for _, param := range rule.Params {
+			cp := compiledParam{ParamMatcher: param}
+			if param.Pattern != "" {
+				cp.pattern = regexp.MustCompile(param.Pattern) // validated above
+			}
+			compiled.params = append(compiled.params, cp)
+		}
+		e.rules = append(e.rules, compiled)
+	}
+
+	sort.SliceStable(e.rules, func(i, j int) bool {
+		a, b := e.rules[i].specificity, e.rules[j].specificity
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] > b[k]
+			}
+		}
+		return false
+	})
+
+	return e, nil
+}
+
+// Resolve returns the decision of the most specific matching rule, or the default
+func (e *Engine) Resolve(req Request) Decision {
+	var params []interface{}
+	paramsDecoded := false
+
+	for _, rule := range e.rules {
+		if !rule.matchesRequest(req) {
+			continue
+		}
+
+		if len(rule.params) > 0 && !paramsDecoded {
+			params = decodeParams(req.Params)
+			paramsDecoded = true
+		}
+		if !rule.matchesParams(params) {
+			continue
+		}
+
+		ttl := e.ttls[rule.CacheType]
+		if rule.TTL != nil {
+			ttl = rule.TTL.TTL()
+		}
+		negativeTTL := e.negativeTTL
+		if rule.NegativeTTL != nil {
+			negativeTTL = rule.NegativeTTL.TTL()
+		}
+		return newDecision(rule.CacheType, ttl, negativeTTL, rule.Name)
+	}
+
+	return newDecision(e.defaultCacheType, e.ttls[e.defaultCacheType], e.negativeTTL, "")
+}
+
+func newDecision(cacheType models.CacheType, ttl, negativeTTL models.TTL, rule string) Decision {
+	if cacheType == models.CacheTypeNone {
+		ttl = models.TTL{}
+		negativeTTL = models.TTL{}
+	}
+
+	return Decision{
+		Info:        models.CacheInfo{TTL: ttl.Fresh, CacheType: cacheType},
+		TTL:         ttl,
+		NegativeTTL: negativeTTL,
+		Rule:        rule,
+	}
+}
+
+// specificity ranks a rule for precedence; see Engine
+func specificity(rule Rule) [3]int {
+	methodScore := 0
+	for _, method := range rule.Methods {
+		score := 2
+		if strings.ContainsAny(method, `*?[\`) {
+			score = 1
+		}
+		if score > methodScore {
+			methodScore = score
+		}
+	}
+
+	scopeScore := 0
+	if rule.Chain != "" {
+		scopeScore++
+	}
+	if rule.Network != "" {
+		scopeScore++
+	}
+
+	return [3]int{methodScore, len(rule.Params), scopeScore}
+}
+
+func (r *compiledRule) matchesRequest(req Request) bool {
+	if r.Chain != "" && r.Chain != req.Chain {
+		return false
+	}
+	if r.Network != "" && r.Network != req.Network {
+		return false
+	}
+	if len(r.Methods) == 0 {
+		return true
+	}
+
+	for _, method := range r.Methods {
+		if ok, _ := path.Match(method, req.Method); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *compiledRule) matchesParams(params []interface{}) bool {
+	for _, param := range r.params {
+		if !param.matches(params) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *compiledParam) matches(params []interface{}) bool {
+	var value interface{}
+	present := p.Index < len(params) && params[p.Index] != nil
+	if present {
+		value = params[p.Index]
+	}
+
+	if present && p.Field != "" {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		value, present = obj[p.Field]
+		present = present && value != nil
+	}
+
+	if p.Block != "" && !matchBlock(p.Block, value, present) {
+		return false
+	}
+
+	if p.pattern != nil {
+		if !present {
+			return false
+		}
+		if !p.pattern.MatchString(stringify(value)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchBlock reports whether a block parameter is of the wanted kind
+func matchBlock(want string, value interface{}, present bool) bool {
+	kind, tag := classifyBlock(value, present)
+
+	switch want {
+	case BlockNumber, BlockHash, BlockTag:
+		return kind == want
+	default:
+		return kind == BlockTag && tag == want
+	}
+}
+
+// classifyBlock returns the kind of a block parameter and, for tags, the tag name.
+// EIP-1898 objects are classified by their blockHash or blockNumber field.
+func classifyBlock(value interface{}, present bool) (string, string) {
+	if !present {
+		return BlockTag, "latest"
+	}
+
+	switch v := value.(type) {
+	case json.Number:
+		return BlockNumber, ""
+	case string:
+		if blockTags[v] {
+			return BlockTag, v
+		}
+		if hexutil.IsHex(v) {
+			if len(v) == 66 {
+				return BlockHash, ""
+			}
+			return BlockNumber, ""
+		}
+	case map[string]interface{}:
+		if _, ok := v["blockHash"]; ok {
+			return BlockHash, ""
+		}
+		if number, ok := v["blockNumber"]; ok {
+			return classifyBlock(number, number != nil)
+		}
+	}
+
+	return "", ""
+}
+
+func stringify(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	if n, ok := value.(json.Number); ok {
+		return n.String()
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// decodeParams decodes a positional params array, returning nil for anything else
+func decodeParams(raw json.RawMessage) []interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var params []interface{}
+	if err := decoder.Decode(&params); err != nil {
+		return nil
+	}
+	return params
+}
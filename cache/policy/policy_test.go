@@ -0,0 +1,195 @@
+package policy
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/proxy-common/models"
+)
+
+const testBlockHash = "0xb3b20624f8f0f86eb50dd04688409e5cea4bd02d700bf6e79e9384d47d6a5a35"
+
+func newTestEngine(t *testing.T, rules ...Rule) *Engine {
+	t.Helper()
+
+	engine, err := New(&Config{Rules: rules})
+	require.NoError(t, err)
+	return engine
+}
+
+func TestEngine_Precedence(t *testing.T) {
+	engine := newTestEngine(t,
+		Rule{Name: "catch-all", CacheType: models.CacheTypeMinimal},
+		Rule{Name: "chain", Chain: "ethereum", CacheType: models.CacheTypeMinimal},
+		Rule{Name: "chain-network", Chain: "ethereum", Network: "mainnet", CacheType: models.CacheTypeMinimal},
+		Rule{Name: "glob", Methods: []string{"eth_get*"}, CacheType: models.CacheTypeShort},
+		Rule{Name: "exact", Methods: []string{"eth_getBalance"}, CacheType: models.CacheTypeShort},
+		Rule{Name: "exact-chain", Chain: "ethereum", Methods: []string{"eth_getBalance"}, CacheType: models.CacheTypeShort},
+		Rule{
+			Name:      "exact-params",
+			Methods:   []string{"eth_getBalance"},
+			Params:    []ParamMatcher{{Index: 1, Block: BlockNumber}},
+			CacheType: models.CacheTypePermanent,
+		},
+		Rule{Name: "exact-duplicate", Methods: []string{"eth_getBalance"}, CacheType: models.CacheTypeNone},
+	)
+
+	tests := []struct {
+		name     string
+		req      Request
+		wantRule string
+	}{
+		{
+			name:     "params beat scope",
+			req:      Request{Chain: "ethereum", Method: "eth_getBalance", Params: json.RawMessage(`["0xabc", "0x10"]`)},
+			wantRule: "exact-params",
+		},
+		{
+			name:     "scope breaks ties between exact methods",
+			req:      Request{Chain: "ethereum", Method: "eth_getBalance", Params: json.RawMessage(`["0xabc", "latest"]`)},
+			wantRule: "exact-chain",
+		},
+		{
+			name:     "earlier rule wins a full tie",
+			req:      Request{Chain: "polygon", Method: "eth_getBalance"},
+			wantRule: "exact",
+		},
+		{
+			name:     "exact method beats glob",
+			req:      Request{Method: "eth_getBalance"},
+			wantRule: "exact",
+		},
+		{
+			name:     "glob beats chain and network",
+			req:      Request{Chain: "ethereum", Network: "mainnet", Method: "eth_getCode"},
+			wantRule: "glob",
+		},
+		{
+			name:     "chain and network beat chain",
+			req:      Request{Chain: "ethereum", Network: "mainnet", Method: "eth_chainId"},
+			wantRule: "chain-network",
+		},
+		{
+			name:     "chain beats catch-all",
+			req:      Request{Chain: "ethereum", Network: "sepolia", Method: "eth_chainId"},
+			wantRule: "chain",
+		},
+		{
+			name:     "catch-all",
+			req:      Request{Chain: "optimism", Method: "eth_chainId"},
+			wantRule: "catch-all",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantRule, engine.Resolve(tt.req).Rule)
+		})
+	}
+}
+
+func TestEngine_BlockParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		matcher ParamMatcher
+		params  string
+		want    bool
+	}{
+		{name: "number", matcher: ParamMatcher{Index: 0, Block: BlockNumber}, params: `["0x10d4f", false]`, want: true},
+		{name: "json number", matcher: ParamMatcher{Index: 0, Block: BlockNumber}, params: `[69967]`, want: true},
+		{name: "number is not a tag", matcher: ParamMatcher{Index: 0, Block: BlockTag}, params: `["0x10d4f"]`, want: false},
+		{name: "latest tag", matcher: ParamMatcher{Index: 0, Block: "latest"}, params: `["latest"]`, want: true},
+		{name: "any tag", matcher: ParamMatcher{Index: 0, Block: BlockTag}, params: `["finalized"]`, want: true},
+		{name: "other tag", matcher: ParamMatcher{Index: 0, Block: "latest"}, params: `["pending"]`, want: false},
+		{name: "missing means latest", matcher: ParamMatcher{Index: 1, Block: "latest"}, params: `["0xabc"]`, want: true},
+		{name: "null means latest", matcher: ParamMatcher{Index: 1, Block: "latest"}, params: `["0xabc", null]`, want: true},
+		{name: "hash", matcher: ParamMatcher{Index: 0, Block: BlockHash}, params: `["` + testBlockHash + `"]`, want: true},
+		{name: "hash is not a number", matcher: ParamMatcher{Index: 0, Block: BlockNumber}, params: `["` + testBlockHash + `"]`, want: false},
+		{name: "eip-1898 hash", matcher: ParamMatcher{Index: 1, Block: BlockHash}, params: `["0xabc", {"blockHash": "` + testBlockHash + `"}]`, want: true},
+		{name: "eip-1898 number", matcher: ParamMatcher{Index: 1, Block: BlockNumber}, params: `["0xabc", {"blockNumber": "0x10"}]`, want: true},
+		{name: "field", matcher: ParamMatcher{Index: 0, Field: "toBlock", Block: BlockNumber}, params: `[{"fromBlock": "0x1", "toBlock": "0x2"}]`, want: true},
+		{name: "missing field means latest", matcher: ParamMatcher{Index: 0, Field: "toBlock", Block: "latest"}, params: `[{"fromBlock": "0x1"}]`, want: true},
+		{name: "field of non-object", matcher: ParamMatcher{Index: 0, Field: "toBlock", Block: BlockNumber}, params: `["0x1"]`, want: false},
+		{name: "invalid block value", matcher: ParamMatcher{Index: 0, Block: BlockNumber}, params: `["0xzz"]`, want: false},
+		{name: "object params", matcher: ParamMatcher{Index: 0, Block: BlockNumber}, params: `{"block": "0x1"}`, want: false},
+		{name: "pattern", matcher: ParamMatcher{Index: 1, Pattern: "^true$"}, params: `["0x1", true]`, want: true},
+		{name: "pattern mismatch", matcher: ParamMatcher{Index: 1, Pattern: "^true$"}, params: `["0x1", false]`, want: false},
+		{name: "pattern on missing param", matcher: ParamMatcher{Index: 2, Pattern: ".*"}, params: `["0x1"]`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newTestEngine(t, Rule{Name: "rule", Params: []ParamMatcher{tt.matcher}, CacheType: models.CacheTypeShort})
+
+			decision := engine.Resolve(Request{Method: "eth_call", Params: json.RawMessage(tt.params)})
+
+			assert.Equal(t, tt.want, decision.Rule == "rule")
+		})
+	}
+}
+
+func TestEngine_Decision(t *testing.T) {
+	engine, err := New(&Config{
+		Defaults: map[models.CacheType]TTLConfig{
+			models.CacheTypeShort: {Fresh: 10 * time.Second, Stale: time.Minute},
+		},
+		Rules: []Rule{
+			{Name: "short", Methods: []string{"eth_blockNumber"}, CacheType: models.CacheTypeShort},
+			{Name: "override", Methods: []string{"eth_gasPrice"}, CacheType: models.CacheTypeShort, TTL: &TTLConfig{Fresh: 3 * time.Second}},
+			{Name: "none", Methods: []string{"eth_sendRawTransaction"}, CacheType: models.CacheTypeNone, TTL: &TTLConfig{Fresh: time.Hour}},
+		},
+	})
+	require.NoError(t, err)
+
+	decision := engine.Resolve(Request{Method: "eth_blockNumber"})
+	assert.Equal(t, models.CacheInfo{TTL: 10 * time.Second, CacheType: models.CacheTypeShort}, decision.Info)
+	assert.Equal(t, models.TTL{Fresh: 10 * time.Second, Stale: time.Minute}, decision.TTL)
+	assert.True(t, decision.Cacheable())
+
+	decision = engine.Resolve(Request{Method: "eth_gasPrice"})
+	assert.Equal(t, models.TTL{Fresh: 3 * time.Second}, decision.TTL)
+
+	// The none cache type never carries a TTL
+	decision = engine.Resolve(Request{Method: "eth_sendRawTransaction"})
+	assert.Equal(t, models.TTL{}, decision.TTL)
+	assert.False(t, decision.Cacheable())
+
+	decision = engine.Resolve(Request{Method: "eth_unknown"})
+	assert.Equal(t, "", decision.Rule)
+	assert.Equal(t, models.CacheTypeNone, decision.Info.CacheType)
+	assert.False(t, decision.Cacheable())
+}
+
+func TestEngine_NegativeTTL(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		engine := newTestEngine(t, Rule{Methods: []string{"eth_getTransactionByHash"}, CacheType: models.CacheTypeShort})
+
+		decision := engine.Resolve(Request{Method: "eth_getTransactionByHash"})
+		assert.Equal(t, DefaultNegativeTTL, decision.NegativeTTL)
+		assert.True(t, decision.NegativeCacheable())
+	})
+
+	engine, err := New(&Config{
+		NegativeTTL: &TTLConfig{Fresh: 2 * time.Second},
+		Rules: []Rule{
+			{Name: "tx", Methods: []string{"eth_getTransactionByHash"}, CacheType: models.CacheTypeShort},
+			{Name: "receipt", Methods: []string{"eth_getTransactionReceipt"}, CacheType: models.CacheTypeShort, NegativeTTL: &TTLConfig{Fresh: time.Second, Stale: time.Second}},
+			{Name: "disabled", Methods: []string{"eth_getBlockByHash"}, CacheType: models.CacheTypeShort, NegativeTTL: &TTLConfig{}},
+			{Name: "none", Methods: []string{"eth_sendRawTransaction"}, CacheType: models.CacheTypeNone, NegativeTTL: &TTLConfig{Fresh: time.Hour}},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, models.TTL{Fresh: 2 * time.Second}, engine.Resolve(Request{Method: "eth_getTransactionByHash"}).NegativeTTL)
+	assert.Equal(t, models.TTL{Fresh: time.Second, Stale: time.Second}, engine.Resolve(Request{Method: "eth_getTransactionReceipt"}).NegativeTTL)
+	assert.False(t, engine.Resolve(Request{Method: "eth_getBlockByHash"}).NegativeCacheable())
+
+	// The none cache type never caches negative results either
+	decision := engine.Resolve(Request{Method: "eth_sendRawTransaction"})
+	assert.Equal(t, models.TTL{}, decision.NegativeTTL)
+	assert.False(t, decision.NegativeCacheable())
+}
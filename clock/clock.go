@@ -0,0 +1,47 @@
+package clock
+
+import (
+	"time"
+)
+
+// Clock provides the current time and tickers, so time-dependent logic can be
+// tested without sleeping
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks at intervals, like time.Ticker
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Real is a Clock backed by the time package
+type Real struct{}
+
+// Ensure Real implements Clock
+var _ Clock = Real{}
+
+// Now returns the current local time
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// NewTicker returns a time.Ticker with period d
+func (Real) NewTicker(d time.Duration) Ticker {
+	return realTicker{ticker: time.NewTicker(d)}
+}
+
+// realTicker adapts time.Ticker to the Ticker interface
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+func (t realTicker) Stop() {
+	t.ticker.Stop()
+}
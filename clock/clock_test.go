@@ -0,0 +1,74 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReal(t *testing.T) {
+	before := time.Now()
+	now := Real{}.Now()
+	assert.False(t, now.Before(before))
+
+	ticker := Real{}.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	select {
+	case <-ticker.C():
+	case <-time.After(time.Second):
+		t.Fatal("expected real ticker to fire")
+	}
+}
+
+func TestMock_Advance(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	m := NewMock(start)
+	assert.Equal(t, start, m.Now())
+
+	m.Advance(1500 * time.Millisecond)
+	assert.Equal(t, start.Add(1500*time.Millisecond), m.Now())
+
+	m.Set(start)
+	assert.Equal(t, start, m.Now())
+}
+
+func TestMock_Ticker(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	m := NewMock(start)
+	ticker := m.NewTicker(time.Second)
+
+	m.Advance(999 * time.Millisecond)
+	select {
+	case <-ticker.C():
+		t.Fatal("expected no tick before the interval")
+	default:
+	}
+
+	m.Advance(time.Millisecond)
+	assert.Equal(t, start.Add(time.Second), <-ticker.C())
+
+	// Ticks for slow receivers are dropped, leaving only the first one buffered
+	m.Advance(3 * time.Second)
+	assert.Equal(t, start.Add(2*time.Second), <-ticker.C())
+	select {
+	case <-ticker.C():
+		t.Fatal("expected dropped ticks")
+	default:
+	}
+
+	ticker.Stop()
+	m.Advance(time.Hour)
+	select {
+	case <-ticker.C():
+		t.Fatal("expected no tick after stop")
+	default:
+	}
+}
+
+func TestMock_NewTicker_NonPositive(t *testing.T) {
+	assert.Panics(t, func() {
+		NewMock(time.Now()).NewTicker(0)
+	})
+}
@@ -0,0 +1,107 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Mock is a Clock whose time only moves when Set or Advance is called.
+// Like time.Ticker, its tickers buffer a single tick and drop ticks for slow receivers.
+type Mock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*mockTicker
+}
+
+// Ensure Mock implements Clock
+var _ Clock = (*Mock)(nil)
+
+// NewMock creates a Mock clock set to now
+func NewMock(now time.Time) *Mock {
+	return &Mock{now: now}
+}
+
+// Now returns the mock's current time
+func (m *Mock) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+// Advance moves the mock's time forward by d, firing any tickers that come due
+func (m *Mock) Advance(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.setLocked(m.now.Add(d))
+}
+
+// Set moves the mock's time to t, firing any tickers that come due. Moving
+// backwards does not fire tickers.
+func (m *Mock) Set(t time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.setLocked(t)
+}
+
+// NewTicker returns a ticker that fires each time the mock's time passes another
+// period d. It panics if d is not positive, like time.NewTicker.
+func (m *Mock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for Mock.NewTicker")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t := &mockTicker{
+		mock:     m,
+		c:        make(chan time.Time, 1),
+		interval: d,
+		next:     m.now.Add(d),
+	}
+	m.tickers = append(m.tickers, t)
+	return t
+}
+
+// setLocked updates the time and fires due tickers; m.mu must be held
+func (m *Mock) setLocked(t time.Time) {
+	m.now = t
+	for _, ticker := range m.tickers {
+		for !ticker.next.After(t) {
+			select {
+			case ticker.c <- ticker.next:
+			default:
+			}
+			ticker.next = ticker.next.Add(ticker.interval)
+		}
+	}
+}
+
+// removeTicker stops firing t
+func (m *Mock) removeTicker(t *mockTicker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, ticker := range m.tickers {
+		if ticker == t {
+			m.tickers = append(m.tickers[:i], m.tickers[i+1:]...)
+			return
+		}
+	}
+}
+
+// mockTicker is a Ticker driven by a Mock clock
+type mockTicker struct {
+	mock     *Mock
+	c        chan time.Time
+	interval time.Duration
+	next     time.Time
+}
+
+func (t *mockTicker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *mockTicker) Stop() {
+	t.mock.removeTicker(t)
+}
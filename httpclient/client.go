@@ -8,6 +8,10 @@ import (
 	"net/http"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	"golang.org/x/time/rate"
 )
 
@@ -19,6 +23,9 @@ type HTTPClientWithRetries struct {
 	// RateLimiter is an optional callback that returns a rate limiter for the request
 	// The callback receives the request and should return a rate limiter or nil
 	RateLimiter func(*http.Request) *rate.Limiter
+	// Tracer is an optional tracer for an http.request span per call with an
+	// http.attempt child span per attempt; spans are not recorded when nil
+	Tracer trace.Tracer
 }
 
 // NewHTTPClientWithRetries creates a new HTTP Client with retry capabilities
@@ -45,10 +52,22 @@ func (c *HTTPClientWithRetries) SetStatusHandler(handler IHttpStatusHandler) {
 	c.StatusHandler = handler
 }
 
+// SetTracer sets the tracer for this Client
+func (c *HTTPClientWithRetries) SetTracer(tracer trace.Tracer) {
+	c.Tracer = tracer
+}
+
 // ExecuteRequest executes an HTTP request with retry logic
 func (c *HTTPClientWithRetries) ExecuteRequest(req *http.Request) (*http.Response, []byte, time.Duration, error) {
 	var lastErr error
 
+	tracer := c.tracer()
+	ctx, span := tracer.Start(req.Context(), "http.request", trace.WithAttributes(
+		attribute.String("http.request.method", req.Method),
+		attribute.String("server.address", req.URL.Host),
+	))
+	defer span.End()
+
 	for attempt := 0; attempt < c.Opts.MaxRetries; attempt++ {
 		if attempt > 0 {
 			log.Printf("%s: Retry %d/%d after error: %v",
@@ -60,61 +79,89 @@ func (c *HTTPClientWithRetries) ExecuteRequest(req *http.Request) (*http.Respons
 
 			backoffDuration := CalculateBackoffWithJitter(c.Opts.BaseBackoff, attempt)
 			log.Printf("%s: Waiting %.2fs before retry", c.Opts.LogPrefix, backoffDuration.Seconds())
+			span.AddEvent("retry", trace.WithAttributes(
+				attribute.Int("http.retry.attempt", attempt),
+				attribute.Float64("http.retry.backoff_seconds", backoffDuration.Seconds()),
+			))
 			time.Sleep(backoffDuration)
 		}
 
+		attemptCtx, attemptSpan := tracer.Start(ctx, "http.attempt", trace.WithAttributes(
+			attribute.Int("http.attempt", attempt),
+		))
 		requestStart := time.Now()
 
 		if c.RateLimiter != nil {
 			limiter := c.RateLimiter(req)
 			if limiter != nil {
-				if err := limiter.Wait(req.Context()); err != nil {
+				if err := limiter.Wait(attemptCtx); err != nil {
 					lastErr = fmt.Errorf("rate limiter wait failed: %w", err)
-					if c.StatusHandler != nil {
-						c.StatusHandler.OnRequest("error")
-					}
+					c.onRequest(attemptSpan, "error", lastErr)
 					break
 				}
 			}
 		}
 
-		resp, err := c.Client.Do(req)
+		resp, err := c.Client.Do(req.WithContext(attemptCtx))
 		requestDuration := time.Since(requestStart)
 
 		if err != nil {
 			lastErr = fmt.Errorf("request failed after %.2fs: %v", requestDuration.Seconds(), err)
-			if c.StatusHandler != nil {
-				c.StatusHandler.OnRequest("error")
-			}
+			c.onRequest(attemptSpan, "error", lastErr)
 			continue
 		}
+		attemptSpan.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
 
 		responseBody, err := processResponse(resp, req, requestDuration)
 		if err != nil {
 			if isRetryableError(resp.StatusCode) {
 				lastErr = err
 				_ = resp.Body.Close()
-				if c.StatusHandler != nil {
-					c.StatusHandler.OnRequest("rate_limited")
-				}
+				c.onRequest(attemptSpan, "rate_limited", err)
 				continue
 			}
 
 			_ = resp.Body.Close()
-			if c.StatusHandler != nil {
-				c.StatusHandler.OnRequest("error")
-			}
+			c.onRequest(attemptSpan, "error", err)
+			failSpan(span, err)
 			return nil, nil, requestDuration, err
 		}
 
-		if c.StatusHandler != nil {
-			c.StatusHandler.OnRequest("success")
-		}
+		c.onRequest(attemptSpan, "success", nil)
 		return resp, responseBody, requestDuration, nil
 	}
 
-	return nil, nil, 0, fmt.Errorf("all %d attempts failed, last error: %v",
+	err := fmt.Errorf("all %d attempts failed, last error: %v",
 		c.Opts.MaxRetries, lastErr)
+	failSpan(span, err)
+	return nil, nil, 0, err
+}
+
+// onRequest reports the outcome of an attempt to the status handler and ends its span
+func (c *HTTPClientWithRetries) onRequest(span trace.Span, status string, err error) {
+	if c.StatusHandler != nil {
+		c.StatusHandler.OnRequest(status)
+	}
+
+	span.SetAttributes(attribute.String("http.outcome", status))
+	if err != nil {
+		failSpan(span, err)
+	}
+	span.End()
+}
+
+// tracer returns the configured tracer or a no-op tracer
+func (c *HTTPClientWithRetries) tracer() trace.Tracer {
+	if c.Tracer != nil {
+		return c.Tracer
+	}
+	return noop.NewTracerProvider().Tracer("")
+}
+
+// failSpan records err on span and marks it as failed
+func failSpan(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
 }
 
 // processResponse reads and processes the HTTP response
@@ -8,6 +8,10 @@ import (
 	"net/http/httptest"
 	"testing"
 	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // MockHttpStatusHandler implements IHttpStatusHandler for testing
@@ -330,3 +334,53 @@ func TestHTTPClientWithRetries_NoHandler(t *testing.T) {
 		t.Errorf("Expected body '{\"status\":\"ok\"}', got '%s'", string(body))
 	}
 }
+
+// TestHTTPClientWithRetries_Tracing tests that every attempt gets its own span under the request span
+func TestHTTPClientWithRetries_Tracing(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	opts := DefaultRetryOptions()
+	opts.BaseBackoff = 10 * time.Millisecond
+
+	client := NewHTTPClientWithRetries(opts, nil, nil)
+	client.SetTracer(provider.Tracer("test"))
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	if _, _, _, err := client.ExecuteRequest(req); err != nil {
+		t.Fatalf("Expected successful request after retry, got error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(spans))
+	}
+
+	first, second, request := spans[0], spans[1], spans[2]
+	if request.Name != "http.request" || first.Name != "http.attempt" || second.Name != "http.attempt" {
+		t.Errorf("Unexpected span names: %s, %s, %s", first.Name, second.Name, request.Name)
+	}
+	if first.Parent.SpanID() != request.SpanContext.SpanID() || second.Parent.SpanID() != request.SpanContext.SpanID() {
+		t.Errorf("Expected attempt spans to be children of the request span")
+	}
+	if first.Status.Code != codes.Error {
+		t.Errorf("Expected failed first attempt, got status %v", first.Status.Code)
+	}
+	if second.Status.Code != codes.Unset || request.Status.Code != codes.Unset {
+		t.Errorf("Expected successful second attempt and request, got %v and %v", second.Status.Code, request.Status.Code)
+	}
+	if len(request.Events) != 1 || request.Events[0].Name != "retry" {
+		t.Errorf("Expected a single retry event, got %v", request.Events)
+	}
+}
@@ -0,0 +1,26 @@
+// Package hexutil holds helpers for the 0x-prefixed hex strings used by Ethereum
+// JSON-RPC, shared by the cache key builder and the TTL policy engine
+package hexutil
+
+// IsHex reports whether s is a 0x- or 0X-prefixed string of at least one hex digit
+func IsHex(s string) bool {
+	if len(s) < 3 || s[0] != '0' || (s[1] != 'x' && s[1] != 'X') {
+		return false
+	}
+	for _, c := range s[2:] {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// TrimQuantity returns the hex quantity s without leading zero digits, so "0x0010"
+// becomes "0x10" and "0x000" becomes "0x0". s must satisfy IsHex.
+func TrimQuantity(s string) string {
+	i := 2
+	for i < len(s)-1 && s[i] == '0' {
+		i++
+	}
+	return s[:2] + s[i:]
+}
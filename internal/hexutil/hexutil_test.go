@@ -0,0 +1,29 @@
+package hexutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsHex(t *testing.T) {
+	for _, s := range []string{"0x0", "0xabcdef", "0XABCDEF", "0x0123456789"} {
+		assert.True(t, IsHex(s), s)
+	}
+	for _, s := range []string{"", "0x", "0", "x12", "0xg", "1x12", "latest"} {
+		assert.False(t, IsHex(s), s)
+	}
+}
+
+func TestTrimQuantity(t *testing.T) {
+	tests := map[string]string{
+		"0x10":   "0x10",
+		"0x0010": "0x10",
+		"0x0":    "0x0",
+		"0x000":  "0x0",
+		"0X01":   "0X1",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, TrimQuantity(in), in)
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -99,49 +100,208 @@ func CacheLevelFromIndex(index int) CacheLevel {
 	}
 }
 
-// CacheResult represents the result of a cache operation with level information
+// CacheResult represents the result of a cache operation with level information.
+// Negative entries are never reported as Found, so callers unaware of them treat them
+// as misses; Entry is still set and IsNegative reports them.
 type CacheResult struct {
 	Entry *CacheEntry `json:"entry,omitempty"`
 	Found bool        `json:"found"`
 	Level CacheLevel  `json:"level"`
 }
 
-// CacheEntry represents an entry in the cache with TTL information
+// IsNegative reports whether the result carries a negative entry, either read from a
+// cache level or just loaded from upstream
+func (r *CacheResult) IsNegative() bool {
+	return r.Entry != nil && r.Entry.IsNegative()
+}
+
+// EntryKind distinguishes cached upstream results from cached upstream failures
+type EntryKind uint8
+
+const (
+	// EntryKindPositive is a successful upstream result
+	EntryKindPositive EntryKind = 0
+	// EntryKindNegative records that upstream had no result or failed deterministically,
+	// e.g. an unknown transaction hash, so the request is not repeated until it expires
+	EntryKindNegative EntryKind = 1
+)
+
+func (k EntryKind) String() string {
+	switch k {
+	case EntryKindPositive:
+		return "positive"
+	case EntryKindNegative:
+		return "negative"
+	default:
+		return fmt.Sprintf("kind(%d)", uint8(k))
+	}
+}
+
+// CacheEntry represents an entry in the cache with TTL information.
+// ExpiresAt, StaleAt and CreatedAt are Unix seconds; the *Ms fields carry the same
+// timestamps in Unix milliseconds and are zero for entries written without them.
+// In JSON, a negative entry's kind and error are written as an object in place of
+// "data", which readers predating negative entries fail to decode.
 type CacheEntry struct {
-	Data      []byte `json:"data"`
-	ExpiresAt int64  `json:"expires_at"`
-	StaleAt   int64  `json:"stale_at"`
-	CreatedAt int64  `json:"created_at"`
+	Data        []byte    `json:"data"`
+	ExpiresAt   int64     `json:"expires_at"`
+	StaleAt     int64     `json:"stale_at"`
+	CreatedAt   int64     `json:"created_at"`
+	ExpiresAtMs int64     `json:"expires_at_ms,omitempty"`
+	StaleAtMs   int64     `json:"stale_at_ms,omitempty"`
+	CreatedAtMs int64     `json:"created_at_ms,omitempty"`
+	Kind        EntryKind `json:"-"`
+	Error       []byte    `json:"-"` // upstream error of a negative entry, nil for "not found"
+}
+
+// NewCacheEntry creates an entry for data written at now with the given TTL
+func NewCacheEntry(data []byte, ttl TTL, now time.Time) *CacheEntry {
+	entry := &CacheEntry{Data: data}
+	created := time.UnixMilli(now.UnixMilli())
+	stale := created.Add(ttl.Fresh.Truncate(time.Millisecond))
+	entry.SetTimes(created, stale, stale.Add(ttl.Stale.Truncate(time.Millisecond)))
+	return entry
+}
+
+// NewNegativeCacheEntry creates a negative entry written at now with the given TTL.
+// errPayload is the upstream error, e.g. a JSON-RPC error object, or nil for "not found".
+func NewNegativeCacheEntry(errPayload []byte, ttl TTL, now time.Time) *CacheEntry {
+	entry := NewCacheEntry(nil, ttl, now)
+	entry.Kind = EntryKindNegative
+	entry.Error = errPayload
+	return entry
+}
+
+// IsNegative reports whether the entry records an upstream miss or error
+func (ce *CacheEntry) IsNegative() bool {
+	return ce.Kind == EntryKindNegative
+}
+
+// SetTimes sets both the second and the millisecond timestamps of the entry
+func (ce *CacheEntry) SetTimes(created, stale, expires time.Time) {
+	ce.CreatedAt, ce.CreatedAtMs = created.Unix(), created.UnixMilli()
+	ce.StaleAt, ce.StaleAtMs = stale.Unix(), stale.UnixMilli()
+	ce.ExpiresAt, ce.ExpiresAtMs = expires.Unix(), expires.UnixMilli()
+}
+
+// CreatedTime returns when the entry was written
+func (ce *CacheEntry) CreatedTime() time.Time {
+	return time.UnixMilli(millis(ce.CreatedAtMs, ce.CreatedAt))
+}
+
+// StaleTime returns when the entry stops being fresh
+func (ce *CacheEntry) StaleTime() time.Time {
+	return time.UnixMilli(millis(ce.StaleAtMs, ce.StaleAt))
+}
+
+// ExpiresTime returns when the entry expires completely
+func (ce *CacheEntry) ExpiresTime() time.Time {
+	return time.UnixMilli(millis(ce.ExpiresAtMs, ce.ExpiresAt))
 }
 
 // IsExpired checks if the cache entry is completely expired
 func (ce *CacheEntry) IsExpired() bool {
-	now := time.Now().Unix()
-	return now > ce.ExpiresAt
+	return ce.IsExpiredAt(time.Now())
+}
+
+// IsExpiredAt checks if the cache entry is completely expired at now
+func (ce *CacheEntry) IsExpiredAt(now time.Time) bool {
+	return now.UnixMilli() > millis(ce.ExpiresAtMs, ce.ExpiresAt)
 }
 
 // IsFresh checks if the cache entry is still fresh
 func (ce *CacheEntry) IsFresh() bool {
-	now := time.Now().Unix()
-	return now <= ce.StaleAt
+	return ce.IsFreshAt(time.Now())
+}
+
+// IsFreshAt checks if the cache entry is fresh at now
+func (ce *CacheEntry) IsFreshAt(now time.Time) bool {
+	return now.UnixMilli() <= millis(ce.StaleAtMs, ce.StaleAt)
 }
 
 // RemainingTTL calculates the remaining TTL for this cache entry
 func (ce *CacheEntry) RemainingTTL() TTL {
-	now := time.Now().Unix()
+	return ce.RemainingTTLAt(time.Now())
+}
 
-	freshRemaining := ce.StaleAt - now
+// RemainingTTLAt calculates the remaining TTL for this cache entry at now. Once the
+// entry is stale, only the part of the stale window still ahead of now remains.
+func (ce *CacheEntry) RemainingTTLAt(now time.Time) TTL {
+	nowMs := now.UnixMilli()
+	staleAt := millis(ce.StaleAtMs, ce.StaleAt)
+	expiresAt := millis(ce.ExpiresAtMs, ce.ExpiresAt)
+
+	freshRemaining := staleAt - nowMs
 	if freshRemaining < 0 {
 		freshRemaining = 0
 	}
 
-	staleRemaining := ce.ExpiresAt - ce.StaleAt
+	staleRemaining := expiresAt - max(nowMs, staleAt)
 	if staleRemaining < 0 {
 		staleRemaining = 0
 	}
 
 	return TTL{
-		Fresh: time.Duration(freshRemaining) * time.Second,
-		Stale: time.Duration(staleRemaining) * time.Second,
+		Fresh: time.Duration(freshRemaining) * time.Millisecond,
+		Stale: time.Duration(staleRemaining) * time.Millisecond,
+	}
+}
+
+// millis returns ms if it is set and agrees with seconds, and seconds converted to
+// milliseconds otherwise, e.g. for entries built by hand with second timestamps only
+func millis(ms, seconds int64) int64 {
+	if ms != 0 && ms/1000 == seconds {
+		return ms
+	}
+	return seconds * 1000
+}
+
+// negativeEntryJSON is the JSON "data" of a negative entry
+type negativeEntryJSON struct {
+	Kind  EntryKind `json:"kind"`
+	Error []byte    `json:"error,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler for CacheEntry
+func (ce CacheEntry) MarshalJSON() ([]byte, error) {
+	type plain CacheEntry
+	if !ce.IsNegative() {
+		return json.Marshal(plain(ce))
+	}
+
+	data, err := json.Marshal(negativeEntryJSON{Kind: ce.Kind, Error: ce.Error})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		plain
+		Data json.RawMessage `json:"data"`
+	}{plain: plain(ce), Data: data})
+}
+
+// UnmarshalJSON implements json.Unmarshaler for CacheEntry
+func (ce *CacheEntry) UnmarshalJSON(data []byte) error {
+	type plain CacheEntry
+	aux := struct {
+		*plain
+		Data json.RawMessage `json:"data"`
+	}{plain: (*plain)(ce)}
+
+	*ce = CacheEntry{}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if len(aux.Data) == 0 {
+		return nil
+	}
+
+	if aux.Data[0] == '{' {
+		var neg negativeEntryJSON
+		if err := json.Unmarshal(aux.Data, &neg); err != nil {
+			return err
+		}
+		ce.Kind, ce.Error = neg.Kind, neg.Error
+		return nil
 	}
+	return json.Unmarshal(aux.Data, &ce.Data)
 }
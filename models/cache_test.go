@@ -1,7 +1,9 @@
 package models
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 )
 
 func TestCacheLevelFromIndex(t *testing.T) {
@@ -99,3 +101,172 @@ func TestCacheLevel_String(t *testing.T) {
 		})
 	}
 }
+
+func TestCacheEntry_Freshness(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	entry := NewCacheEntry([]byte("data"), TTL{Fresh: 250 * time.Millisecond, Stale: 500 * time.Millisecond}, now)
+
+	if entry.CreatedAt != 1700000000 || entry.StaleAt != 1700000000 || entry.ExpiresAt != 1700000000 {
+		t.Fatalf("unexpected second timestamps: %+v", entry)
+	}
+	if entry.CreatedAtMs != 1700000000000 || entry.StaleAtMs != 1700000000250 || entry.ExpiresAtMs != 1700000000750 {
+		t.Fatalf("unexpected millisecond timestamps: %+v", entry)
+	}
+
+	tests := []struct {
+		name        string
+		elapsed     time.Duration
+		wantFresh   bool
+		wantExpired bool
+		wantTTL     TTL
+	}{
+		{
+			name:      "fresh",
+			elapsed:   100 * time.Millisecond,
+			wantFresh: true,
+			wantTTL:   TTL{Fresh: 150 * time.Millisecond, Stale: 500 * time.Millisecond},
+		},
+		{
+			name:      "last fresh millisecond",
+			elapsed:   250 * time.Millisecond,
+			wantFresh: true,
+			wantTTL:   TTL{Stale: 500 * time.Millisecond},
+		},
+		{
+			name:    "stale",
+			elapsed: 251 * time.Millisecond,
+			wantTTL: TTL{Stale: 499 * time.Millisecond},
+		},
+		{
+			name:    "last stale millisecond",
+			elapsed: 750 * time.Millisecond,
+		},
+		{
+			name:        "expired",
+			elapsed:     751 * time.Millisecond,
+			wantExpired: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at := now.Add(tt.elapsed)
+			if got := entry.IsFreshAt(at); got != tt.wantFresh {
+				t.Errorf("IsFreshAt() = %v, want %v", got, tt.wantFresh)
+			}
+			if got := entry.IsExpiredAt(at); got != tt.wantExpired {
+				t.Errorf("IsExpiredAt() = %v, want %v", got, tt.wantExpired)
+			}
+			if got := entry.RemainingTTLAt(at); got != tt.wantTTL {
+				t.Errorf("RemainingTTLAt() = %+v, want %+v", got, tt.wantTTL)
+			}
+		})
+	}
+}
+
+func TestCacheEntry_JSON(t *testing.T) {
+	entry := NewCacheEntry([]byte("data"), TTL{Fresh: 1500 * time.Millisecond}, time.UnixMilli(1700000000123))
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	// Readers predating millisecond precision only look at the second fields
+	var legacy struct {
+		StaleAt int64 `json:"stale_at"`
+	}
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if legacy.StaleAt != 1700000001 {
+		t.Errorf("stale_at = %d, want 1700000001", legacy.StaleAt)
+	}
+
+	var decoded CacheEntry
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if string(decoded.Data) != "data" || decoded.StaleAt != entry.StaleAt || !decoded.StaleTime().Equal(entry.StaleTime()) {
+		t.Errorf("round trip = %+v, want %+v", decoded, *entry)
+	}
+}
+
+func TestCacheEntry_UnmarshalJSON_SecondPrecision(t *testing.T) {
+	var entry CacheEntry
+	err := json.Unmarshal([]byte(`{"data":"ZGF0YQ==","expires_at":1700000120,"stale_at":1700000060,"created_at":1700000000}`), &entry)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if !entry.CreatedTime().Equal(time.Unix(1700000000, 0)) || !entry.StaleTime().Equal(time.Unix(1700000060, 0)) || !entry.ExpiresTime().Equal(time.Unix(1700000120, 0)) {
+		t.Errorf("unexpected timestamps: %+v", entry)
+	}
+}
+
+func TestCacheEntry_SecondFieldsTakePrecedence(t *testing.T) {
+	entry := NewCacheEntry([]byte("data"), TTL{Fresh: time.Minute}, time.UnixMilli(1700000000500))
+
+	// Code predating the millisecond fields only updates the second fields
+	entry.StaleAt += 60
+	entry.ExpiresAt += 60
+
+	if want := time.Unix(1700000120, 0); !entry.StaleTime().Equal(want) {
+		t.Errorf("StaleTime() = %v, want %v", entry.StaleTime(), want)
+	}
+	if !entry.IsFreshAt(time.Unix(1700000100, 0)) {
+		t.Error("IsFreshAt() = false, want true")
+	}
+}
+
+func TestCacheEntry_Negative(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	entry := NewNegativeCacheEntry([]byte(`{"code":-32000,"message":"unknown transaction"}`), TTL{Fresh: 5 * time.Second}, now)
+
+	if !entry.IsNegative() || entry.Data != nil || entry.StaleAtMs != 1700000005000 {
+		t.Errorf("unexpected negative entry: %+v", entry)
+	}
+	if NewCacheEntry([]byte("data"), TTL{Fresh: time.Second}, now).IsNegative() {
+		t.Error("NewCacheEntry() created a negative entry")
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var decoded CacheEntry
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !decoded.IsNegative() || string(decoded.Error) != string(entry.Error) || decoded.Data != nil {
+		t.Errorf("round trip = %+v, want %+v", decoded, *entry)
+	}
+
+	// Readers predating negative entries must fail to decode them rather than see a hit
+	var legacy struct {
+		Data      []byte `json:"data"`
+		ExpiresAt int64  `json:"expires_at"`
+	}
+	if err := json.Unmarshal(data, &legacy); err == nil {
+		t.Errorf("legacy Unmarshal() decoded negative entry %s", data)
+	}
+
+	tests := []struct {
+		name   string
+		result *CacheResult
+		want   bool
+	}{
+		{"negative hit", &CacheResult{Entry: entry, Found: true, Level: CacheLevelL1}, true},
+		{"positive hit", &CacheResult{Entry: &CacheEntry{}, Found: true, Level: CacheLevelL1}, false},
+		{"negative load", &CacheResult{Entry: entry, Found: false, Level: CacheLevelMiss}, true},
+		{"miss", &CacheResult{Level: CacheLevelMiss}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.IsNegative(); got != tt.want {
+				t.Errorf("IsNegative() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
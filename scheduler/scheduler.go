@@ -4,24 +4,44 @@ import (
 	"context"
 	"sync"
 	"time"
+
+	"github.com/status-im/proxy-common/clock"
 )
 
 // Scheduler manages a background task that runs at regular intervals
 type Scheduler struct {
 	interval time.Duration
 	task     func()
+	clock    clock.Clock
 	cancel   context.CancelFunc
 	wg       sync.WaitGroup
 	mu       sync.Mutex
 	running  bool
 }
 
+// Option is a functional option for configuring Scheduler
+type Option func(*Scheduler)
+
+// WithClock sets the clock whose ticker drives the task
+func WithClock(c clock.Clock) Option {
+	return func(s *Scheduler) {
+		s.clock = c
+	}
+}
+
 // New creates a new Scheduler instance
-func New(interval time.Duration, task func()) *Scheduler {
-	return &Scheduler{
+func New(interval time.Duration, task func(), opts ...Option) *Scheduler {
+	s := &Scheduler{
 		interval: interval,
 		task:     task,
+		clock:    clock.Real{},
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 func (s *Scheduler) Start() {
@@ -36,15 +56,16 @@ func (s *Scheduler) Start() {
 	s.cancel = cancel
 	s.running = true
 
+	ticker := s.clock.NewTicker(s.interval)
+
 	s.wg.Add(1)
 	go func() {
 		defer s.wg.Done()
-		ticker := time.NewTicker(s.interval)
 		defer ticker.Stop()
 
 		for {
 			select {
-			case <-ticker.C:
+			case <-ticker.C():
 				s.task()
 			case <-ctx.Done():
 				return
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/status-im/proxy-common/clock"
 )
 
 func TestPeriodicTask(t *testing.T) {
@@ -62,3 +64,23 @@ func TestPeriodicTask_DoubleStart(t *testing.T) {
 
 	assert.GreaterOrEqual(t, atomic.LoadInt32(&counter), int32(1))
 }
+
+func TestPeriodicTask_WithClock(t *testing.T) {
+	var counter int32
+	mock := clock.NewMock(time.Now())
+	pt := New(time.Minute, func() {
+		atomic.AddInt32(&counter, 1)
+	}, WithClock(mock))
+
+	pt.Start()
+	defer pt.Stop()
+
+	mock.Advance(59 * time.Second)
+	assert.Never(t, func() bool { return atomic.LoadInt32(&counter) > 0 }, 50*time.Millisecond, 5*time.Millisecond)
+
+	mock.Advance(time.Second)
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&counter) == 1 }, time.Second, 5*time.Millisecond)
+
+	mock.Advance(time.Minute)
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&counter) == 2 }, time.Second, 5*time.Millisecond)
+}